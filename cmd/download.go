@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/xdagiz/xytz/internal/config"
+	"github.com/xdagiz/xytz/internal/headless"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	downloadOpts headless.DownloadOptions
+
+	downloadCmd = &cobra.Command{
+		Use:   "download <url|file>...",
+		Short: "Download videos or playlists without the TUI",
+		Long: `Download videos or playlists without the TUI.
+
+Each argument is a video or playlist URL, or a file listing one URL per
+line. Use "-" to read the list from standard input.
+
+Exits with 0 when every download succeeded, 1 when all of them failed,
+2 when only some did and 130 when the run was cancelled.`,
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			closeLog := logToFile()
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			opts := downloadOpts
+			opts.Inputs = args
+
+			code := headless.Download(ctx, opts, os.Stdin, os.Stdout)
+			stop()
+			closeLog()
+			os.Exit(code)
+		},
+	}
+)
+
+func init() {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Printf("Warning: Could not load config, using defaults: %v", err)
+		cfg = config.GetDefault()
+	}
+
+	downloadCmd.Flags().StringVarP(&downloadOpts.Quality, "quality", "Q", "", "Video quality, e.g. 1080p, 720p or best (default from config)")
+	downloadCmd.Flags().BoolVar(&downloadOpts.Audio, "audio", false, "Download audio only")
+	downloadCmd.Flags().StringVarP(&downloadOpts.OutputDir, "output", "o", "", "Directory to save downloads to (default from config)")
+	downloadCmd.Flags().BoolVar(&downloadOpts.JSON, "json", false, "Print progress as JSON lines")
+
+	downloadCmd.Flags().StringVar(&downloadOpts.CookiesFromBrowser, "cookies-from-browser", cfg.CookiesBrowser, "The name of the browser to load cookies from")
+	downloadCmd.Flags().StringVar(&downloadOpts.Cookies, "cookies", cfg.CookiesFile, "Netscape formatted file to read cookies from")
+
+	rootCmd.AddCommand(downloadCmd)
+}
@@ -18,9 +18,14 @@ import (
 var (
 	searchLimit        int
 	sortBy             string
+	uploadDate         string
+	duration           string
+	resultType         string
+	features           []string
 	query              string
 	channel            string
 	playlist           string
+	batchFile          string
 	cookiesFromBrowser string
 	cookies            string
 
@@ -50,9 +55,14 @@ func startApp() {
 	opts := &models.CLIOptions{
 		SearchLimit:        searchLimit,
 		SortBy:             sortBy,
+		UploadDate:         uploadDate,
+		Duration:           duration,
+		Type:               resultType,
+		Features:           features,
 		Query:              query,
 		Channel:            channel,
 		Playlist:           playlist,
+		BatchFile:          batchFile,
 		CookiesFromBrowser: cookiesFromBrowser,
 		Cookies:            cookies,
 	}
@@ -64,6 +74,24 @@ func startApp() {
 	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion())
 	m.Program = p
 
+	closeLog := logToFile()
+	defer closeLog()
+
+	if _, err := p.Run(); err != nil {
+		log.Fatal("unable to run the app")
+		os.Exit(1)
+	}
+
+	m.SearchManager.Cancel()
+	m.FormatsManager.Cancel()
+	m.DownloadManager.Cancel()
+
+	saveConfigOptions(m)
+}
+
+// logToFile sends the log to debug.log in the data directory, keeping it
+// off the terminal. The returned func closes the file.
+func logToFile() func() {
 	logDir := paths.GetDataDir()
 	if err := paths.EnsureDirExists(logDir); err != nil {
 		log.Printf("Warning: Could not create log directory: %v", err)
@@ -75,20 +103,10 @@ func startApp() {
 	logger, err := tea.LogToFile(logPath, "debug")
 	if err != nil {
 		log.Printf("Warning: Could not create debug log file: %v", err)
-	} else {
-		defer logger.Close()
+		return func() {}
 	}
 
-	if _, err := p.Run(); err != nil {
-		log.Fatal("unable to run the app")
-		os.Exit(1)
-	}
-
-	m.SearchManager.Cancel()
-	m.FormatsManager.Cancel()
-	m.DownloadManager.Cancel()
-
-	saveConfigOptions(m)
+	return func() { logger.Close() }
 }
 
 func Execute() {
@@ -107,12 +125,17 @@ func init() {
 	rootCmd.Flags().IntVarP(&searchLimit, "number", "n", cfg.SearchLimit, "Number of search results")
 
 	rootCmd.Flags().StringVarP(&sortBy, "sort-by", "s", cfg.SortByDefault, "Default sort option (relevance, date, views, rating)")
+	rootCmd.Flags().StringVar(&uploadDate, "upload-date", cfg.FilterUploadDate, "Only show results uploaded within (hour, today, week, month, year)")
+	rootCmd.Flags().StringVar(&duration, "duration", cfg.FilterDuration, "Only show results of a duration (short, medium, long)")
+	rootCmd.Flags().StringVar(&resultType, "type", cfg.FilterType, "Only show results of a type (video, channel, playlist, movie)")
+	rootCmd.Flags().StringSliceVar(&features, "features", cfg.FilterFeatures, "Only show results with features (4k, hd, subtitles, creative_commons, live)")
 
 	rootCmd.Flags().BoolP("help", "h", false, "Help for xytz")
 
 	rootCmd.Flags().StringVarP(&query, "query", "q", "", "Direct search with a query")
 	rootCmd.Flags().StringVarP(&channel, "channel", "c", "", "Direct channel search")
 	rootCmd.Flags().StringVarP(&playlist, "playlist", "p", "", "Direct playlist search")
+	rootCmd.Flags().StringVarP(&batchFile, "batch-file", "a", "", "Queue the URLs listed in a file, one per line")
 
 	rootCmd.Flags().StringVarP(&cookiesFromBrowser, "cookies-from-browser", "", cfg.CookiesBrowser, "The name of the browser to load cookies from")
 	rootCmd.Flags().StringVarP(&cookies, "cookies", "", cfg.CookiesFile, "Netscape formatted file to read cookies from")
@@ -133,11 +156,26 @@ func saveConfigOptions(m *app.Model) {
 			cfg.EmbedMetadata = opt.Enabled
 		case "EmbedChapters":
 			cfg.EmbedChapters = opt.Enabled
+		case "EmbedThumbnail":
+			cfg.EmbedThumbnail = opt.Enabled
+		case "LiveFromStart":
+			cfg.LiveFromStart = opt.Enabled
+		case "SponsorBlock":
+			cfg.SponsorBlockMode = config.SponsorBlockOff
+			if opt.Enabled {
+				cfg.SponsorBlockMode = opt.Mode
+			}
 		}
 	}
 
 	cfg.SortByDefault = string(m.Search.SortBy)
 
+	filters := m.Search.Filters.Filters
+	cfg.FilterUploadDate = string(filters.UploadDate)
+	cfg.FilterDuration = string(filters.Duration)
+	cfg.FilterType = string(filters.Type)
+	cfg.FilterFeatures = filters.FeatureNames()
+
 	if err := cfg.Save(); err != nil {
 		log.Printf("Failed to save config on exit: %v", err)
 	}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/xdagiz/xytz/internal/config"
+	"github.com/xdagiz/xytz/internal/headless"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	searchOpts headless.SearchOptions
+
+	searchCmd = &cobra.Command{
+		Use:   "search [query]",
+		Short: "Print search results without the TUI",
+		Long: `Print search results without the TUI, for piping into fzf and other
+scripts. Use --channel or --playlist to list their videos instead.
+
+Exits with 1 and prints the error to stderr when the search fails.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if searchOpts.Channel != "" || searchOpts.Playlist != "" {
+				return cobra.NoArgs(cmd, args)
+			}
+
+			return cobra.MinimumNArgs(1)(cmd, args)
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			closeLog := logToFile()
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			opts := searchOpts
+			opts.Query = strings.Join(args, " ")
+
+			code := headless.Search(ctx, opts, os.Stdout, os.Stderr)
+			stop()
+			closeLog()
+			os.Exit(code)
+		},
+	}
+)
+
+func init() {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Printf("Warning: Could not load config, using defaults: %v", err)
+		cfg = config.GetDefault()
+	}
+
+	searchCmd.Flags().IntVarP(&searchOpts.Number, "number", "n", cfg.SearchLimit, "Number of search results")
+	searchCmd.Flags().StringVarP(&searchOpts.SortBy, "sort-by", "s", cfg.SortByDefault, "Sort option (relevance, date, views, rating)")
+	searchCmd.Flags().StringVarP(&searchOpts.Channel, "channel", "c", "", "List a channel's videos (use @username format)")
+	searchCmd.Flags().StringVarP(&searchOpts.Playlist, "playlist", "p", "", "List a playlist's videos (use playlist ID)")
+	searchCmd.Flags().StringVarP(&searchOpts.Format, "format", "f", headless.FormatTable, "Output format ("+strings.Join(headless.SearchFormats, ", ")+")")
+
+	searchCmd.Flags().StringVar(&searchOpts.CookiesFromBrowser, "cookies-from-browser", cfg.CookiesBrowser, "The name of the browser to load cookies from")
+	searchCmd.Flags().StringVar(&searchOpts.Cookies, "cookies", cfg.CookiesFile, "Netscape formatted file to read cookies from")
+
+	rootCmd.AddCommand(searchCmd)
+}
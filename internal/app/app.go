@@ -33,6 +33,8 @@ type Model struct {
 	ToastMsg        string
 	ToastTimer      *time.Timer
 	SearchManager   *utils.SearchManager
+	LoadMoreManager *utils.SearchManager
+	FeedManager     *utils.FeedManager
 	FormatsManager  *utils.FormatsManager
 	DownloadManager *utils.DownloadManager
 	PlayerManager   *utils.PlayerManager
@@ -64,7 +66,7 @@ func (m *Model) Init() tea.Cmd {
 			m.VideoList.ChannelName = ""
 			m.VideoList.PlaylistName = ""
 			m.VideoList.PlaylistURL = ""
-			cmd = utils.PerformSearch(m.SearchManager, opts.Query, m.Search.SortBy.GetSPParam(), m.Search.SearchLimit, m.Search.CookiesFromBrowser, m.Search.Cookies)
+			cmd = utils.PerformSearch(m.SearchManager, opts.Query, m.Search.SearchParam(), m.Search.SearchLimit, m.Search.CookiesFromBrowser, m.Search.Cookies)
 		}
 
 		if opts.Playlist != "" {
@@ -77,6 +79,13 @@ func (m *Model) Init() tea.Cmd {
 			m.VideoList.PlaylistURL = utils.BuildPlaylistURL(opts.Playlist)
 			cmd = utils.PerformPlaylistSearch(m.SearchManager, m.VideoList.PlaylistURL, m.Search.SearchLimit, m.Search.CookiesFromBrowser, m.Search.Cookies)
 		}
+
+		if opts.BatchFile != "" {
+			m.State = types.StateLoading
+			m.LoadingType = "import"
+			m.CurrentQuery = opts.BatchFile
+			cmd = utils.ImportBatchFile(m.SearchManager, opts.BatchFile, m.Search.CookiesFromBrowser, m.Search.Cookies)
+		}
 	}
 
 	return tea.Batch(m.Search.Init(), m.Spinner.Tick, m.Download.Init(), m.fetchLatestVersion(), cmd)
@@ -100,6 +109,8 @@ func NewModel() *Model {
 		Download:        models.NewDownloadModel(),
 		Player:          models.NewPlayer(),
 		SearchManager:   utils.NewSearchManager(),
+		LoadMoreManager: utils.NewSearchManager(),
+		FeedManager:     utils.NewFeedManager(),
 		FormatsManager:  utils.NewFormatsManager(),
 		DownloadManager: utils.NewDownloadManager(),
 		PlayerManager:   utils.NewPlayerManager(),
@@ -120,6 +131,8 @@ func NewModelWithOptions(opts *models.CLIOptions) *Model {
 		Download:        models.NewDownloadModel(),
 		Player:          models.NewPlayer(),
 		SearchManager:   utils.NewSearchManager(),
+		LoadMoreManager: utils.NewSearchManager(),
+		FeedManager:     utils.NewFeedManager(),
 		FormatsManager:  utils.NewFormatsManager(),
 		DownloadManager: utils.NewDownloadManager(),
 		PlayerManager:   utils.NewPlayerManager(),
@@ -29,6 +29,8 @@ func setupAppTeaEnv(t *testing.T) {
 
 	origConfigDir := config.GetConfigDir
 	origUnfinishedPath := utils.GetUnfinishedFilePath
+	origArchivePath := utils.GetArchiveFilePath
+	origDownloadsPath := utils.GetDownloadsFilePath
 
 	tmpDir := t.TempDir()
 	config.GetConfigDir = func() string {
@@ -37,10 +39,18 @@ func setupAppTeaEnv(t *testing.T) {
 	utils.GetUnfinishedFilePath = func() string {
 		return filepath.Join(tmpDir, "unfinished.json")
 	}
+	utils.GetArchiveFilePath = func() string {
+		return filepath.Join(tmpDir, "archive.txt")
+	}
+	utils.GetDownloadsFilePath = func() string {
+		return filepath.Join(tmpDir, "downloads.json")
+	}
 
 	t.Cleanup(func() {
 		config.GetConfigDir = origConfigDir
 		utils.GetUnfinishedFilePath = origUnfinishedPath
+		utils.GetArchiveFilePath = origArchivePath
+		utils.GetDownloadsFilePath = origDownloadsPath
 	})
 }
 
@@ -662,6 +672,26 @@ func TestModelInit_PlaylistOptionSetsLoadingState(t *testing.T) {
 	}
 }
 
+func TestModelInit_BatchFileOptionSetsLoadingState(t *testing.T) {
+	setupAppTeaEnv(t)
+
+	m := NewModelWithOptions(&models.CLIOptions{BatchFile: "urls.txt"})
+	cmd := m.Init()
+	if cmd == nil {
+		t.Fatalf("Init() returned nil cmd")
+	}
+
+	if m.State != types.StateLoading {
+		t.Fatalf("m.State = %q, want %q", m.State, types.StateLoading)
+	}
+	if m.LoadingType != "import" {
+		t.Fatalf("m.LoadingType = %q, want import", m.LoadingType)
+	}
+	if m.CurrentQuery != "urls.txt" {
+		t.Fatalf("m.CurrentQuery = %q, want urls.txt", m.CurrentQuery)
+	}
+}
+
 func TestModelInit_OptionPrecedenceQueryOverChannel(t *testing.T) {
 	setupAppTeaEnv(t)
 
@@ -857,8 +887,7 @@ func TestAppStartResumeDownloadClearsAllState(t *testing.T) {
 
 	updated, _ := m.Update(types.StartResumeDownloadMsg{
 		URL:      "https://youtube.com/watch?v=newvideo",
-		URLs:     nil,
-		Videos:   nil,
+		Items:    nil,
 		FormatID: "best",
 		Title:    "New Video",
 	})
@@ -58,12 +58,13 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.CurrentQuery = strings.TrimSpace(msg.Query)
 		m.VideoList.IsChannelSearch = urlType == "channel"
 		m.VideoList.IsPlaylistSearch = urlType == "playlist"
+		m.VideoList.IsFeed = false
 		if urlType == "channel" {
 			m.VideoList.ChannelName = utils.ExtractChannelUsername(msg.Query)
 		}
 		m.VideoList.PlaylistName = ""
 		m.VideoList.PlaylistURL = ""
-		cmd = utils.PerformSearch(m.SearchManager, msg.Query, m.Search.SortBy.GetSPParam(), m.Search.SearchLimit, m.Search.CookiesFromBrowser, m.Search.Cookies)
+		cmd = utils.PerformSearch(m.SearchManager, msg.Query, m.Search.SearchParam(), m.Search.SearchLimit, m.Search.CookiesFromBrowser, m.Search.Cookies)
 		m.ErrMsg = ""
 		m.Search.ErrMsg = ""
 		m.Search.Input.SetValue("")
@@ -84,16 +85,78 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case types.SearchResultMsg:
 		m.LoadingType = ""
 		m.Videos = msg.Videos
-		m.VideoList.SetItems(msg.Videos)
+		m.VideoList.SetResults(msg.Videos, msg.URL, msg.Next)
 		m.VideoList.CurrentQuery = m.CurrentQuery
 		m.VideoList.ErrMsg = msg.Err
+		if m.VideoList.IsChannelSearch {
+			m.VideoList.ResetChannelTabs()
+			if msg.Err == "" {
+				m.VideoList.SetChannelTabItems(types.ChannelTabVideos, msg.Videos, msg.URL, msg.Next)
+			}
+		}
+		m.VideoList = m.VideoList.HandleResize(m.Width, m.Height)
 		m.State = types.StateVideoList
 		m.ErrMsg = msg.Err
 		return m, nil
 
+	case types.StartChannelTabMsg:
+		m.State = types.StateLoading
+		m.LoadingType = "channel_tab"
+		m.VideoList.LoadingTab = msg.Tab
+		cmd = utils.PerformChannelTabSearch(m.SearchManager, m.VideoList.ChannelName, msg.Tab, m.Search.SearchLimit, m.Search.CookiesFromBrowser, m.Search.Cookies)
+		return m, cmd
+
+	case types.ChannelTabResultMsg:
+		m.LoadingType = ""
+		m.VideoList.LoadingTab = ""
+		m.State = types.StateVideoList
+		if msg.Err != "" {
+			return m, func() tea.Msg {
+				return types.ShowToastMsg{Message: msg.Tab.DisplayName() + ": " + msg.Err}
+			}
+		}
+
+		m.VideoList.SetChannelTabItems(msg.Tab, msg.Items, msg.URL, msg.Next)
+		return m, nil
+
+	case types.LoadMoreMsg:
+		if m.State != types.StateVideoList || !m.VideoList.StartLoadMore() {
+			return m, nil
+		}
+
+		limit := m.Search.SearchLimit
+		if msg.All {
+			limit = 0
+		}
+
+		var tab types.ChannelTab
+		if m.VideoList.IsChannelSearch {
+			tab = m.VideoList.ChannelTab
+		}
+
+		cmd = utils.PerformLoadMore(m.LoadMoreManager, m.VideoList.SourceURL, tab, m.VideoList.Next, limit, m.Search.CookiesFromBrowser, m.Search.Cookies)
+		return m, cmd
+
+	case types.MoreResultsMsg:
+		if m.VideoList.AppendResults(msg) && msg.Err != "" {
+			return m, func() tea.Msg {
+				return types.ShowToastMsg{Message: "Couldn't load more: " + msg.Err}
+			}
+		}
+
+		return m, nil
+
 	case types.FormatResultMsg:
 		m.LoadingType = ""
-		m.FormatList.SetFormats(msg.VideoFormats, msg.AudioFormats, msg.ThumbnailFormats, msg.AllFormats)
+		thumbnails := msg.ThumbnailFormats
+		if m.FormatList.IsQueue {
+			thumbnails = nil
+			m.FormatList.SetSubtitles(nil)
+		} else {
+			m.FormatList.SetSubtitles(msg.SubtitleFormats)
+		}
+		m.FormatList.SetFormats(msg.VideoFormats, msg.AudioFormats, thumbnails, msg.AllFormats)
+		m.FormatList.ResetClip()
 		m.FormatList.ShowVideoInfo = !m.FormatList.IsQueue
 		if msg.VideoInfo.ID != "" {
 			m.FormatList.SelectedVideo = msg.VideoInfo
@@ -103,16 +166,49 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case types.StartDownloadMsg:
+		video := msg.SelectedVideo
+		if video.ID == "" {
+			video = m.SelectedVideo
+			if video.ID == "" {
+				video = m.FormatList.SelectedVideo
+			}
+		}
+
+		// Clips, subtitles and thumbnails aren't checked against the archive,
+		// as in doDownload.
+		wholeVideo := msg.Clip.IsZero() && !msg.Subtitles.Only && msg.Thumbnail.URL == ""
+		if !msg.ForceRedownload && wholeVideo && utils.IsArchived(msg.URL) {
+			if m.State != types.StateFormatList {
+				return m, func() tea.Msg {
+					return types.ShowToastMsg{Message: "already downloaded: " + video.Title()}
+				}
+			}
+
+			msg.ForceRedownload = true
+			m.FormatList = m.FormatList.AskConfirm("Already downloaded: "+video.Title(), msg)
+			return m, nil
+		}
+
+		outputDir := m.outputDir(video, "")
+		var warnCmd tea.Cmd
+		if !msg.SkipSpaceCheck {
+			msg.SkipSpaceCheck = true
+			required := utils.EstimateRequiredBytes(msg.EstimatedSize, msg.FormatID, msg.IsAudioTab)
+			var fits bool
+			if fits, warnCmd = m.checkDiskSpace(outputDir, required, msg); !fits {
+				return m, nil
+			}
+		}
+
 		m.State = types.StateDownload
 		m.clearDownloadProgressState()
-		if msg.SelectedVideo.ID != "" {
-			m.Download.SelectedVideo = msg.SelectedVideo
-		} else if m.SelectedVideo.ID == "" {
-			m.Download.SelectedVideo = m.FormatList.SelectedVideo
-		} else {
-			m.Download.SelectedVideo = m.SelectedVideo
-		}
+		m.Download.SelectedVideo = video
 		m.LoadingType = "download"
+		m.Download.FormatID = msg.FormatID
+		m.Download.IsAudioTab = msg.IsAudioTab
+		m.Download.Clip = msg.Clip
+		m.Download.Subtitles = msg.Subtitles
+		m.Download.Thumbnail = msg.Thumbnail
 		req := types.DownloadRequest{
 			URL:                msg.URL,
 			FormatID:           msg.FormatID,
@@ -120,98 +216,75 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			ABR:                msg.ABR,
 			Title:              m.Download.SelectedVideo.Title(),
 			Videos:             []types.VideoItem{m.Download.SelectedVideo},
+			OutputDir:          outputDir,
+			RateLimit:          m.Download.RateLimit,
+			Clip:               msg.Clip,
+			Subtitles:          msg.Subtitles,
+			Thumbnail:          msg.Thumbnail,
 			Options:            m.Search.DownloadOptions,
+			ForceRedownload:    msg.ForceRedownload,
 			CookiesFromBrowser: m.Search.CookiesFromBrowser,
 			Cookies:            m.Search.Cookies,
 		}
 		cmd = utils.StartDownload(m.DownloadManager, m.Program, req)
-		return m, cmd
+		return m, tea.Batch(cmd, warnCmd)
 
 	case types.StartResumeDownloadMsg:
 		m.State = types.StateDownload
 		m.clearDownloadProgressState()
 		m.LoadingType = "download"
-		resumeURLs := msg.URLs
-		resumeVideos := msg.Videos
-		resumeTitle := msg.Title
-		resumeFormatID := msg.FormatID
-		if len(resumeURLs) > 0 {
-			videos := resumeVideos
-			if len(videos) == 0 {
-				videos = make([]types.VideoItem, len(resumeURLs))
-				for i, u := range resumeURLs {
-					videos[i] = types.VideoItem{ID: u, VideoTitle: u}
-				}
-			}
+		options := msg.Options
+		if len(options) == 0 {
+			options = m.Search.DownloadOptions
+		}
+		cookiesFromBrowser, cookies := msg.CookiesFromBrowser, msg.Cookies
+		if cookiesFromBrowser == "" && cookies == "" {
+			cookiesFromBrowser, cookies = m.Search.CookiesFromBrowser, m.Search.Cookies
+		}
 
-			queueLabel := resumeTitle
+		if msg.Queue && len(msg.Items) > 0 {
+			queueLabel := msg.Title
 			if queueLabel == "" {
 				queueLabel = "Queued downloads"
 			}
 
-			m.Download.IsQueue = true
-			m.Download.QueueLabel = queueLabel
-			m.Download.QueueTotal = len(videos)
-			m.Download.QueueIndex = 1
-			m.Download.SelectedVideo = videos[0]
-			m.Download.QueueItems = make([]types.QueueItem, len(videos))
-			m.Download.QueueFormatID = resumeFormatID
-			m.Download.QueueIsAudioTab = false
-			m.Download.QueueABR = 0
-
-			for i, v := range videos {
-				m.Download.QueueItems[i] = types.QueueItem{
-					Index:  i + 1,
-					Video:  v,
-					URL:    v.ID,
-					Status: types.QueueStatusPending,
-				}
-			}
-
-			updateQueueUnfinished(queueLabel, resumeFormatID, m.Download.QueueTotal, pendingQueueURLs(m.Download.QueueItems), pendingQueueVideos(m.Download.QueueItems))
-
-			m.Download.QueueItems[0].Status = types.QueueStatusDownloading
-			req := types.DownloadRequest{
-				URL:                m.Download.QueueItems[0].URL,
-				URLs:               pendingQueueURLs(m.Download.QueueItems),
-				Videos:             pendingQueueVideos(m.Download.QueueItems),
-				FormatID:           resumeFormatID,
-				IsAudioTab:         false,
-				ABR:                0,
-				QueueIndex:         1,
-				QueueTotal:         m.Download.QueueTotal,
-				UnfinishedKey:      utils.QueueUnfinishedKey(queueLabel),
-				UnfinishedTitle:    queueLabel,
-				UnfinishedDesc:     fmt.Sprintf("%d items left", m.Download.QueueTotal),
-				Title:              m.Download.SelectedVideo.Title(),
-				Options:            m.Search.DownloadOptions,
-				CookiesFromBrowser: m.Search.CookiesFromBrowser,
-				Cookies:            m.Search.Cookies,
-			}
-
-			cmd = utils.StartDownload(m.DownloadManager, m.Program, req)
+			m.Download.QueueOptions = options
+			m.Download.QueueCookiesFromBrowser = cookiesFromBrowser
+			m.Download.QueueCookies = cookies
+			cmd = m.beginQueue(queueLabel, msg.FormatID, msg.IsAudioTab, msg.ABR, msg.Items)
 			return m, cmd
 		}
 
-		m.Download.SelectedVideo = types.VideoItem{VideoTitle: resumeTitle}
-		if len(resumeVideos) > 0 {
-			m.Download.SelectedVideo = resumeVideos[0]
-		} else if resumeTitle != "" {
+		m.Download.SelectedVideo = types.VideoItem{VideoTitle: msg.Title}
+		outputDir := ""
+		if len(msg.Items) > 0 {
+			m.Download.SelectedVideo = msg.Items[0].Video
+			outputDir = msg.Items[0].OutputDir
+		} else if msg.Title != "" {
 			m.Download.SelectedVideo = types.VideoItem{
 				ID:         msg.URL,
-				VideoTitle: resumeTitle,
+				VideoTitle: msg.Title,
 			}
 		}
+		m.Download.FormatID = msg.FormatID
+		m.Download.IsAudioTab = msg.IsAudioTab
+		m.Download.Clip = msg.Clip
+		m.Download.Subtitles = msg.Subtitles
 		req := types.DownloadRequest{
 			URL:                msg.URL,
-			FormatID:           resumeFormatID,
-			IsAudioTab:         false,
-			ABR:                0,
+			FormatID:           msg.FormatID,
+			IsAudioTab:         msg.IsAudioTab,
+			ABR:                msg.ABR,
 			Title:              m.Download.SelectedVideo.Title(),
 			Videos:             []types.VideoItem{m.Download.SelectedVideo},
-			Options:            m.Search.DownloadOptions,
-			CookiesFromBrowser: m.Search.CookiesFromBrowser,
-			Cookies:            m.Search.Cookies,
+			OutputDir:          outputDir,
+			RateLimit:          m.Download.RateLimit,
+			Clip:               msg.Clip,
+			Subtitles:          msg.Subtitles,
+			Options:            options,
+			ForceRedownload:    msg.ForceRedownload,
+			CookiesFromBrowser: cookiesFromBrowser,
+			Cookies:            cookies,
 		}
 		cmd = utils.StartDownload(m.DownloadManager, m.Program, req)
 		return m, cmd
@@ -219,57 +292,53 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case types.DownloadResultMsg:
 		m.LoadingType = ""
 		if m.Download.IsQueue {
-			if len(m.Download.QueueItems) >= m.Download.QueueIndex {
-				item := &m.Download.QueueItems[m.Download.QueueIndex-1]
+			index := msg.QueueIndex
+			if index <= 0 {
+				index = m.Download.QueueIndex
+			}
+
+			errMsg := ""
+			var hookCmd tea.Cmd
+			if index > 0 && index <= len(m.Download.QueueItems) {
+				item := &m.Download.QueueItems[index-1]
+				// A cancelled item was already moved on from when it was
+				// cancelled, so its late result must not finish the queue again.
+				if item.Status != types.QueueStatusDownloading {
+					return m, nil
+				}
+
 				if msg.Destination != "" {
 					item.Destination = msg.Destination
 				}
 
-				if msg.Err != "" {
+				item.Paused = false
+				if msg.NoSpace {
+					item.Status = types.QueueStatusPending
+					item.Progress = 0
+					m.Download.QueueHold = "no space left on device"
+				} else if msg.Err != "" {
 					item.Status = types.QueueStatusError
 					item.Error = msg.Err
+					errMsg = msg.Err
 				} else {
 					item.Status = types.QueueStatusComplete
+					archiveDownload(item.Video.ID)
+					recordDownload(item.Video, item.Destination, m.Download.QueueFormatID, m.Download.QueueIsAudioTab)
+					hookCmd = m.postDownloadHooks(item.Video, item.Destination, m.Download.QueueLabel, m.Download.QueueFormatID, index)
 				}
 			}
 
-			if m.Download.QueueIndex < m.Download.QueueTotal {
-				m.Download.QueueIndex++
-				next := &m.Download.QueueItems[m.Download.QueueIndex-1]
-				next.Status = types.QueueStatusDownloading
-				m.Download.SelectedVideo = next.Video
-				m.clearDownloadProgressState()
-
-				remaining := queueRemaining(m.Download.QueueItems)
-				updateQueueUnfinished(queueLabel, m.Download.QueueFormatID, remaining, pendingQueueURLs(m.Download.QueueItems), pendingQueueVideos(m.Download.QueueItems))
-
-				req := types.DownloadRequest{
-					URL:                next.URL,
-					FormatID:           m.Download.QueueFormatID,
-					IsAudioTab:         m.Download.QueueIsAudioTab,
-					ABR:                m.Download.QueueABR,
-					QueueIndex:         m.Download.QueueIndex,
-					QueueTotal:         m.Download.QueueTotal,
-					URLs:               pendingQueueURLs(m.Download.QueueItems),
-					Videos:             pendingQueueVideos(m.Download.QueueItems),
-					UnfinishedKey:      utils.QueueUnfinishedKey(m.Download.QueueLabel),
-					UnfinishedTitle:    m.Download.QueueLabel,
-					UnfinishedDesc:     fmt.Sprintf("%d items left", remaining),
-					Title:              next.Video.Title(),
-					Options:            m.Search.DownloadOptions,
-					CookiesFromBrowser: m.Search.CookiesFromBrowser,
-					Cookies:            m.Search.Cookies,
-				}
-
-				cmd = utils.StartDownload(m.DownloadManager, m.Program, req)
-				return m, cmd
+			if m.Download.Cancelled {
+				return m, hookCmd
 			}
 
-			updateQueueUnfinished(queueLabel, m.Download.QueueFormatID, 0, nil, nil)
-			m.Download.QueueError = msg.Err
-			m.Download.Completed = true
+			if m.Download.QueueHold != "" {
+				updateQueueUnfinished(m.Download, queueRemaining(m.Download.QueueItems))
+				return m, hookCmd
+			}
 
-			return m, nil
+			cmd = m.advanceQueue(index, errMsg)
+			return m, tea.Batch(cmd, hookCmd)
 		}
 
 		if msg.Err != "" {
@@ -279,22 +348,17 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		} else {
 			m.Download.Completed = true
+			if m.Download.WholeVideo() {
+				archiveDownload(m.Download.SelectedVideo.ID)
+			}
+			recordDownload(m.Download.SelectedVideo, msg.Destination, m.Download.FormatID, m.Download.IsAudioTab)
+			cmd = m.postDownloadHooks(m.Download.SelectedVideo, msg.Destination, "", m.Download.FormatID, 0)
 		}
-		return m, nil
+		return m, cmd
 
 	case types.DownloadCompleteMsg:
 		if m.Download.IsQueue {
-			urls := pendingQueueURLs(m.Download.QueueItems)
-			videos := pendingQueueVideos(m.Download.QueueItems)
-			remaining := queueRemaining(m.Download.QueueItems)
-			if remaining == 0 && len(urls) > 0 {
-				remaining = len(urls)
-			}
-			if len(urls) == 0 {
-				updateQueueUnfinished(queueLabel, m.Download.QueueFormatID, 0, nil, nil)
-			} else {
-				updateQueueUnfinished(queueLabel, m.Download.QueueFormatID, remaining, urls, videos)
-			}
+			updateQueueUnfinished(m.Download, queueRetryable(m.Download.QueueItems))
 		}
 
 		m.State = types.StateSearchInput
@@ -303,12 +367,36 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.resetDownloadState()
 		return m, nil
 
+	case types.SetRateLimitMsg:
+		m.Download.RateLimit = msg.Limit
+		if m.DownloadManager != nil {
+			for _, index := range m.DownloadManager.ActiveIndexes() {
+				if !m.Download.CanRestart(index) {
+					continue
+				}
+
+				if err := m.DownloadManager.RestartItem(index, msg.Limit); err != nil {
+					log.Printf("Failed to restart download at new rate limit: %v", err)
+				}
+			}
+		}
+
+		cmd = func() tea.Msg {
+			return types.ShowToastMsg{Message: "rate limit: " + config.RateLimitDisplay(msg.Limit)}
+		}
+		return m, cmd
+
+	case types.ResumeQueueMsg:
+		m.Download.QueueHold = ""
+		cmd = m.advanceQueue(0, "")
+		return m, cmd
+
 	case types.PauseDownloadMsg:
-		m.Download.Paused = true
+		m.Download.SetPaused(msg.QueueIndex, true)
 		return m, nil
 
 	case types.ResumeDownloadMsg:
-		m.Download.Paused = false
+		m.Download.SetPaused(msg.QueueIndex, false)
 		return m, nil
 
 	case types.CancelDownloadMsg:
@@ -317,19 +405,14 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			_ = m.DownloadManager.Cancel()
 		}
 		if m.Download.IsQueue {
-			for i := m.Download.QueueIndex - 1; i < len(m.Download.QueueItems); i++ {
+			for i := range m.Download.QueueItems {
 				if m.Download.QueueItems[i].Status == types.QueueStatusDownloading {
 					m.Download.QueueItems[i].Status = types.QueueStatusPending
+					m.Download.QueueItems[i].Paused = false
 				}
 			}
 
-			remaining := queueRemaining(m.Download.QueueItems)
-			urls := pendingQueueURLs(m.Download.QueueItems)
-			if remaining == 0 && len(urls) > 0 {
-				remaining = len(urls)
-			}
-
-			updateQueueUnfinished(queueLabel, m.Download.QueueFormatID, remaining, urls, pendingQueueVideos(m.Download.QueueItems))
+			updateQueueUnfinished(m.Download, queueRetryable(m.Download.QueueItems))
 			m.Download.Completed = true
 			return m, nil
 		}
@@ -345,82 +428,75 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case types.SkipCurrentQueueItemMsg:
-		if !m.Download.IsQueue {
+		index := m.queueItemIndex(msg.Index)
+		if index == 0 {
 			return m, nil
 		}
 
-		m.Download.QueueItems[m.Download.QueueIndex-1].Status = types.QueueStatusSkipped
+		item := &m.Download.QueueItems[index-1]
+		wasActive := item.Status == types.QueueStatusDownloading
+		item.Status = types.QueueStatusSkipped
+		item.Paused = false
 		m.Download.QueueError = ""
+		if wasActive && m.DownloadManager != nil {
+			_ = m.DownloadManager.CancelItem(index)
+		}
 
-		if m.Download.QueueIndex < m.Download.QueueTotal {
-			m.Download.QueueIndex++
-			m.Download.QueueItems[m.Download.QueueIndex-1].Status = types.QueueStatusDownloading
-			m.Download.SelectedVideo = m.Download.QueueItems[m.Download.QueueIndex-1].Video
-			m.clearDownloadProgressState()
-
-			remaining := queueRemaining(m.Download.QueueItems)
-			updateQueueUnfinished(queueLabel, m.Download.QueueFormatID, remaining, pendingQueueURLs(m.Download.QueueItems), pendingQueueVideos(m.Download.QueueItems))
-
-			req := types.DownloadRequest{
-				URL:                m.Download.QueueItems[m.Download.QueueIndex-1].URL,
-				URLs:               pendingQueueURLs(m.Download.QueueItems),
-				Videos:             pendingQueueVideos(m.Download.QueueItems),
-				FormatID:           m.Download.QueueFormatID,
-				IsAudioTab:         m.Download.QueueIsAudioTab,
-				ABR:                m.Download.QueueABR,
-				QueueIndex:         m.Download.QueueIndex,
-				QueueTotal:         m.Download.QueueTotal,
-				UnfinishedKey:      utils.QueueUnfinishedKey(m.Download.QueueLabel),
-				UnfinishedTitle:    m.Download.QueueLabel,
-				UnfinishedDesc:     fmt.Sprintf("%d items left", remaining),
-				Title:              m.Download.QueueItems[m.Download.QueueIndex-1].Video.Title(),
-				Options:            m.Search.DownloadOptions,
-				CookiesFromBrowser: m.Search.CookiesFromBrowser,
-				Cookies:            m.Search.Cookies,
-			}
+		cmd = m.advanceQueue(index, "")
+		return m, cmd
 
-			cmd = utils.StartDownload(m.DownloadManager, m.Program, req)
-			return m, cmd
+	case types.CancelQueueItemMsg:
+		index := m.queueItemIndex(msg.Index)
+		if index == 0 {
+			return m, nil
 		}
 
-		updateQueueUnfinished(queueLabel, m.Download.QueueFormatID, 0, nil, nil)
-		m.Download.Completed = true
-		return m, nil
+		item := &m.Download.QueueItems[index-1]
+		if item.Status != types.QueueStatusDownloading {
+			return m, nil
+		}
+
+		item.Status = types.QueueStatusError
+		item.Error = "Download cancelled"
+		item.Paused = false
+		if m.DownloadManager != nil {
+			_ = m.DownloadManager.CancelItem(index)
+		}
+
+		cmd = m.advanceQueue(index, item.Error)
+		return m, cmd
 
 	case types.RetryCurrentQueueItemMsg:
-		if !m.Download.IsQueue {
+		index := m.queueItemIndex(msg.Index)
+		if index == 0 {
 			return m, nil
 		}
 
-		m.Download.QueueItems[m.Download.QueueIndex-1].Status = types.QueueStatusDownloading
-		m.Download.QueueItems[m.Download.QueueIndex-1].Error = ""
+		item := &m.Download.QueueItems[index-1]
+		if item.Status == types.QueueStatusSkipped {
+			// Skipped items are usually already downloaded, so retrying
+			// one means downloading it again.
+			item.ForceRedownload = true
+		}
+		item.Status = types.QueueStatusPending
+		item.Error = ""
 		m.Download.QueueError = ""
-		m.clearDownloadProgressState()
-
-		remaining := queueRemaining(m.Download.QueueItems)
+		m.Download.Completed = false
 
-		req := types.DownloadRequest{
-			URL:                m.Download.QueueItems[m.Download.QueueIndex-1].URL,
-			URLs:               pendingQueueURLs(m.Download.QueueItems),
-			Videos:             pendingQueueVideos(m.Download.QueueItems),
-			FormatID:           m.Download.QueueFormatID,
-			IsAudioTab:         m.Download.QueueIsAudioTab,
-			ABR:                m.Download.QueueABR,
-			QueueIndex:         m.Download.QueueIndex,
-			QueueTotal:         m.Download.QueueTotal,
-			UnfinishedKey:      utils.QueueUnfinishedKey(m.Download.QueueLabel),
-			UnfinishedTitle:    m.Download.QueueLabel,
-			UnfinishedDesc:     fmt.Sprintf("%d items left", remaining),
-			Title:              m.Download.QueueItems[m.Download.QueueIndex-1].Video.Title(),
-			Options:            m.Search.DownloadOptions,
-			CookiesFromBrowser: m.Search.CookiesFromBrowser,
-			Cookies:            m.Search.Cookies,
+		if queueActive(m.Download.QueueItems) < max(m.Download.QueueLimit, 1) {
+			cmd = m.startQueueItem(index)
 		}
 
-		cmd = utils.StartDownload(m.DownloadManager, m.Program, req)
 		return m, cmd
 
 	case types.CancelSearchMsg:
+		if m.LoadingType == "channel_tab" {
+			m.State = types.StateVideoList
+			m.LoadingType = ""
+			m.VideoList.LoadingTab = ""
+			return m, nil
+		}
+
 		m.State = types.StateSearchInput
 		m.LoadingType = ""
 		m.ErrMsg = "Search cancelled"
@@ -439,12 +515,52 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.LoadingType = "channel"
 		m.VideoList.IsChannelSearch = true
 		m.VideoList.IsPlaylistSearch = false
+		m.VideoList.IsFeed = false
 		m.VideoList.ChannelName = msg.ChannelName
 		m.VideoList.PlaylistURL = ""
 		cmd = utils.PerformChannelSearch(m.SearchManager, msg.ChannelName, m.Search.SearchLimit, m.Search.CookiesFromBrowser, m.Search.Cookies)
 		m.ErrMsg = ""
 		return m, cmd
 
+	case types.StartFeedMsg:
+		subs, err := utils.LoadSubscriptions()
+		if err != nil {
+			m.ErrMsg = fmt.Sprintf("Failed to load subscriptions: %v", err)
+			return m, nil
+		}
+
+		m.State = types.StateLoading
+		m.LoadingType = "feed"
+		m.ErrMsg = ""
+		cmd = utils.FetchFeed(m.FeedManager, subs, feedVideosPerChannel, m.Search.CookiesFromBrowser, m.Search.Cookies)
+		return m, cmd
+
+	case types.FeedResultMsg:
+		m.LoadingType = ""
+		if msg.Err != "" {
+			m.State = types.StateSearchInput
+			m.ErrMsg = msg.Err
+			return m, nil
+		}
+
+		m.VideoList.IsFeed = true
+		m.VideoList.IsChannelSearch = false
+		m.VideoList.IsPlaylistSearch = false
+		m.VideoList.FeedNew = msg.New
+		m.VideoList.ErrMsg = ""
+		m.Videos = msg.Videos
+		m.VideoList.SetResults(msg.Videos, "", 0)
+		m.VideoList = m.VideoList.HandleResize(m.Width, m.Height)
+		m.State = types.StateVideoList
+		m.ErrMsg = ""
+
+		if len(msg.Failed) > 0 {
+			return m, func() tea.Msg {
+				return types.ShowToastMsg{Message: fmt.Sprintf("Couldn't load %d of %d channels: %s", len(msg.Failed), msg.Channels, msg.Failed[0])}
+			}
+		}
+		return m, nil
+
 	case types.StartPlayURLMsg:
 		m.State = types.StateLoading
 		m.LoadingType = "fetch_info"
@@ -467,13 +583,8 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.Player.URL = utils.BuildVideoURL(msg.SelectedVideo.ID)
 		}
 
-		playFormat := config.GetDefault().GetDefaultFormat()
-		if cfg, err := config.Load(); err == nil {
-			playFormat = cfg.GetDefaultFormat()
-		}
-
 		m.State = types.StateVideoPlaying
-		cmd = m.PlayerManager.PlayURL(m.Player.URL, playFormat, msg.SelectedVideo, m.Program)
+		cmd = m.PlayerManager.PlayURL(m.Player.URL, playFormat(), msg.SelectedVideo, m.Program)
 		return m, cmd
 
 	case types.StartPlaylistURLMsg:
@@ -482,13 +593,18 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.CurrentQuery = strings.TrimSpace(msg.Query)
 		m.VideoList.IsPlaylistSearch = true
 		m.VideoList.IsChannelSearch = false
+		m.VideoList.IsFeed = false
 		m.VideoList.PlaylistName = strings.TrimSpace(msg.Query)
+		if msg.Title != "" {
+			m.VideoList.PlaylistName = msg.Title
+		}
 		m.VideoList.PlaylistURL = utils.BuildPlaylistURL(msg.Query)
 		cmd = utils.PerformPlaylistSearch(m.SearchManager, msg.Query, m.Search.SearchLimit, m.Search.CookiesFromBrowser, m.Search.Cookies)
 		m.ErrMsg = ""
 		return m, cmd
 
 	case types.BackFromVideoListMsg:
+		m.cancelLoadMore()
 		m.State = types.StateSearchInput
 		m.ErrMsg = ""
 		m.clearSelections()
@@ -507,7 +623,19 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.ToastMsg = ""
 		return m, nil
 
+	case types.PlayFileMsg:
+		m.Player.URL = msg.Path
+		m.Player.Video = msg.SelectedVideo
+		cmd = m.PlayerManager.PlayURL(msg.Path, "", msg.SelectedVideo, m.Program)
+		return m, cmd
+
 	case types.PlayVideoMsg:
+		if msg.ErrMsg != "" {
+			m.ErrMsg = msg.ErrMsg
+			m.Player = models.PlayerModel{}
+			return m, nil
+		}
+
 		if m.State == types.StateVideoPlaying {
 			m.State = types.StateSearchInput
 			m.Player = models.PlayerModel{}
@@ -519,19 +647,54 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.Player.URL = utils.BuildVideoURL(msg.SelectedVideo.ID)
 		}
 
-		playFormat := config.GetDefault().GetDefaultFormat()
-		if cfg, err := config.Load(); err == nil {
-			playFormat = cfg.GetDefaultFormat()
+		cmd = m.PlayerManager.PlayURL(m.Player.URL, playFormat(), msg.SelectedVideo, m.Program)
+		return m, cmd
+
+	case types.PlayCollectionMsg:
+		urls := make([]string, len(msg.Videos))
+		for i, video := range msg.Videos {
+			urls[i] = utils.BuildVideoURL(video.ID)
 		}
 
-		cmd = m.PlayerManager.PlayURL(m.Player.URL, playFormat, msg.SelectedVideo, m.Program)
-		return m, cmd
+		m.Player.URL = urls[0]
+		m.Player.Video = msg.Videos[0]
+		cmd = m.PlayerManager.PlayURLs(urls, playFormat(), msg.Videos[0], m.Program)
+		return m, tea.Batch(cmd, func() tea.Msg {
+			return types.ShowToastMsg{Message: fmt.Sprintf("Playing %d videos from %s", len(urls), msg.Name)}
+		})
 
 	case types.MPVStartedMsg:
 		m.State = types.StateVideoPlaying
 		m.Player.Video = msg.SelectedVideo
 		return m, nil
 
+	case types.StartImportMsg:
+		m.State = types.StateLoading
+		m.LoadingType = "import"
+		m.CurrentQuery = strings.TrimSpace(msg.Path)
+		cmd = utils.ImportBatchFile(m.SearchManager, m.CurrentQuery, m.Search.CookiesFromBrowser, m.Search.Cookies)
+		return m, cmd
+
+	case types.ImportResultMsg:
+		m.LoadingType = ""
+		if msg.Err != "" {
+			m.State = types.StateSearchInput
+			m.ErrMsg = msg.Err
+			return m, nil
+		}
+
+		confirmCmd := func() tea.Msg {
+			return types.StartQueueConfirmMsg{Videos: msg.Videos, Label: msg.Label}
+		}
+		if msg.Skipped == 0 {
+			return m, confirmCmd
+		}
+
+		toastCmd := func() tea.Msg {
+			return types.ShowToastMsg{Message: fmt.Sprintf("Skipped %d lines of %s", msg.Skipped, msg.Label)}
+		}
+		return m, tea.Batch(confirmCmd, toastCmd)
+
 	case types.StartQueueConfirmMsg:
 		if m.DownloadManager != nil {
 			_ = m.DownloadManager.Cancel()
@@ -541,6 +704,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.LoadingType = "format"
 		m.FormatList.IsQueue = true
 		m.FormatList.QueueVideos = msg.Videos
+		m.FormatList.QueueLabel = msg.Label
 		m.FormatList.DownloadOptions = m.Search.DownloadOptions
 		m.FormatList.ShowVideoInfo = false
 		first := msg.Videos[0]
@@ -555,103 +719,34 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.resetDownloadState()
 		m.State = types.StateDownload
 		m.LoadingType = "queue"
-		m.Download.IsQueue = true
-		m.Download.QueueLabel = queueLabel
-		m.Download.QueueTotal = len(msg.Videos)
-		m.Download.QueueIndex = 1
-		m.Download.SelectedVideo = msg.Videos[0]
-		m.Download.QueueItems = make([]types.QueueItem, len(msg.Videos))
-		m.Download.QueueFormatID = msg.FormatID
-		m.Download.QueueIsAudioTab = msg.IsAudioTab
-		m.Download.QueueABR = msg.ABR
-
-		for i, v := range msg.Videos {
-			url := utils.BuildVideoURL(v.ID)
-			m.Download.QueueItems[i] = types.QueueItem{
-				Index:  i + 1,
-				Video:  v,
-				URL:    url,
-				Status: types.QueueStatusPending,
-			}
-		}
-
-		updateQueueUnfinished(queueLabel, msg.FormatID, m.Download.QueueTotal, pendingQueueURLs(m.Download.QueueItems), pendingQueueVideos(m.Download.QueueItems))
-
-		m.Download.QueueItems[0].Status = types.QueueStatusDownloading
+		m.useSearchQueueOptions()
+		cmd = m.beginQueue(queueLabel, msg.FormatID, msg.IsAudioTab, msg.ABR, newQueueItems(msg.Videos))
+		return m, cmd
 
-		req := types.DownloadRequest{
-			URL:                m.Download.QueueItems[0].URL,
-			URLs:               pendingQueueURLs(m.Download.QueueItems),
-			Videos:             pendingQueueVideos(m.Download.QueueItems),
-			FormatID:           msg.FormatID,
-			IsAudioTab:         msg.IsAudioTab,
-			ABR:                msg.ABR,
-			QueueIndex:         1,
-			QueueTotal:         m.Download.QueueTotal,
-			UnfinishedKey:      utils.QueueUnfinishedKey(queueLabel),
-			UnfinishedTitle:    queueLabel,
-			UnfinishedDesc:     fmt.Sprintf("%d items left", m.Download.QueueTotal),
-			Title:              m.Download.SelectedVideo.Title(),
-			Options:            m.Search.DownloadOptions,
-			CookiesFromBrowser: m.Search.CookiesFromBrowser,
-			Cookies:            m.Search.Cookies,
+	case types.StartQueueDownloadMsg:
+		if msg.Label != "" {
+			queueLabel = msg.Label
 		}
 
-		cmd = utils.StartDownload(m.DownloadManager, m.Program, req)
-		return m, cmd
+		var warnCmd tea.Cmd
+		if !msg.SkipSpaceCheck && len(msg.Videos) > 0 {
+			msg.SkipSpaceCheck = true
+			required := utils.EstimateQueueBytes(msg.EstimatedSize, len(msg.Videos), maxConcurrentDownloads(), msg.FormatID, msg.IsAudioTab)
+			var fits bool
+			if fits, warnCmd = m.checkDiskSpace(m.outputDir(msg.Videos[0], queueLabel), required, msg); !fits {
+				return m, nil
+			}
+		}
 
-	case types.StartQueueDownloadMsg:
 		if m.DownloadManager != nil {
 			_ = m.DownloadManager.Cancel()
 		}
 		m.resetDownloadState()
 		m.State = types.StateDownload
 		m.LoadingType = "queue"
-		m.Download.IsQueue = true
-		m.Download.QueueLabel = queueLabel
-		sourceVideos := msg.Videos
-		m.Download.QueueTotal = len(sourceVideos)
-		m.Download.QueueIndex = 1
-		m.Download.SelectedVideo = sourceVideos[0]
-		m.Download.QueueItems = make([]types.QueueItem, len(sourceVideos))
-		m.Download.QueueFormatID = msg.FormatID
-		m.Download.QueueIsAudioTab = msg.IsAudioTab
-		m.Download.QueueABR = msg.ABR
-
-		for i, v := range sourceVideos {
-			url := utils.BuildVideoURL(v.ID)
-			m.Download.QueueItems[i] = types.QueueItem{
-				Index:  i + 1,
-				Video:  v,
-				URL:    url,
-				Status: types.QueueStatusPending,
-			}
-		}
-
-		updateQueueUnfinished(queueLabel, msg.FormatID, m.Download.QueueTotal, pendingQueueURLs(m.Download.QueueItems), pendingQueueVideos(m.Download.QueueItems))
-
-		m.Download.QueueItems[0].Status = types.QueueStatusDownloading
-
-		req := types.DownloadRequest{
-			URL:                m.Download.QueueItems[0].URL,
-			URLs:               pendingQueueURLs(m.Download.QueueItems),
-			Videos:             pendingQueueVideos(m.Download.QueueItems),
-			FormatID:           msg.FormatID,
-			IsAudioTab:         msg.IsAudioTab,
-			ABR:                msg.ABR,
-			QueueIndex:         1,
-			QueueTotal:         m.Download.QueueTotal,
-			UnfinishedKey:      utils.QueueUnfinishedKey(queueLabel),
-			UnfinishedTitle:    queueLabel,
-			UnfinishedDesc:     fmt.Sprintf("%d items left", m.Download.QueueTotal),
-			Title:              m.Download.SelectedVideo.Title(),
-			Options:            m.Search.DownloadOptions,
-			CookiesFromBrowser: m.Search.CookiesFromBrowser,
-			Cookies:            m.Search.Cookies,
-		}
-
-		cmd = utils.StartDownload(m.DownloadManager, m.Program, req)
-		return m, cmd
+		m.useSearchQueueOptions()
+		cmd = m.beginQueue(queueLabel, msg.FormatID, msg.IsAudioTab, msg.ABR, newQueueItems(msg.Videos))
+		return m, tea.Batch(cmd, warnCmd)
 
 	case tea.KeyMsg:
 		switch msg.Type {
@@ -671,12 +766,19 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				switch m.LoadingType {
 				case "format", "fetch_info":
 					cmd = utils.CancelFormats(m.FormatsManager)
+				case "feed":
+					cmd = utils.CancelFeed(m.FeedManager)
 				default:
 					cmd = utils.CancelSearch(m.SearchManager)
 				}
 			}
 
 		case types.StateVideoList:
+			if m.VideoList.SavePrompt.Visible {
+				m.VideoList, cmd = m.VideoList.Update(msg)
+				return m, cmd
+			}
+
 			switch msg.String() {
 			case "b", "esc":
 				if len(m.VideoList.SelectedVideos) > 0 {
@@ -684,6 +786,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return m, nil
 				} else {
 					if HandleListEsc(m.VideoList.List) {
+						m.cancelLoadMore()
 						m.State = types.StateSearchInput
 						m.ErrMsg = ""
 						m.VideoList.List.ResetFilter()
@@ -718,6 +821,11 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.VideoList, cmd = m.VideoList.Update(msg)
 
 		case types.StateFormatList:
+			if m.FormatList.ClipEditing || m.FormatList.Confirming() || m.FormatList.SavePrompt.Visible {
+				m.FormatList, cmd = m.FormatList.Update(msg)
+				return m, cmd
+			}
+
 			switch msg.String() {
 			case "b", "esc":
 				if m.FormatList.ActiveTab != models.FormatTabCustom {
@@ -809,30 +917,326 @@ func queueRemaining(items []types.QueueItem) int {
 	return count
 }
 
-func pendingQueueURLs(items []types.QueueItem) []string {
-	var urls []string
+func queueActive(items []types.QueueItem) int {
+	count := 0
 	for _, it := range items {
-		if it.Status == types.QueueStatusPending || it.Status == types.QueueStatusDownloading || it.Status == types.QueueStatusError {
-			if it.URL != "" {
-				urls = append(urls, it.URL)
-			}
+		if it.Status == types.QueueStatusDownloading {
+			count++
 		}
 	}
 
-	return urls
+	return count
+}
+
+func newQueueItems(videos []types.VideoItem) []types.QueueItem {
+	items := make([]types.QueueItem, len(videos))
+	for i, v := range videos {
+		items[i] = types.QueueItem{
+			Index:  i + 1,
+			Video:  v,
+			URL:    utils.BuildVideoURL(v.ID),
+			Status: types.QueueStatusPending,
+		}
+	}
+
+	return items
+}
+
+// feedVideosPerChannel is how many of each subscription's latest uploads
+// the feed lists.
+const feedVideosPerChannel = 10
+
+// playFormat is the format videos are streamed to mpv in.
+func playFormat() string {
+	cfg, err := config.Load()
+	if err != nil {
+		return config.GetDefault().GetDefaultFormat()
+	}
+
+	return cfg.GetDefaultFormat()
+}
+
+func maxConcurrentDownloads() int {
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = config.GetDefault()
+	}
+
+	return max(cfg.MaxConcurrentDownloads, 1)
 }
 
-func pendingQueueVideos(items []types.QueueItem) []types.VideoItem {
-	var videos []types.VideoItem
+// queueRetryable counts the items /resume would download again, which
+// includes failed ones.
+func queueRetryable(items []types.QueueItem) int {
+	count := 0
 	for _, it := range items {
 		if it.Status == types.QueueStatusPending || it.Status == types.QueueStatusDownloading || it.Status == types.QueueStatusError {
-			if it.Video.ID != "" || it.Video.VideoTitle != "" {
-				videos = append(videos, it.Video)
-			}
+			count++
 		}
 	}
 
-	return videos
+	return count
+}
+
+func (m *Model) useSearchQueueOptions() {
+	m.Download.QueueOptions = m.Search.DownloadOptions
+	m.Download.QueueCookiesFromBrowser = m.Search.CookiesFromBrowser
+	m.Download.QueueCookies = m.Search.Cookies
+}
+
+// outputDir resolves where a video goes under the configured organize mode.
+func (m *Model) outputDir(video types.VideoItem, queueLabel string) string {
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = config.GetDefault()
+	}
+
+	channel := ""
+	if m.VideoList.IsChannelSearch {
+		channel = m.VideoList.ChannelName
+	}
+
+	playlist := ""
+	if m.VideoList.IsPlaylistSearch {
+		playlist = m.VideoList.PlaylistName
+	}
+
+	return utils.ResolveOutputDir(cfg.GetDownloadPath(), cfg.OrganizeBy, video, channel, playlist, queueLabel)
+}
+
+// checkDiskSpace compares the estimated size of a download with the free
+// space in dir. When it doesn't fit, the format list asks for confirmation
+// and resends pending; when it only just fits, a warning toast is returned.
+func (m *Model) checkDiskSpace(dir string, required int64, pending tea.Msg) (bool, tea.Cmd) {
+	if required <= 0 {
+		return true, nil
+	}
+
+	check, err := utils.CheckDiskSpace(dir, required)
+	if err != nil {
+		log.Printf("Failed to check free disk space: %v", err)
+		return true, nil
+	}
+
+	if !check.Enough() && m.State != types.StateFormatList {
+		return true, func() tea.Msg {
+			return types.ShowToastMsg{Message: "not enough disk space: " + utils.FormatBytes(check.Free) + " free"}
+		}
+	}
+
+	if !check.Enough() {
+		m.FormatList = m.FormatList.AskConfirm(fmt.Sprintf("Not enough disk space: needs about %s, %s free", utils.FormatBytes(check.Required), utils.FormatBytes(check.Free)), pending)
+		return false, nil
+	}
+
+	if check.Low() {
+		return true, func() tea.Msg {
+			return types.ShowToastMsg{Message: "low disk space: " + utils.FormatBytes(check.Free) + " free"}
+		}
+	}
+
+	return true, nil
+}
+
+func (m *Model) beginQueue(label, formatID string, isAudioTab bool, abr float64, items []types.QueueItem) tea.Cmd {
+	m.Download.IsQueue = true
+	m.Download.QueueLabel = label
+	m.Download.QueueTotal = len(items)
+	m.Download.QueueIndex = 1
+	m.Download.SelectedVideo = items[0].Video
+	m.Download.QueueItems = items
+	m.Download.QueueFormatID = formatID
+	m.Download.QueueIsAudioTab = isAudioTab
+	m.Download.QueueABR = abr
+	m.Download.QueueLimit = maxConcurrentDownloads()
+
+	for i := range m.Download.QueueItems {
+		if m.Download.QueueItems[i].OutputDir == "" {
+			m.Download.QueueItems[i].OutputDir = m.outputDir(m.Download.QueueItems[i].Video, label)
+		}
+	}
+
+	skipArchivedQueueItems(m.Download.QueueItems)
+
+	updateQueueUnfinished(m.Download, queueRemaining(items))
+
+	return m.advanceQueue(0, "")
+}
+
+func skipArchivedQueueItems(items []types.QueueItem) {
+	archive, err := utils.LoadArchive()
+	if err != nil {
+		log.Printf("Failed to load download archive: %v", err)
+		return
+	}
+
+	for i := range items {
+		if items[i].Status == types.QueueStatusPending && !items[i].ForceRedownload && archive[utils.ArchiveVideoID(items[i].Video.ID)] {
+			items[i].Status = types.QueueStatusSkipped
+			items[i].Error = "already downloaded"
+		}
+	}
+}
+
+func (m *Model) postDownloadHooks(video types.VideoItem, file, queueLabel, formatID string, queueIndex int) tea.Cmd {
+	if file == "" {
+		log.Printf("Warning: No destination for %q, skipping post-download hooks", video.Title())
+		return nil
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = config.GetDefault()
+	}
+
+	env := utils.HookEnv{
+		File:       file,
+		VideoID:    video.ID,
+		Title:      video.Title(),
+		Channel:    video.Channel,
+		QueueLabel: queueLabel,
+		FormatID:   formatID,
+	}
+
+	return utils.RunPostDownloadHooks(cfg.PostDownloadHooks, env, queueIndex, cfg.GetHookTimeout())
+}
+
+func archiveDownload(id string) {
+	if err := utils.AddToArchive(id); err != nil {
+		log.Printf("Failed to update download archive: %v", err)
+	}
+}
+
+func recordDownload(video types.VideoItem, destination, formatID string, isAudio bool) {
+	if destination == "" {
+		log.Printf("Warning: No destination for %q, not adding it to the downloads log", video.Title())
+		return
+	}
+
+	download := utils.CompletedDownload{
+		VideoID:     video.ID,
+		Title:       video.Title(),
+		Channel:     video.Channel,
+		FormatID:    formatID,
+		IsAudio:     isAudio,
+		Destination: destination,
+	}
+
+	if err := utils.AddDownload(download); err != nil {
+		log.Printf("Failed to record completed download: %v", err)
+	}
+}
+
+// queueItemIndex resolves a 1-based queue index from a message, where 0
+// means the currently focused item. It returns 0 when there is no such item.
+func (m *Model) queueItemIndex(index int) int {
+	if !m.Download.IsQueue {
+		return 0
+	}
+
+	if index <= 0 {
+		index = m.Download.QueueIndex
+	}
+
+	if index < 1 || index > len(m.Download.QueueItems) {
+		return 0
+	}
+
+	return index
+}
+
+// startQueueDownloads starts pending queue items until the concurrency
+// limit is reached.
+func (m *Model) startQueueDownloads() tea.Cmd {
+	if m.Download.QueueHold != "" {
+		return nil
+	}
+
+	limit := max(m.Download.QueueLimit, 1)
+
+	var cmds []tea.Cmd
+	for i := range m.Download.QueueItems {
+		if queueActive(m.Download.QueueItems) >= limit {
+			break
+		}
+
+		if m.Download.QueueItems[i].Status != types.QueueStatusPending {
+			continue
+		}
+
+		cmds = append(cmds, m.startQueueItem(i+1))
+	}
+
+	return tea.Batch(cmds...)
+}
+
+func (m *Model) startQueueItem(index int) tea.Cmd {
+	item := &m.Download.QueueItems[index-1]
+	item.Status = types.QueueStatusDownloading
+	item.Error = ""
+	item.Progress = 0
+	item.Speed = ""
+	item.ETA = ""
+	item.Paused = false
+
+	focused := m.Download.QueueIndex
+	if focused < 1 || focused > len(m.Download.QueueItems) || m.Download.QueueItems[focused-1].Status != types.QueueStatusDownloading || focused == index {
+		m.Download.FocusQueueItem(index)
+	}
+
+	updateQueueUnfinished(m.Download, queueRemaining(m.Download.QueueItems))
+
+	req := types.DownloadRequest{
+		URL:                item.URL,
+		Videos:             []types.VideoItem{item.Video},
+		FormatID:           m.Download.QueueFormatID,
+		IsAudioTab:         m.Download.QueueIsAudioTab,
+		ABR:                m.Download.QueueABR,
+		QueueIndex:         index,
+		QueueTotal:         m.Download.QueueTotal,
+		OutputDir:          item.OutputDir,
+		RateLimit:          m.Download.RateLimit,
+		Title:              item.Video.Title(),
+		Options:            m.Download.QueueOptions,
+		ForceRedownload:    item.ForceRedownload,
+		CookiesFromBrowser: m.Download.QueueCookiesFromBrowser,
+		Cookies:            m.Download.QueueCookies,
+	}
+
+	return utils.StartDownload(m.DownloadManager, m.Program, req)
+}
+
+// advanceQueue fills free download slots after an item finished, and marks
+// the queue complete once nothing is pending or running.
+func (m *Model) advanceQueue(index int, errMsg string) tea.Cmd {
+	if cmd := m.startQueueDownloads(); cmd != nil {
+		return cmd
+	}
+
+	remaining := queueRemaining(m.Download.QueueItems)
+	updateQueueUnfinished(m.Download, remaining)
+	if remaining > 0 {
+		return nil
+	}
+
+	if errMsg != "" {
+		m.Download.FocusQueueItem(index)
+	}
+
+	m.Download.QueueError = errMsg
+	m.Download.Completed = true
+	return nil
+}
+
+// cancelLoadMore stops listing more results for a list that's being left.
+func (m *Model) cancelLoadMore() {
+	if m.LoadMoreManager == nil {
+		return
+	}
+
+	if err := m.LoadMoreManager.Cancel(); err != nil {
+		log.Printf("Failed to cancel loading more results: %v", err)
+	}
 }
 
 func (m *Model) clearSelections() {
@@ -841,8 +1245,10 @@ func (m *Model) clearSelections() {
 	m.VideoList.List.ResetSelected()
 }
 
-func updateQueueUnfinished(query, formatID string, remaining int, urls []string, videos []types.VideoItem) {
-	label := strings.TrimSpace(query)
+// updateQueueUnfinished records the queue with the status of every item for
+// /resume while remaining items are left, and removes the entry otherwise.
+func updateQueueUnfinished(d models.DownloadModel, remaining int) {
+	label := strings.TrimSpace(d.QueueLabel)
 	if label == "" {
 		label = "Queued downloads"
 	}
@@ -856,19 +1262,38 @@ func updateQueueUnfinished(query, formatID string, remaining int, urls []string,
 		return
 	}
 
-	if len(urls) == 0 {
+	var items []utils.UnfinishedItem
+	for _, it := range d.QueueItems {
+		if it.URL == "" {
+			continue
+		}
+
+		items = append(items, utils.UnfinishedItem{
+			URL:             it.URL,
+			Video:           it.Video,
+			OutputDir:       it.OutputDir,
+			Status:          it.Status,
+			Error:           it.Error,
+			ForceRedownload: it.ForceRedownload,
+		})
+	}
+
+	if len(items) == 0 {
 		return
 	}
 
-	desc := fmt.Sprintf("%d items left", remaining)
 	entry := utils.UnfinishedDownload{
-		URL:       key,
-		FormatID:  formatID,
-		Title:     label,
-		Desc:      desc,
-		URLs:      urls,
-		Videos:    videos,
-		Timestamp: time.Now(),
+		URL:                key,
+		FormatID:           d.QueueFormatID,
+		Title:              label,
+		Desc:               fmt.Sprintf("%d items left", remaining),
+		IsAudio:            d.QueueIsAudioTab,
+		ABR:                d.QueueABR,
+		Options:            utils.SaveOptions(d.QueueOptions),
+		CookiesFromBrowser: d.QueueCookiesFromBrowser,
+		Cookies:            d.QueueCookies,
+		Items:              items,
+		Timestamp:          time.Now(),
 	}
 
 	if err := utils.AddUnfinished(entry); err != nil {
@@ -881,6 +1306,7 @@ func (m *Model) resetDownloadState() {
 	m.InitDownloadManager()
 	m.SelectedVideo = types.VideoItem{}
 	m.Download.QueueError = ""
+	m.Download.QueueHold = ""
 	m.Download.IsQueue = false
 	m.Download.QueueItems = nil
 	m.Download.QueueIndex = 0
@@ -889,16 +1315,23 @@ func (m *Model) resetDownloadState() {
 	m.Download.QueueLabel = ""
 	m.Download.QueueIsAudioTab = false
 	m.Download.QueueABR = 0
+	m.Download.QueueLimit = 0
+	m.Download.QueueOptions = nil
+	m.Download.QueueCookiesFromBrowser = ""
+	m.Download.QueueCookies = ""
 	m.Download.QueueItems = nil
 	m.Download.Progress.SetPercent(0)
 	m.Download.CurrentSpeed = ""
 	m.Download.CurrentETA = ""
 	m.Download.Phase = ""
+	m.Download.Stage = ""
+	m.Download.StageStartedAt = time.Time{}
 	m.Download.Completed = false
 	m.Download.Cancelled = false
 	m.Download.Paused = false
 	m.FormatList.IsQueue = false
 	m.FormatList.QueueVideos = nil
+	m.FormatList.QueueLabel = ""
 }
 
 func (m *Model) clearDownloadProgressState() {
@@ -909,7 +1342,20 @@ func (m *Model) clearDownloadProgressState() {
 	m.Download.CurrentSpeed = ""
 	m.Download.CurrentETA = ""
 	m.Download.Phase = ""
+	m.Download.Stage = ""
+	m.Download.StageStartedAt = time.Time{}
 	m.Download.Progress.SetPercent(0)
 	m.Download.Paused = false
 	m.Download.QueueLabel = ""
+	m.Download.HookRuns = nil
+	m.Download.IsAudioTab = false
+	m.Download.Clip = types.ClipRange{}
+	m.Download.Subtitles = types.SubtitleRequest{}
+	m.Download.Thumbnail = types.ThumbnailItem{}
+	m.Download.DownloadedBytes = 0
+	m.Download.TotalBytes = 0
+	m.Download.FragmentIndex = 0
+	m.Download.FragmentCount = 0
+	m.Download.Elapsed = 0
+	m.Download.StoppingRecording = false
 }
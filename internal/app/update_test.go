@@ -3,12 +3,16 @@ package app
 import (
 	"bytes"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/x/exp/teatest"
 	zone "github.com/lrstanley/bubblezone"
 	"github.com/xdagiz/xytz/internal/config"
+	"github.com/xdagiz/xytz/internal/models"
 	"github.com/xdagiz/xytz/internal/types"
 	"github.com/xdagiz/xytz/internal/utils"
 )
@@ -18,6 +22,9 @@ func setupQueueTestEnv(t *testing.T) {
 
 	origConfigDir := config.GetConfigDir
 	origUnfinishedPath := utils.GetUnfinishedFilePath
+	origArchivePath := utils.GetArchiveFilePath
+	origDownloadsPath := utils.GetDownloadsFilePath
+	origCollectionsPath := utils.GetCollectionsFilePath
 
 	tmpDir := t.TempDir()
 	config.GetConfigDir = func() string {
@@ -26,10 +33,22 @@ func setupQueueTestEnv(t *testing.T) {
 	utils.GetUnfinishedFilePath = func() string {
 		return filepath.Join(tmpDir, "unfinished.json")
 	}
+	utils.GetArchiveFilePath = func() string {
+		return filepath.Join(tmpDir, "archive.txt")
+	}
+	utils.GetDownloadsFilePath = func() string {
+		return filepath.Join(tmpDir, "downloads.json")
+	}
+	utils.GetCollectionsFilePath = func() string {
+		return filepath.Join(tmpDir, "collections.json")
+	}
 
 	t.Cleanup(func() {
 		config.GetConfigDir = origConfigDir
 		utils.GetUnfinishedFilePath = origUnfinishedPath
+		utils.GetArchiveFilePath = origArchivePath
+		utils.GetDownloadsFilePath = origDownloadsPath
+		utils.GetCollectionsFilePath = origCollectionsPath
 	})
 }
 
@@ -90,49 +109,29 @@ func TestQueueRemaining(t *testing.T) {
 	}
 }
 
-func TestPendingQueueURLsFiltersStatusesAndEmptyURL(t *testing.T) {
-	items := []types.QueueItem{
-		{URL: "u1", Status: types.QueueStatusPending},
-		{URL: "u2", Status: types.QueueStatusDownloading},
-		{URL: "u3", Status: types.QueueStatusError},
-		{URL: "", Status: types.QueueStatusPending},
-		{URL: "u4", Status: types.QueueStatusComplete},
-		{URL: "u5", Status: types.QueueStatusSkipped},
-	}
-
-	got := pendingQueueURLs(items)
-	if len(got) != 3 {
-		t.Fatalf("pendingQueueURLs() len = %d, want 3", len(got))
-	}
-	if got[0] != "u1" || got[1] != "u2" || got[2] != "u3" {
-		t.Fatalf("pendingQueueURLs() = %v, want [u1 u2 u3]", got)
-	}
-}
-
-func TestPendingQueueVideosFiltersStatusesAndEmptyMetadata(t *testing.T) {
+func TestQueueRetryableCountsFailedItems(t *testing.T) {
 	items := []types.QueueItem{
-		{Video: makeVideo("v1", "one"), Status: types.QueueStatusPending},
-		{Video: makeVideo("v2", "two"), Status: types.QueueStatusDownloading},
-		{Video: makeVideo("v3", "three"), Status: types.QueueStatusError},
-		{Video: types.VideoItem{}, Status: types.QueueStatusPending},
-		{Video: makeVideo("v4", "four"), Status: types.QueueStatusComplete},
-		{Video: makeVideo("v5", "five"), Status: types.QueueStatusSkipped},
+		{Status: types.QueueStatusPending},
+		{Status: types.QueueStatusDownloading},
+		{Status: types.QueueStatusError},
+		{Status: types.QueueStatusComplete},
+		{Status: types.QueueStatusSkipped},
 	}
 
-	got := pendingQueueVideos(items)
-	if len(got) != 3 {
-		t.Fatalf("pendingQueueVideos() len = %d, want 3", len(got))
-	}
-	if got[0].ID != "v1" || got[1].ID != "v2" || got[2].ID != "v3" {
-		t.Fatalf("pendingQueueVideos() IDs = [%s %s %s], want [v1 v2 v3]", got[0].ID, got[1].ID, got[2].ID)
+	if got := queueRetryable(items); got != 3 {
+		t.Fatalf("queueRetryable() = %d, want 3", got)
 	}
 }
 
 func TestUpdateQueueUnfinishedDefaultLabelAndRemove(t *testing.T) {
 	setupQueueTestEnv(t)
 
-	videos := []types.VideoItem{makeVideo("abc", "video")}
-	updateQueueUnfinished("   ", "best", 1, []string{"https://example.com/1"}, videos)
+	d := models.DownloadModel{
+		QueueLabel:    "   ",
+		QueueFormatID: "best",
+		QueueItems:    []types.QueueItem{{Video: makeVideo("abc", "video"), URL: "https://example.com/1", Status: types.QueueStatusPending}},
+	}
+	updateQueueUnfinished(d, 1)
 
 	entry := utils.GetUnfinishedByURL("queue:Queued downloads")
 	if entry == nil {
@@ -145,7 +144,7 @@ func TestUpdateQueueUnfinishedDefaultLabelAndRemove(t *testing.T) {
 		t.Fatalf("entry.Desc = %q, want %q", entry.Desc, "1 items left")
 	}
 
-	updateQueueUnfinished("", "best", 0, nil, nil)
+	updateQueueUnfinished(d, 0)
 	entry = utils.GetUnfinishedByURL("queue:Queued downloads")
 	if entry != nil {
 		t.Fatalf("expected unfinished queue entry to be removed, got %+v", *entry)
@@ -155,7 +154,11 @@ func TestUpdateQueueUnfinishedDefaultLabelAndRemove(t *testing.T) {
 func TestUpdateQueueUnfinishedSkipsWriteWhenNoURLs(t *testing.T) {
 	setupQueueTestEnv(t)
 
-	updateQueueUnfinished("q", "best", 2, nil, []types.VideoItem{makeVideo("abc", "video")})
+	updateQueueUnfinished(models.DownloadModel{
+		QueueLabel:    "q",
+		QueueFormatID: "best",
+		QueueItems:    []types.QueueItem{{Video: makeVideo("abc", "video"), Status: types.QueueStatusPending}},
+	}, 2)
 
 	downloads, err := utils.LoadUnfinished()
 	if err != nil {
@@ -208,8 +211,63 @@ func TestModelUpdateStartQueueDownloadInitializesQueue(t *testing.T) {
 	if entry == nil {
 		t.Fatalf("expected unfinished queue entry for query label")
 	}
-	if len(entry.URLs) != 2 {
-		t.Fatalf("unfinished URLs len = %d, want 2", len(entry.URLs))
+	if len(entry.Items) != 2 {
+		t.Fatalf("unfinished Items len = %d, want 2", len(entry.Items))
+	}
+}
+
+func TestModelUpdateStartQueueDownloadUsesMessageLabel(t *testing.T) {
+	m := newQueueTestModel(t)
+	m.CurrentQuery = "query label"
+
+	updated, _ := m.Update(types.StartQueueDownloadMsg{
+		FormatID: "best",
+		Videos:   []types.VideoItem{makeVideo("id1", "video one"), makeVideo("id2", "video two")},
+		Label:    "urls.txt",
+	})
+	m = updated.(*Model)
+
+	if m.Download.QueueLabel != "urls.txt" {
+		t.Fatalf("m.Download.QueueLabel = %q, want urls.txt", m.Download.QueueLabel)
+	}
+	if entry := utils.GetUnfinishedByURL(utils.QueueUnfinishedKey("urls.txt")); entry == nil {
+		t.Fatalf("expected the queue to be saved under the file name")
+	}
+}
+
+func TestModelUpdateImportResultConfirmsQueue(t *testing.T) {
+	m := newQueueTestModel(t)
+	m.State = types.StateLoading
+	m.LoadingType = "import"
+
+	videos := []types.VideoItem{makeVideo("id1", "video one")}
+	_, cmd := m.Update(types.ImportResultMsg{Label: "urls.txt", Videos: videos})
+	if cmd == nil {
+		t.Fatalf("expected a command")
+	}
+
+	msg, ok := cmd().(types.StartQueueConfirmMsg)
+	if !ok {
+		t.Fatalf("cmd msg type = %T, want types.StartQueueConfirmMsg", cmd())
+	}
+	if msg.Label != "urls.txt" || len(msg.Videos) != 1 {
+		t.Fatalf("unexpected confirm msg: %+v", msg)
+	}
+}
+
+func TestModelUpdateImportResultErrorReturnsToSearch(t *testing.T) {
+	m := newQueueTestModel(t)
+	m.State = types.StateLoading
+	m.LoadingType = "import"
+
+	updated, _ := m.Update(types.ImportResultMsg{Label: "urls.txt", Err: "No videos found in urls.txt"})
+	m = updated.(*Model)
+
+	if m.State != types.StateSearchInput {
+		t.Fatalf("m.State = %q, want %q", m.State, types.StateSearchInput)
+	}
+	if m.ErrMsg != "No videos found in urls.txt" {
+		t.Fatalf("m.ErrMsg = %q", m.ErrMsg)
 	}
 }
 
@@ -271,7 +329,7 @@ func TestModelUpdateDownloadResultFinalErrorCompletesQueue(t *testing.T) {
 		{Index: 1, Video: makeVideo("id1", "video one"), URL: "u1", Status: types.QueueStatusDownloading},
 	}
 
-	updateQueueUnfinished("queue", "best", 1, []string{"u1"}, []types.VideoItem{makeVideo("id1", "video one")})
+	updateQueueUnfinished(m.Download, 1)
 
 	tm.Send(types.DownloadResultMsg{Err: "boom"})
 	waitForOutputContains(t, tm, "Error: boom")
@@ -322,8 +380,8 @@ func TestModelUpdateCancelDownloadQueueRequeuesCurrentItem(t *testing.T) {
 	if entry == nil {
 		t.Fatalf("expected unfinished queue entry to exist after cancel")
 	}
-	if len(entry.URLs) != 2 {
-		t.Fatalf("entry.URLs len = %d, want 2", len(entry.URLs))
+	if len(entry.Items) != 2 {
+		t.Fatalf("entry.Items len = %d, want 2", len(entry.Items))
 	}
 }
 
@@ -338,7 +396,7 @@ func TestModelUpdateSkipLastQueueItemCompletesQueue(t *testing.T) {
 		{Index: 1, Video: makeVideo("id1", "video one"), URL: "u1", Status: types.QueueStatusDownloading},
 	}
 
-	updateQueueUnfinished("queue", "best", 1, []string{"u1"}, []types.VideoItem{makeVideo("id1", "video one")})
+	updateQueueUnfinished(m.Download, 1)
 
 	tm.Send(types.SkipCurrentQueueItemMsg{})
 	waitForOutputContains(t, tm, "Queue Summary:")
@@ -390,12 +448,14 @@ func TestModelUpdateStartResumeDownloadUsesVideoInfoFromUnfinishedItem(t *testin
 		URL:      "https://www.youtube.com/watch?v=abc123",
 		FormatID: "best",
 		Title:    "Fallback Title",
-		Videos: []types.VideoItem{
+		Items: []types.QueueItem{
 			{
-				ID:         "https://www.youtube.com/watch?v=abc123",
-				VideoTitle: "Real Video Title",
-				Channel:    "Real Channel",
-				Duration:   120,
+				Video: types.VideoItem{
+					ID:         "https://www.youtube.com/watch?v=abc123",
+					VideoTitle: "Real Video Title",
+					Channel:    "Real Channel",
+					Duration:   120,
+				},
 			},
 		},
 	})
@@ -432,3 +492,615 @@ func TestModelUpdateStartResumeDownloadFallbacksToTitleAndURL(t *testing.T) {
 		t.Fatalf("SelectedVideo.ID = %q, want URL", m.Download.SelectedVideo.ID)
 	}
 }
+
+func TestModelUpdateStartQueueDownloadRespectsConcurrencyLimit(t *testing.T) {
+	m := newQueueTestModel(t)
+	cfg := config.GetDefault()
+	cfg.MaxConcurrentDownloads = 2
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("cfg.Save() error = %v", err)
+	}
+
+	videos := []types.VideoItem{makeVideo("id1", "one"), makeVideo("id2", "two"), makeVideo("id3", "three")}
+	updated, cmd := m.Update(types.StartQueueDownloadMsg{FormatID: "best", Videos: videos})
+	m = updated.(*Model)
+
+	if cmd == nil {
+		t.Fatalf("expected non-nil download command")
+	}
+	if m.Download.QueueLimit != 2 {
+		t.Fatalf("m.Download.QueueLimit = %d, want 2", m.Download.QueueLimit)
+	}
+
+	want := []types.QueueStatus{types.QueueStatusDownloading, types.QueueStatusDownloading, types.QueueStatusPending}
+	for i, status := range want {
+		if m.Download.QueueItems[i].Status != status {
+			t.Fatalf("item %d status = %q, want %q", i+1, m.Download.QueueItems[i].Status, status)
+		}
+	}
+	if m.Download.QueueIndex != 1 {
+		t.Fatalf("m.Download.QueueIndex = %d, want 1", m.Download.QueueIndex)
+	}
+}
+
+func TestModelUpdateDownloadResultRoutesByQueueIndex(t *testing.T) {
+	m := newQueueTestModel(t)
+	m.Download.IsQueue = true
+	m.Download.QueueLabel = "queue"
+	m.Download.QueueFormatID = "best"
+	m.Download.QueueLimit = 2
+	m.Download.QueueTotal = 3
+	m.Download.QueueIndex = 1
+	m.Download.QueueItems = []types.QueueItem{
+		{Index: 1, Video: makeVideo("id1", "one"), URL: "u1", Status: types.QueueStatusDownloading},
+		{Index: 2, Video: makeVideo("id2", "two"), URL: "u2", Status: types.QueueStatusDownloading},
+		{Index: 3, Video: makeVideo("id3", "three"), URL: "u3", Status: types.QueueStatusPending},
+	}
+
+	updated, cmd := m.Update(types.DownloadResultMsg{QueueIndex: 2, Destination: "/tmp/two.mp4"})
+	m = updated.(*Model)
+
+	if cmd == nil {
+		t.Fatalf("expected non-nil command to fill the free slot")
+	}
+	if m.Download.QueueItems[0].Status != types.QueueStatusDownloading {
+		t.Fatalf("first item status = %q, want %q", m.Download.QueueItems[0].Status, types.QueueStatusDownloading)
+	}
+	if m.Download.QueueItems[1].Status != types.QueueStatusComplete {
+		t.Fatalf("second item status = %q, want %q", m.Download.QueueItems[1].Status, types.QueueStatusComplete)
+	}
+	if m.Download.QueueItems[1].Destination != "/tmp/two.mp4" {
+		t.Fatalf("second item destination = %q, want /tmp/two.mp4", m.Download.QueueItems[1].Destination)
+	}
+	if m.Download.QueueItems[2].Status != types.QueueStatusDownloading {
+		t.Fatalf("third item status = %q, want %q", m.Download.QueueItems[2].Status, types.QueueStatusDownloading)
+	}
+	if m.Download.QueueIndex != 1 {
+		t.Fatalf("m.Download.QueueIndex = %d, want focus to stay on 1", m.Download.QueueIndex)
+	}
+}
+
+func TestModelUpdateSkipQueueItemByIndexKeepsOthersRunning(t *testing.T) {
+	m := newQueueTestModel(t)
+	m.Download.IsQueue = true
+	m.Download.QueueLabel = "queue"
+	m.Download.QueueFormatID = "best"
+	m.Download.QueueLimit = 2
+	m.Download.QueueTotal = 2
+	m.Download.QueueIndex = 1
+	m.Download.QueueItems = []types.QueueItem{
+		{Index: 1, Video: makeVideo("id1", "one"), URL: "u1", Status: types.QueueStatusDownloading},
+		{Index: 2, Video: makeVideo("id2", "two"), URL: "u2", Status: types.QueueStatusDownloading},
+	}
+
+	updated, _ := m.Update(types.SkipCurrentQueueItemMsg{Index: 2})
+	m = updated.(*Model)
+
+	if m.Download.QueueItems[1].Status != types.QueueStatusSkipped {
+		t.Fatalf("second item status = %q, want %q", m.Download.QueueItems[1].Status, types.QueueStatusSkipped)
+	}
+	if m.Download.QueueItems[0].Status != types.QueueStatusDownloading {
+		t.Fatalf("first item status = %q, want %q", m.Download.QueueItems[0].Status, types.QueueStatusDownloading)
+	}
+	if m.Download.Completed {
+		t.Fatalf("m.Download.Completed = true, want false while an item is running")
+	}
+}
+
+func TestModelUpdateCancelQueueItemMarksItemCancelled(t *testing.T) {
+	m := newQueueTestModel(t)
+	m.Download.IsQueue = true
+	m.Download.QueueLabel = "queue"
+	m.Download.QueueFormatID = "best"
+	m.Download.QueueTotal = 1
+	m.Download.QueueIndex = 1
+	m.Download.QueueItems = []types.QueueItem{
+		{Index: 1, Video: makeVideo("id1", "one"), URL: "u1", Status: types.QueueStatusDownloading},
+	}
+
+	updated, _ := m.Update(types.CancelQueueItemMsg{Index: 1})
+	m = updated.(*Model)
+
+	item := m.Download.QueueItems[0]
+	if item.Status != types.QueueStatusError || item.Error != "Download cancelled" {
+		t.Fatalf("item = %+v, want cancelled error status", item)
+	}
+	if !m.Download.Completed || m.Download.QueueError != "Download cancelled" {
+		t.Fatalf("queue completed/error = %v/%q, want true/%q", m.Download.Completed, m.Download.QueueError, "Download cancelled")
+	}
+}
+
+func TestModelUpdateCancelledQueueItemResultKeepsQueueError(t *testing.T) {
+	m := newQueueTestModel(t)
+	m.Download.IsQueue = true
+	m.Download.QueueLabel = "queue"
+	m.Download.QueueFormatID = "best"
+	m.Download.QueueTotal = 2
+	m.Download.QueueIndex = 2
+	m.Download.QueueItems = []types.QueueItem{
+		{Index: 1, Video: makeVideo("id1", "one"), URL: "u1", Status: types.QueueStatusComplete},
+		{Index: 2, Video: makeVideo("id2", "two"), URL: "u2", Status: types.QueueStatusDownloading},
+	}
+
+	updated, _ := m.Update(types.CancelQueueItemMsg{Index: 2})
+	m = updated.(*Model)
+
+	// The killed process reports back after the cancel.
+	updated, cmd := m.Update(types.DownloadResultMsg{Err: "yt-dlp command failed: signal: killed", QueueIndex: 2, QueueTotal: 2})
+	m = updated.(*Model)
+
+	if cmd != nil {
+		t.Fatalf("expected no command for the cancelled item's result")
+	}
+	if !m.Download.Completed || m.Download.QueueError != "Download cancelled" {
+		t.Fatalf("queue completed/error = %v/%q, want true/%q", m.Download.Completed, m.Download.QueueError, "Download cancelled")
+	}
+	if item := m.Download.QueueItems[1]; item.Error != "Download cancelled" {
+		t.Fatalf("item error = %q, want it to stay cancelled", item.Error)
+	}
+}
+
+func TestModelUpdateStartQueueDownloadSkipsArchivedVideos(t *testing.T) {
+	m := newQueueTestModel(t)
+	if err := utils.AddToArchive("id1"); err != nil {
+		t.Fatalf("AddToArchive() error = %v", err)
+	}
+
+	videos := []types.VideoItem{makeVideo("id1", "one"), makeVideo("id2", "two")}
+	updated, cmd := m.Update(types.StartQueueDownloadMsg{FormatID: "best", Videos: videos})
+	m = updated.(*Model)
+
+	if cmd == nil {
+		t.Fatalf("expected non-nil download command")
+	}
+	if m.Download.QueueItems[0].Status != types.QueueStatusSkipped {
+		t.Fatalf("first item status = %q, want %q", m.Download.QueueItems[0].Status, types.QueueStatusSkipped)
+	}
+	if m.Download.QueueItems[1].Status != types.QueueStatusDownloading {
+		t.Fatalf("second item status = %q, want %q", m.Download.QueueItems[1].Status, types.QueueStatusDownloading)
+	}
+	if m.Download.QueueIndex != 2 {
+		t.Fatalf("m.Download.QueueIndex = %d, want 2", m.Download.QueueIndex)
+	}
+}
+
+func TestModelUpdateStartQueueDownloadAllArchivedCompletes(t *testing.T) {
+	m := newQueueTestModel(t)
+	if err := utils.AddToArchive("id1"); err != nil {
+		t.Fatalf("AddToArchive() error = %v", err)
+	}
+
+	updated, _ := m.Update(types.StartQueueDownloadMsg{FormatID: "best", Videos: []types.VideoItem{makeVideo("id1", "one")}})
+	m = updated.(*Model)
+
+	if !m.Download.Completed {
+		t.Fatalf("m.Download.Completed = false, want true when every item is archived")
+	}
+}
+
+func TestModelUpdateRetrySkippedQueueItemForcesRedownload(t *testing.T) {
+	m := newQueueTestModel(t)
+	if err := utils.AddToArchive("id1"); err != nil {
+		t.Fatalf("AddToArchive() error = %v", err)
+	}
+
+	updated, _ := m.Update(types.StartQueueDownloadMsg{FormatID: "best", Videos: []types.VideoItem{makeVideo("id1", "one")}})
+	m = updated.(*Model)
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	m = updated.(*Model)
+	if cmd == nil {
+		t.Fatalf("expected r to retry the skipped item")
+	}
+	retry, ok := cmd().(types.RetryCurrentQueueItemMsg)
+	if !ok {
+		t.Fatalf("expected RetryCurrentQueueItemMsg")
+	}
+
+	updated, cmd = m.Update(retry)
+	m = updated.(*Model)
+
+	if cmd == nil {
+		t.Fatalf("expected non-nil download command")
+	}
+	item := m.Download.QueueItems[0]
+	if item.Status != types.QueueStatusDownloading || !item.ForceRedownload {
+		t.Fatalf("item status/force = %q/%v, want %q/true", item.Status, item.ForceRedownload, types.QueueStatusDownloading)
+	}
+	if m.Download.Completed {
+		t.Fatalf("m.Download.Completed = true, want false while the retry runs")
+	}
+}
+
+func TestModelUpdateDownloadResultArchivesCompletedVideo(t *testing.T) {
+	m := newQueueTestModel(t)
+	m.Download.SelectedVideo = makeVideo("abc", "single")
+
+	updated, _ := m.Update(types.DownloadResultMsg{Output: "Download complete"})
+	m = updated.(*Model)
+
+	if !utils.IsArchived("abc") {
+		t.Fatalf("expected completed video to be archived")
+	}
+}
+
+func TestModelUpdateDownloadResultRecordsCompletedDownload(t *testing.T) {
+	m := newQueueTestModel(t)
+	m.Download.SelectedVideo = makeVideo("abc", "single")
+	m.Download.FormatID = "140"
+	m.Download.IsAudioTab = true
+
+	updated, _ := m.Update(types.DownloadResultMsg{Output: "Download complete", Destination: "/downloads/single.mp3"})
+	m = updated.(*Model)
+
+	downloads, err := utils.LoadDownloads()
+	if err != nil {
+		t.Fatalf("LoadDownloads() error = %v", err)
+	}
+	if len(downloads) != 1 {
+		t.Fatalf("len(downloads) = %d, want 1", len(downloads))
+	}
+
+	got := downloads[0]
+	if got.VideoID != "abc" || got.FormatID != "140" || !got.IsAudio || got.Destination != "/downloads/single.mp3" {
+		t.Fatalf("unexpected downloads entry: %+v", got)
+	}
+}
+
+func TestModelUpdateStartQueueDownloadOrganizesByQueueLabel(t *testing.T) {
+	m := newQueueTestModel(t)
+	cfg := config.GetDefault()
+	cfg.DefaultDownloadPath = "/downloads"
+	cfg.OrganizeBy = config.OrganizeQueue
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("cfg.Save() error = %v", err)
+	}
+	m.CurrentQuery = "lofi"
+
+	videos := []types.VideoItem{makeVideo("id1", "one"), makeVideo("id2", "two")}
+	updated, _ := m.Update(types.StartQueueDownloadMsg{FormatID: "best", Videos: videos})
+	m = updated.(*Model)
+
+	want := filepath.Join("/downloads", "lofi")
+	for i, item := range m.Download.QueueItems {
+		if item.OutputDir != want {
+			t.Fatalf("item %d OutputDir = %q, want %q", i+1, item.OutputDir, want)
+		}
+	}
+
+	entry := utils.GetUnfinishedByURL("queue:lofi")
+	if entry == nil {
+		t.Fatalf("expected unfinished queue entry")
+	}
+	if len(entry.Items) != 2 || entry.Items[0].OutputDir != want || entry.Items[1].OutputDir != want {
+		t.Fatalf("entry.Items = %+v, want two items in %q", entry.Items, want)
+	}
+}
+
+func TestModelUpdateStartResumeDownloadRestoresOutputDirs(t *testing.T) {
+	m := newQueueTestModel(t)
+	cfg := config.GetDefault()
+	cfg.OrganizeBy = config.OrganizeChannel
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("cfg.Save() error = %v", err)
+	}
+
+	updated, _ := m.Update(types.StartResumeDownloadMsg{
+		Title:    "resumed",
+		FormatID: "best",
+		Queue:    true,
+		Items: []types.QueueItem{
+			{Index: 1, Video: makeVideo("id1", "one"), URL: "u1", Status: types.QueueStatusPending, OutputDir: "/old/one"},
+			{Index: 2, Video: makeVideo("id2", "two"), URL: "u2", Status: types.QueueStatusPending, OutputDir: "/old/two"},
+		},
+	})
+	m = updated.(*Model)
+
+	if got := m.Download.QueueItems[0].OutputDir; got != "/old/one" {
+		t.Fatalf("first item OutputDir = %q, want /old/one", got)
+	}
+	if got := m.Download.QueueItems[1].OutputDir; got != "/old/two" {
+		t.Fatalf("second item OutputDir = %q, want /old/two", got)
+	}
+}
+
+func TestModelUpdateSetRateLimitAppliesToRestOfQueue(t *testing.T) {
+	m := newQueueTestModel(t)
+
+	updated, cmd := m.Update(types.SetRateLimitMsg{Limit: "5M"})
+	m = updated.(*Model)
+
+	if m.Download.RateLimit != "5M" {
+		t.Fatalf("m.Download.RateLimit = %q, want 5M", m.Download.RateLimit)
+	}
+	if cmd == nil {
+		t.Fatalf("expected toast command")
+	}
+	if toast, ok := cmd().(types.ShowToastMsg); !ok || toast.Message != "rate limit: 5M/s" {
+		t.Fatalf("cmd() = %#v, want rate limit toast", cmd())
+	}
+}
+
+func TestModelUpdateSetRateLimitKeepsPausedItemsPaused(t *testing.T) {
+	m := newQueueTestModel(t)
+	m.Download.IsQueue = true
+	m.Download.QueueItems = []types.QueueItem{
+		{Index: 1, Video: makeVideo("id1", "one"), Status: types.QueueStatusDownloading, Paused: true},
+		{Index: 2, Video: makeVideo("id2", "two"), Status: types.QueueStatusDownloading},
+	}
+
+	updated, _ := m.Update(types.SetRateLimitMsg{Limit: "5M"})
+	m = updated.(*Model)
+
+	if !m.Download.QueueItems[0].Paused {
+		t.Fatalf("paused item was resumed by the rate limit change")
+	}
+}
+
+func TestModelUpdateDownloadResultRunsPostDownloadHooks(t *testing.T) {
+	m := newQueueTestModel(t)
+	cfg := config.GetDefault()
+	cfg.PostDownloadHooks = []string{"exit 1"}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("cfg.Save() error = %v", err)
+	}
+	m.State = types.StateDownload
+	m.Download.SelectedVideo = makeVideo("abc", "single")
+	m.Download.FormatID = "best"
+
+	updated, cmd := m.Update(types.DownloadResultMsg{Output: "Download complete", Destination: "/tmp/single.mp4"})
+	m = updated.(*Model)
+
+	if cmd == nil {
+		t.Fatalf("expected hook command")
+	}
+	hookMsg, ok := cmd().(types.HookResultMsg)
+	if !ok {
+		t.Fatalf("cmd() returned %T, want types.HookResultMsg", cmd())
+	}
+
+	updated, _ = m.Update(hookMsg)
+	m = updated.(*Model)
+
+	if !m.Download.Completed || m.Download.QueueError != "" || m.ErrMsg != "" {
+		t.Fatalf("failed hook changed download outcome: completed=%v queueErr=%q err=%q", m.Download.Completed, m.Download.QueueError, m.ErrMsg)
+	}
+	if len(m.Download.HookRuns) != 1 || m.Download.HookRuns[0].Results[0].ExitCode != 1 {
+		t.Fatalf("m.Download.HookRuns = %+v, want one failed run", m.Download.HookRuns)
+	}
+	if view := m.Download.View(); !strings.Contains(view, "✗ exit 1 (exit 1)") {
+		t.Fatalf("download view missing hook result:\n%s", view)
+	}
+}
+
+func TestModelUpdateDownloadResultWithoutDestinationSkipsHooks(t *testing.T) {
+	m := newQueueTestModel(t)
+	cfg := config.GetDefault()
+	cfg.PostDownloadHooks = []string{"exit 1"}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("cfg.Save() error = %v", err)
+	}
+	m.State = types.StateDownload
+	m.Download.SelectedVideo = makeVideo("abc", "single")
+
+	updated, cmd := m.Update(types.DownloadResultMsg{Output: "Download complete"})
+	m = updated.(*Model)
+
+	if cmd != nil {
+		t.Fatalf("expected no hook command without a destination")
+	}
+	if !m.Download.Completed || !utils.IsArchived("abc") {
+		t.Fatalf("download without a destination should still complete and be archived")
+	}
+	if downloads, _ := utils.LoadDownloads(); len(downloads) != 0 {
+		t.Fatalf("downloads = %+v, want none without a destination", downloads)
+	}
+}
+
+func TestModelUpdateDownloadResultNoSpaceHoldsQueue(t *testing.T) {
+	m := newQueueTestModel(t)
+	m.Download.IsQueue = true
+	m.Download.QueueLabel = "queue"
+	m.Download.QueueFormatID = "best"
+	m.Download.QueueTotal = 2
+	m.Download.QueueIndex = 1
+	m.Download.QueueLimit = 1
+	m.Download.QueueItems = []types.QueueItem{
+		{Index: 1, Video: makeVideo("id1", "video one"), URL: "u1", Status: types.QueueStatusDownloading},
+		{Index: 2, Video: makeVideo("id2", "video two"), URL: "u2", Status: types.QueueStatusPending},
+	}
+
+	updated, _ := m.Update(types.DownloadResultMsg{Err: "Download error: no space left on device", NoSpace: true, QueueIndex: 1})
+	m = updated.(*Model)
+
+	if m.Download.QueueHold == "" {
+		t.Fatalf("expected the queue to be on hold")
+	}
+	for i, item := range m.Download.QueueItems {
+		if item.Status != types.QueueStatusPending {
+			t.Fatalf("item %d status = %q, want %q", i+1, item.Status, types.QueueStatusPending)
+		}
+	}
+	if m.Download.Completed {
+		t.Fatalf("m.Download.Completed = true, want false")
+	}
+
+	updated, cmd := m.Update(types.ResumeQueueMsg{})
+	m = updated.(*Model)
+
+	if cmd == nil {
+		t.Fatalf("expected a command restarting the queue")
+	}
+	if m.Download.QueueHold != "" {
+		t.Fatalf("QueueHold = %q, want it cleared", m.Download.QueueHold)
+	}
+	if m.Download.QueueItems[0].Status != types.QueueStatusDownloading {
+		t.Fatalf("first item status = %q, want %q", m.Download.QueueItems[0].Status, types.QueueStatusDownloading)
+	}
+}
+
+func TestModelUpdateStartDownloadAsksWhenDiskIsFull(t *testing.T) {
+	m := newQueueTestModel(t)
+	m.State = types.StateFormatList
+
+	origFree := utils.FreeDiskSpace
+	utils.FreeDiskSpace = func(string) (int64, error) { return 100, nil }
+	t.Cleanup(func() { utils.FreeDiskSpace = origFree })
+
+	updated, _ := m.Update(types.StartDownloadMsg{URL: "u1", FormatID: "137+140", EstimatedSize: 1000})
+	m = updated.(*Model)
+
+	if m.State != types.StateFormatList {
+		t.Fatalf("state = %q, want %q", m.State, types.StateFormatList)
+	}
+	if !m.FormatList.Confirming() {
+		t.Fatalf("expected a disk space prompt")
+	}
+
+	pending, ok := m.FormatList.Confirm.Pending.(types.StartDownloadMsg)
+	if !ok || !pending.SkipSpaceCheck || pending.FormatID != "137+140" {
+		t.Fatalf("Pending = %+v, want the download with SkipSpaceCheck set", m.FormatList.Confirm.Pending)
+	}
+}
+
+func TestModelUpdateStartDownloadAsksWhenArchived(t *testing.T) {
+	m := newQueueTestModel(t)
+	m.State = types.StateFormatList
+	if err := utils.AddToArchive("abc"); err != nil {
+		t.Fatalf("AddToArchive() error = %v", err)
+	}
+
+	url := utils.BuildVideoURL("abc")
+	updated, _ := m.Update(types.StartDownloadMsg{URL: url, FormatID: "best", SelectedVideo: makeVideo("abc", "seen")})
+	m = updated.(*Model)
+
+	if m.State != types.StateFormatList {
+		t.Fatalf("state = %q, want %q", m.State, types.StateFormatList)
+	}
+	if m.FormatList.Confirm.Message != "Already downloaded: seen" {
+		t.Fatalf("Confirm.Message = %q", m.FormatList.Confirm.Message)
+	}
+
+	pending, ok := m.FormatList.Confirm.Pending.(types.StartDownloadMsg)
+	if !ok || !pending.ForceRedownload || pending.URL != url {
+		t.Fatalf("Pending = %+v, want the download with ForceRedownload set", m.FormatList.Confirm.Pending)
+	}
+
+	updated, _ = m.Update(types.StartDownloadMsg{URL: url, FormatID: "best", Clip: types.ClipRange{Start: 1, End: 2}})
+	m = updated.(*Model)
+	if m.State != types.StateDownload {
+		t.Fatalf("state = %q, want a clip of an archived video to start", m.State)
+	}
+}
+
+func TestModelUpdateStartDownloadSkipsArchivedOutsideFormatList(t *testing.T) {
+	m := newQueueTestModel(t)
+	m.State = types.StateVideoList
+	if err := utils.AddToArchive("abc"); err != nil {
+		t.Fatalf("AddToArchive() error = %v", err)
+	}
+
+	updated, cmd := m.Update(types.StartDownloadMsg{URL: utils.BuildVideoURL("abc"), FormatID: "best", SelectedVideo: makeVideo("abc", "seen")})
+	m = updated.(*Model)
+
+	if m.State != types.StateVideoList {
+		t.Fatalf("state = %q, want the download not to start", m.State)
+	}
+	if toast, ok := cmd().(types.ShowToastMsg); !ok || toast.Message != "already downloaded: seen" {
+		t.Fatalf("cmd msg = %+v, want an already downloaded toast", toast)
+	}
+}
+
+func TestModelUpdateStartResumeDownloadRestoresAudioQueue(t *testing.T) {
+	m := newQueueTestModel(t)
+	options := []types.DownloadOption{{Name: "Embed Thumbnail", ConfigField: "EmbedThumbnail", Enabled: true}}
+
+	updated, _ := m.Update(types.StartResumeDownloadMsg{
+		Title:      "albums",
+		FormatID:   "140",
+		IsAudioTab: true,
+		ABR:        128,
+		Options:    options,
+		Queue:      true,
+		Items: []types.QueueItem{
+			{Index: 1, Video: makeVideo("id1", "one"), URL: "u1", Status: types.QueueStatusComplete},
+			{Index: 2, Video: makeVideo("id2", "two"), URL: "u2", Status: types.QueueStatusPending},
+		},
+	})
+	m = updated.(*Model)
+
+	if !m.Download.QueueIsAudioTab || m.Download.QueueABR != 128 {
+		t.Fatalf("queue audio/ABR = %v/%v, want true/128", m.Download.QueueIsAudioTab, m.Download.QueueABR)
+	}
+	if !types.IsOptionEnabled(m.Download.QueueOptions, "EmbedThumbnail") {
+		t.Fatalf("QueueOptions = %+v, want the resumed options", m.Download.QueueOptions)
+	}
+	if m.Download.QueueItems[0].Status != types.QueueStatusComplete {
+		t.Fatalf("first item status = %q, want it to stay complete", m.Download.QueueItems[0].Status)
+	}
+	if m.Download.QueueItems[1].Status != types.QueueStatusDownloading {
+		t.Fatalf("second item status = %q, want %q", m.Download.QueueItems[1].Status, types.QueueStatusDownloading)
+	}
+
+	entry := utils.GetUnfinishedByURL("queue:albums")
+	if entry == nil {
+		t.Fatalf("expected unfinished queue entry")
+	}
+	if !entry.IsAudio || entry.ABR != 128 || len(entry.Options) != 1 || !entry.Options[0].Enabled {
+		t.Fatalf("entry = %+v, want audio settings and options recorded", entry)
+	}
+	if entry.Items[0].Status != types.QueueStatusComplete {
+		t.Fatalf("entry.Items[0].Status = %q, want complete", entry.Items[0].Status)
+	}
+}
+
+func TestModelUpdateFeedResultShowsFeed(t *testing.T) {
+	m := newQueueTestModel(t)
+	m.State = types.StateLoading
+	m.LoadingType = "feed"
+	m.VideoList.IsChannelSearch = true
+
+	videos := []list.Item{
+		types.VideoItem{ID: "new", VideoTitle: "New upload", IsNew: true},
+		types.VideoItem{ID: "old", VideoTitle: "Old upload"},
+	}
+	updated, cmd := m.Update(types.FeedResultMsg{Videos: videos, Channels: 3, New: 1, Failed: []string{"@gone: Channel not found"}})
+	m = updated.(*Model)
+
+	if m.State != types.StateVideoList {
+		t.Fatalf("m.State = %q, want %q", m.State, types.StateVideoList)
+	}
+	if !m.VideoList.IsFeed || m.VideoList.IsChannelSearch || m.VideoList.FeedNew != 1 {
+		t.Fatalf("unexpected video list: IsFeed=%v IsChannelSearch=%v FeedNew=%d", m.VideoList.IsFeed, m.VideoList.IsChannelSearch, m.VideoList.FeedNew)
+	}
+	if len(m.VideoList.List.Items()) != 2 {
+		t.Fatalf("len(items) = %d, want 2", len(m.VideoList.List.Items()))
+	}
+	if cmd == nil {
+		t.Fatalf("expected a toast for the failed channel")
+	}
+	if toast, ok := cmd().(types.ShowToastMsg); !ok || !strings.Contains(toast.Message, "1 of 3 channels") {
+		t.Fatalf("cmd msg = %+v, want failed channels toast", cmd())
+	}
+}
+
+func TestModelVideoListSavePromptTakesKeys(t *testing.T) {
+	m := newQueueTestModel(t)
+	m.State = types.StateVideoList
+	m.VideoList.SetItems([]list.Item{makeVideo("a", "Video A")})
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("w")})
+	if !m.VideoList.SavePrompt.Visible {
+		t.Fatalf("expected w to open the save prompt")
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")})
+	if m.State != types.StateVideoList || m.VideoList.SavePrompt.Input.Value() != "Watch Laterb" {
+		t.Fatalf("b should be typed into the prompt, got state %q and %q", m.State, m.VideoList.SavePrompt.Input.Value())
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.State != types.StateVideoList || m.VideoList.SavePrompt.Visible {
+		t.Fatalf("esc should only close the prompt")
+	}
+}
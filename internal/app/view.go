@@ -20,6 +20,8 @@ type StatusBarConfig struct {
 	IsCancelled           bool
 	Keys                  models.StatusKeys
 	ResumeVisible         bool
+	DownloadsVisible      bool
+	CollectionsVisible    bool
 	QueueDownloadComplete bool
 	SelectedVideosCount   int
 	ShowFormatSelect      bool
@@ -46,13 +48,35 @@ func getStatusBarText(m *Model, cfg StatusBarConfig) string {
 			)
 		}
 
+		if cfg.DownloadsVisible {
+			return styles.StatusBarStyle.Padding(0).Italic(true).Render(
+				models.FormatKeysForStatusBar(models.DownloadsStatusKeys()),
+			)
+		}
+
+		if cfg.CollectionsVisible {
+			return styles.StatusBarStyle.Padding(0).Italic(true).Render(
+				models.FormatKeysForStatusBar(models.CollectionsStatusKeys(m.Search.CollectionsList.Open != "")),
+			)
+		}
+
+		if m.Search.Filters.Visible {
+			return styles.StatusBarStyle.Padding(0).Italic(true).Render(
+				models.FormatKeysForStatusBar(models.FilterPanelStatusKeys()),
+			)
+		}
+
 		return models.FormatKeysForStatusBar(models.StatusKeys{
 			Quit:         cfg.Keys.Quit,
+			Filters:      cfg.Keys.Filters,
 			StarOnGithub: cfg.Keys.StarOnGithub,
 		})
 	case types.StateLoading:
 		return models.FormatKeysForStatusBar(models.LoadingStatusKeys(cfg.Keys))
 	case types.StateVideoList:
+		if m.VideoList.SavePrompt.Visible {
+			return models.FormatKeysForStatusBar(models.CollectionPromptStatusKeys())
+		}
 		if cfg.HasError {
 			return models.FormatKeysForStatusBar(models.StatusKeys{
 				Quit:  cfg.Keys.Quit,
@@ -66,24 +90,52 @@ func getStatusBarText(m *Model, cfg StatusBarConfig) string {
 						Quit:            cfg.Keys.Quit,
 						DownloadDefault: cfg.Keys.DownloadDefault,
 						Back:            cfg.Keys.Back,
+						Save:            cfg.Keys.Save,
 					})),
 			)
 		}
-		return models.FormatKeysForStatusBar(models.StatusKeys{
+		keys := models.StatusKeys{
 			Quit:            cfg.Keys.Quit,
 			Back:            cfg.Keys.Back,
 			PlayVideo:       cfg.Keys.PlayVideo,
 			DownloadDefault: cfg.Keys.DownloadDefault,
 			SelectVideos:    cfg.Keys.SelectVideos,
 			CopyURL:         cfg.Keys.CopyURL,
-		})
+			Save:            cfg.Keys.Save,
+		}
+		if m.VideoList.CanLoadMore() {
+			keys.LoadMore = cfg.Keys.LoadMore
+			if m.VideoList.IsPlaylistSearch {
+				keys.LoadAll = cfg.Keys.LoadAll
+			}
+		}
+
+		return models.FormatKeysForStatusBar(keys)
 	case types.StateFormatList:
-		return models.FormatKeysForStatusBar(models.StatusKeys{
+		if m.FormatList.ClipEditing {
+			return models.FormatKeysForStatusBar(models.ClipEditStatusKeys())
+		}
+
+		if m.FormatList.Confirming() {
+			return models.FormatKeysForStatusBar(models.ConfirmPromptStatusKeys())
+		}
+
+		if m.FormatList.SavePrompt.Visible {
+			return models.FormatKeysForStatusBar(models.CollectionPromptStatusKeys())
+		}
+
+		keys := models.StatusKeys{
 			Quit:    cfg.Keys.Quit,
 			Back:    cfg.Keys.Back,
 			Tab:     cfg.Keys.Tab,
 			CopyURL: cfg.Keys.CopyURL,
-		})
+			Save:    cfg.Keys.Save,
+		}
+		if !m.FormatList.IsQueue {
+			keys.Clip = cfg.Keys.Clip
+		}
+
+		return models.FormatKeysForStatusBar(keys)
 	case types.StateDownload:
 		if cfg.IsCompleted || cfg.IsCancelled {
 			return models.FormatKeysForStatusBar(models.StatusKeys{
@@ -100,12 +152,17 @@ func getStatusBarText(m *Model, cfg StatusBarConfig) string {
 				CopyURL: cfg.Keys.CopyURL,
 			})
 		}
-		return models.FormatKeysForStatusBar(models.StatusKeys{
+		keys := models.StatusKeys{
 			Quit:    cfg.Keys.Quit,
 			Pause:   cfg.Keys.Pause,
 			Cancel:  cfg.Keys.Cancel,
 			CopyURL: cfg.Keys.CopyURL,
-		})
+		}
+		if m.Download.Recording() && !m.Download.StoppingRecording {
+			keys.StopRecording = cfg.Keys.StopRecording
+		}
+
+		return models.FormatKeysForStatusBar(keys)
 	case types.StateVideoPlaying:
 		return models.FormatKeysForStatusBar(models.StatusKeys{
 			Quit: cfg.Keys.Quit,
@@ -147,6 +204,8 @@ func (m *Model) View() string {
 		IsCancelled:         m.Download.Cancelled,
 		Keys:                models.GetStatusKeys(m.State, m.Search.ResumeList.Visible),
 		ResumeVisible:       m.Search.ResumeList.Visible,
+		DownloadsVisible:    m.Search.DownloadsList.Visible,
+		CollectionsVisible:  m.Search.CollectionsList.Visible,
 		SelectedVideosCount: len(m.VideoList.SelectedVideos),
 		ShowFormatSelect:    false,
 	}
@@ -201,10 +260,17 @@ func (m *Model) LoadingView() string {
 		loadingText = "Loading formats..."
 	case "channel":
 		loadingText = "Loading videos for channel " + styles.SpinnerStyle.Render("@"+m.VideoList.ChannelName)
+	case "channel_tab":
+		tab := strings.ToLower(m.VideoList.LoadingTab.DisplayName())
+		loadingText = "Loading " + tab + " for channel " + styles.SpinnerStyle.Render("@"+m.VideoList.ChannelName)
 	case "playlist":
 		loadingText = fmt.Sprintf("Searching playlist: %s", styles.SpinnerStyle.Render(m.CurrentQuery))
 	case "queue":
 		loadingText = "Starting queue download..."
+	case "feed":
+		loadingText = "Checking subscriptions for new uploads..."
+	case "import":
+		loadingText = "Importing URLs from " + styles.SpinnerStyle.Render(m.CurrentQuery)
 	case "video_playing":
 		loadingText = fmt.Sprintf("Starting mpv for: %s", m.Player.Video.Title())
 	}
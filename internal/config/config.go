@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/xdagiz/xytz/internal/paths"
 	"gopkg.in/yaml.v3"
@@ -13,19 +14,36 @@ import (
 const ConfigFileName = "config.yaml"
 
 type Config struct {
-	SearchLimit         int    `yaml:"search_limit"`
-	DefaultDownloadPath string `yaml:"default_download_path"`
-	DefaultQuality      string `yaml:"default_quality"`
-	SortByDefault       string `yaml:"sort_by_default"`
-	EmbedSubtitles      bool   `yaml:"embed_subtitles"`
-	EmbedMetadata       bool   `yaml:"embed_metadata"`
-	EmbedChapters       bool   `yaml:"embed_chapters"`
-	FFmpegPath          string `yaml:"ffmpeg_path"`
-	YTDLPPath           string `yaml:"yt_dlp_path"`
-	VideoFormat         string `yaml:"video_format"`
-	AudioFormat         string `yaml:"audio_format"`
-	CookiesBrowser      string `yaml:"cookies_browser"`
-	CookiesFile         string `yaml:"cookies_file"`
+	SearchLimit            int      `yaml:"search_limit"`
+	DefaultDownloadPath    string   `yaml:"default_download_path"`
+	DefaultQuality         string   `yaml:"default_quality"`
+	SortByDefault          string   `yaml:"sort_by_default"`
+	FilterUploadDate       string   `yaml:"filter_upload_date"`
+	FilterDuration         string   `yaml:"filter_duration"`
+	FilterType             string   `yaml:"filter_type"`
+	FilterFeatures         []string `yaml:"filter_features"`
+	EmbedSubtitles         bool     `yaml:"embed_subtitles"`
+	EmbedMetadata          bool     `yaml:"embed_metadata"`
+	EmbedChapters          bool     `yaml:"embed_chapters"`
+	EmbedThumbnail         bool     `yaml:"embed_thumbnail"`
+	LiveFromStart          bool     `yaml:"live_from_start"`
+	ThumbnailFormat        string   `yaml:"thumbnail_format"`
+	FFmpegPath             string   `yaml:"ffmpeg_path"`
+	YTDLPPath              string   `yaml:"yt_dlp_path"`
+	VideoFormat            string   `yaml:"video_format"`
+	AudioFormat            string   `yaml:"audio_format"`
+	CookiesBrowser         string   `yaml:"cookies_browser"`
+	CookiesFile            string   `yaml:"cookies_file"`
+	MaxConcurrentDownloads int      `yaml:"max_concurrent_downloads"`
+	VideoOutputTemplate    string   `yaml:"video_output_template"`
+	AudioOutputTemplate    string   `yaml:"audio_output_template"`
+	OrganizeBy             string   `yaml:"organize_by"`
+	RateLimit              string   `yaml:"rate_limit"`
+	PostDownloadHooks      []string `yaml:"post_download_hooks"`
+	HookTimeout            int      `yaml:"post_download_hook_timeout"`
+	SponsorBlockMode       string   `yaml:"sponsorblock_mode"`
+	SponsorBlockCategories []string `yaml:"sponsorblock_categories"`
+	SponsorBlockAPI        string   `yaml:"sponsorblock_api"`
 }
 
 var GetConfigDir = func() string {
@@ -61,6 +79,7 @@ func Load() (*Config, error) {
 	}
 
 	cfg.applyDefaults()
+	cfg.validateTemplates()
 
 	return &cfg, nil
 }
@@ -106,6 +125,47 @@ func (c *Config) applyDefaults() {
 	if c.AudioFormat == "" {
 		c.AudioFormat = defaults.AudioFormat
 	}
+
+	if c.MaxConcurrentDownloads <= 0 {
+		c.MaxConcurrentDownloads = defaults.MaxConcurrentDownloads
+	}
+
+	if c.HookTimeout <= 0 {
+		c.HookTimeout = defaults.HookTimeout
+	}
+
+	if c.VideoOutputTemplate == "" {
+		c.VideoOutputTemplate = defaults.VideoOutputTemplate
+	}
+
+	if c.AudioOutputTemplate == "" {
+		c.AudioOutputTemplate = defaults.AudioOutputTemplate
+	}
+
+	if !IsValidOrganizeMode(c.OrganizeBy) {
+		if c.OrganizeBy != "" {
+			log.Printf("Warning: Unknown organize_by %q, using %q", c.OrganizeBy, defaults.OrganizeBy)
+		}
+		c.OrganizeBy = defaults.OrganizeBy
+	}
+
+	if c.ThumbnailFormat == "jpeg" {
+		c.ThumbnailFormat = ThumbnailJPG
+	}
+
+	if !IsValidThumbnailFormat(c.ThumbnailFormat) {
+		if c.ThumbnailFormat != "" {
+			log.Printf("Warning: Unknown thumbnail_format %q, using %q", c.ThumbnailFormat, defaults.ThumbnailFormat)
+		}
+		c.ThumbnailFormat = defaults.ThumbnailFormat
+	}
+
+	if err := ValidateRateLimit(c.RateLimit); err != nil {
+		log.Printf("Warning: %v, downloading without a limit", err)
+		c.RateLimit = defaults.RateLimit
+	}
+
+	c.validateSponsorBlock()
 }
 
 func (c *Config) GetDefaultFormat() string {
@@ -126,3 +186,13 @@ func (c *Config) ExpandPath(path string) string {
 func (c *Config) GetDownloadPath() string {
 	return c.ExpandPath(c.DefaultDownloadPath)
 }
+
+// GetHookTimeout is how long a post-download hook may run before it's killed.
+func (c *Config) GetHookTimeout() time.Duration {
+	seconds := c.HookTimeout
+	if seconds <= 0 {
+		seconds = GetDefault().HookTimeout
+	}
+
+	return time.Duration(seconds) * time.Second
+}
@@ -201,3 +201,24 @@ func TestGetDownloadPath(t *testing.T) {
 		t.Error("GetDownloadPath() returned empty string")
 	}
 }
+
+func TestApplyDefaultsThumbnailFormat(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{format: "", want: ThumbnailJPG},
+		{format: "png", want: ThumbnailPNG},
+		{format: "webp", want: ThumbnailWEBP},
+		{format: "jpeg", want: ThumbnailJPG},
+		{format: "gif", want: ThumbnailJPG},
+	}
+
+	for _, tt := range tests {
+		cfg := &Config{ThumbnailFormat: tt.format}
+		cfg.applyDefaults()
+		if cfg.ThumbnailFormat != tt.want {
+			t.Errorf("applyDefaults() ThumbnailFormat for %q = %q, want %q", tt.format, cfg.ThumbnailFormat, tt.want)
+		}
+	}
+}
@@ -2,16 +2,27 @@ package config
 
 func GetDefault() *Config {
 	return &Config{
-		SearchLimit:         25,
-		DefaultDownloadPath: "~/Videos",
-		DefaultQuality:      "best",
-		SortByDefault:       "relevance",
-		EmbedSubtitles:      false,
-		EmbedMetadata:       true,
-		EmbedChapters:       true,
-		VideoFormat:         "mp4",
-		AudioFormat:         "mp3",
-		CookiesBrowser:      "",
-		CookiesFile:         "",
+		SearchLimit:            25,
+		DefaultDownloadPath:    "~/Videos",
+		DefaultQuality:         "best",
+		SortByDefault:          "relevance",
+		EmbedSubtitles:         false,
+		EmbedMetadata:          true,
+		EmbedChapters:          true,
+		EmbedThumbnail:         false,
+		LiveFromStart:          false,
+		ThumbnailFormat:        ThumbnailJPG,
+		VideoFormat:            "mp4",
+		AudioFormat:            "mp3",
+		CookiesBrowser:         "",
+		CookiesFile:            "",
+		MaxConcurrentDownloads: 1,
+		VideoOutputTemplate:    "%(title)s.%(ext)s",
+		AudioOutputTemplate:    "%(artist)s - %(title)s.%(ext)s",
+		OrganizeBy:             OrganizeFlat,
+		RateLimit:              "",
+		HookTimeout:            3600,
+		SponsorBlockMode:       SponsorBlockOff,
+		SponsorBlockCategories: []string{"sponsor", "selfpromo", "interaction"},
 	}
 }
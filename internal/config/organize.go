@@ -0,0 +1,20 @@
+package config
+
+const (
+	OrganizeFlat     = "flat"
+	OrganizeChannel  = "channel"
+	OrganizePlaylist = "playlist"
+	OrganizeQueue    = "queue"
+)
+
+var OrganizeModes = []string{OrganizeFlat, OrganizeChannel, OrganizePlaylist, OrganizeQueue}
+
+func IsValidOrganizeMode(mode string) bool {
+	for _, m := range OrganizeModes {
+		if m == mode {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var rateLimitRegex = regexp.MustCompile(`^\d+(\.\d+)?[KMG]?$`)
+
+// RateLimitPresets are cycled through on the download screen. An empty value
+// means unlimited.
+var RateLimitPresets = []string{"", "1M", "5M"}
+
+func ValidateRateLimit(limit string) error {
+	if limit == "" {
+		return nil
+	}
+
+	if !rateLimitRegex.MatchString(strings.ToUpper(limit)) {
+		return fmt.Errorf("invalid rate limit %q, expected a value like 500K or 5M", limit)
+	}
+
+	return nil
+}
+
+func NextRateLimit(current string) string {
+	for i, preset := range RateLimitPresets {
+		if strings.EqualFold(preset, current) {
+			return RateLimitPresets[(i+1)%len(RateLimitPresets)]
+		}
+	}
+
+	return RateLimitPresets[0]
+}
+
+func RateLimitDisplay(limit string) string {
+	if limit == "" {
+		return "unlimited"
+	}
+
+	return limit + "/s"
+}
@@ -0,0 +1,37 @@
+package config
+
+import "testing"
+
+func TestValidateRateLimit(t *testing.T) {
+	valid := []string{"", "500K", "1M", "1.5M", "2G", "4096", "5m"}
+	for _, limit := range valid {
+		if err := ValidateRateLimit(limit); err != nil {
+			t.Errorf("ValidateRateLimit(%q) error = %v, want nil", limit, err)
+		}
+	}
+
+	invalid := []string{"fast", "1 M", "M", "-1M", "5MB"}
+	for _, limit := range invalid {
+		if err := ValidateRateLimit(limit); err == nil {
+			t.Errorf("ValidateRateLimit(%q) error = nil, want error", limit)
+		}
+	}
+}
+
+func TestNextRateLimit(t *testing.T) {
+	tests := []struct {
+		current string
+		want    string
+	}{
+		{current: "", want: "1M"},
+		{current: "1M", want: "5M"},
+		{current: "5M", want: ""},
+		{current: "2M", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := NextRateLimit(tt.current); got != tt.want {
+			t.Errorf("NextRateLimit(%q) = %q, want %q", tt.current, got, tt.want)
+		}
+	}
+}
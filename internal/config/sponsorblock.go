@@ -0,0 +1,89 @@
+package config
+
+import (
+	"log"
+	"slices"
+	"strings"
+)
+
+const (
+	SponsorBlockOff    = ""
+	SponsorBlockMark   = "mark"
+	SponsorBlockRemove = "remove"
+)
+
+// SponsorBlockModes are cycled through by the SponsorBlock download option,
+// after the off state.
+var SponsorBlockModes = []string{SponsorBlockMark, SponsorBlockRemove}
+
+// SponsorBlockCategories are the segment categories yt-dlp understands.
+var SponsorBlockCategories = []string{
+	"sponsor",
+	"intro",
+	"outro",
+	"selfpromo",
+	"preview",
+	"filler",
+	"interaction",
+	"music_offtopic",
+	"poi_highlight",
+	"chapter",
+	"default",
+	"all",
+}
+
+// sponsorBlockMarkOnly are categories yt-dlp can mark but not remove.
+var sponsorBlockMarkOnly = []string{"poi_highlight", "chapter"}
+
+func IsValidSponsorBlockMode(mode string) bool {
+	if mode == SponsorBlockOff {
+		return true
+	}
+
+	for _, m := range SponsorBlockModes {
+		if m == mode {
+			return true
+		}
+	}
+
+	return false
+}
+
+func IsValidSponsorBlockCategory(category string) bool {
+	for _, c := range SponsorBlockCategories {
+		if c == category {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SponsorBlockRemovable reports whether yt-dlp accepts category for
+// --sponsorblock-remove.
+func SponsorBlockRemovable(category string) bool {
+	return !slices.Contains(sponsorBlockMarkOnly, category)
+}
+
+func (c *Config) validateSponsorBlock() {
+	defaults := GetDefault()
+	if !IsValidSponsorBlockMode(c.SponsorBlockMode) {
+		log.Printf("Warning: Unknown sponsorblock_mode %q, SponsorBlock is off", c.SponsorBlockMode)
+		c.SponsorBlockMode = SponsorBlockOff
+	}
+
+	var categories []string
+	for _, category := range c.SponsorBlockCategories {
+		category = strings.ToLower(strings.TrimSpace(category))
+		if !IsValidSponsorBlockCategory(category) {
+			log.Printf("Warning: Ignoring unknown SponsorBlock category %q", category)
+			continue
+		}
+		categories = append(categories, category)
+	}
+
+	if len(categories) == 0 {
+		categories = defaults.SponsorBlockCategories
+	}
+	c.SponsorBlockCategories = categories
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidateSponsorBlock(t *testing.T) {
+	tests := []struct {
+		name           string
+		mode           string
+		categories     []string
+		wantMode       string
+		wantCategories []string
+	}{
+		{
+			name:           "keeps valid settings",
+			mode:           SponsorBlockRemove,
+			categories:     []string{"sponsor", "music_offtopic"},
+			wantMode:       SponsorBlockRemove,
+			wantCategories: []string{"sponsor", "music_offtopic"},
+		},
+		{
+			name:           "drops unknown categories",
+			mode:           SponsorBlockMark,
+			categories:     []string{" Sponsor ", "ads"},
+			wantMode:       SponsorBlockMark,
+			wantCategories: []string{"sponsor"},
+		},
+		{
+			name:           "unknown mode turns SponsorBlock off",
+			mode:           "skip",
+			categories:     []string{"intro"},
+			wantMode:       SponsorBlockOff,
+			wantCategories: []string{"intro"},
+		},
+		{
+			name:           "empty categories use defaults",
+			mode:           SponsorBlockMark,
+			wantMode:       SponsorBlockMark,
+			wantCategories: GetDefault().SponsorBlockCategories,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := GetDefault()
+			cfg.SponsorBlockMode = tt.mode
+			cfg.SponsorBlockCategories = tt.categories
+
+			cfg.validateSponsorBlock()
+
+			if cfg.SponsorBlockMode != tt.wantMode {
+				t.Fatalf("SponsorBlockMode = %q, want %q", cfg.SponsorBlockMode, tt.wantMode)
+			}
+			if !reflect.DeepEqual(cfg.SponsorBlockCategories, tt.wantCategories) {
+				t.Fatalf("SponsorBlockCategories = %v, want %v", cfg.SponsorBlockCategories, tt.wantCategories)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// OutputTemplateFieldRegex matches a yt-dlp output template field like
+// %(title).50s, capturing the field expression and the precision.
+var OutputTemplateFieldRegex = regexp.MustCompile(`%\(([^()]*)\)[-#0 +]*\d*(?:\.(\d+))?[diouxXeEfFgGcrsaqjlBDSU]`)
+
+func ValidateOutputTemplate(tmpl string) error {
+	if strings.TrimSpace(tmpl) == "" {
+		return errors.New("template is empty")
+	}
+
+	if filepath.IsAbs(tmpl) {
+		return errors.New("template must be relative to the download path")
+	}
+
+	for _, part := range strings.FieldsFunc(tmpl, func(r rune) bool { return r == '/' || r == '\\' }) {
+		if part == ".." {
+			return errors.New("template must not leave the download path")
+		}
+	}
+
+	rest := strings.ReplaceAll(tmpl, "%%", "")
+	for _, match := range OutputTemplateFieldRegex.FindAllStringSubmatch(rest, -1) {
+		if strings.TrimSpace(match[1]) == "" {
+			return errors.New("template has an empty field name")
+		}
+	}
+
+	rest = OutputTemplateFieldRegex.ReplaceAllString(rest, "")
+	if strings.Contains(rest, "%(") {
+		return fmt.Errorf("template has an unterminated field in %q", tmpl)
+	}
+
+	if !strings.Contains(tmpl, "%(ext)s") {
+		return errors.New("template must contain %(ext)s")
+	}
+
+	return nil
+}
+
+func (c *Config) validateTemplates() {
+	defaults := GetDefault()
+	if err := ValidateOutputTemplate(c.VideoOutputTemplate); err != nil {
+		log.Printf("Warning: Invalid video_output_template %q: %v, using default", c.VideoOutputTemplate, err)
+		c.VideoOutputTemplate = defaults.VideoOutputTemplate
+	}
+
+	if err := ValidateOutputTemplate(c.AudioOutputTemplate); err != nil {
+		log.Printf("Warning: Invalid audio_output_template %q: %v, using default", c.AudioOutputTemplate, err)
+		c.AudioOutputTemplate = defaults.AudioOutputTemplate
+	}
+}
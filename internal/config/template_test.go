@@ -0,0 +1,66 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateOutputTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		tmpl    string
+		wantErr bool
+	}{
+		{name: "default video", tmpl: "%(title)s.%(ext)s"},
+		{name: "team template", tmpl: "%(upload_date)s - %(channel)s - %(title)s [%(id)s].%(ext)s"},
+		{name: "subdirectory and precision", tmpl: "%(channel)s/%(title).50s.%(ext)s"},
+		{name: "literal percent", tmpl: "100%% %(title)s.%(ext)s"},
+		{name: "empty", tmpl: "  ", wantErr: true},
+		{name: "missing ext", tmpl: "%(title)s", wantErr: true},
+		{name: "unterminated field", tmpl: "%(title.%(ext)s", wantErr: true},
+		{name: "missing conversion", tmpl: "%(title).%(ext)s", wantErr: true},
+		{name: "empty field name", tmpl: "%()s.%(ext)s", wantErr: true},
+		{name: "absolute path", tmpl: "/tmp/%(title)s.%(ext)s", wantErr: true},
+		{name: "parent directory", tmpl: "../%(title)s.%(ext)s", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateOutputTemplate(tt.tmpl)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateOutputTemplate(%q) error = %v, wantErr %v", tt.tmpl, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadReplacesInvalidTemplates(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	originalConfigDir := GetConfigDir
+	defer func() { GetConfigDir = originalConfigDir }()
+
+	GetConfigDir = func() string {
+		return tmpDir
+	}
+
+	content := `video_output_template: "%(title)s"
+audio_output_template: "%(channel)s/%(title)s.%(ext)s"
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, ConfigFileName), []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.VideoOutputTemplate != GetDefault().VideoOutputTemplate {
+		t.Errorf("Load() VideoOutputTemplate = %q, want default", cfg.VideoOutputTemplate)
+	}
+	if cfg.AudioOutputTemplate != "%(channel)s/%(title)s.%(ext)s" {
+		t.Errorf("Load() AudioOutputTemplate = %q, want it kept", cfg.AudioOutputTemplate)
+	}
+}
@@ -0,0 +1,19 @@
+package config
+
+const (
+	ThumbnailJPG  = "jpg"
+	ThumbnailPNG  = "png"
+	ThumbnailWEBP = "webp"
+)
+
+var ThumbnailFormats = []string{ThumbnailJPG, ThumbnailPNG, ThumbnailWEBP}
+
+func IsValidThumbnailFormat(format string) bool {
+	for _, f := range ThumbnailFormats {
+		if f == format {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,239 @@
+// Package headless runs xytz's downloads without the TUI, for scripts and
+// cron jobs.
+package headless
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/xdagiz/xytz/internal/config"
+	"github.com/xdagiz/xytz/internal/types"
+	"github.com/xdagiz/xytz/internal/utils"
+)
+
+// Exit codes of a headless run.
+const (
+	ExitOK        = 0
+	ExitFailed    = 1
+	ExitPartial   = 2
+	ExitCancelled = 130
+)
+
+type DownloadOptions struct {
+	// Inputs are video or playlist URLs, or files listing one per line.
+	// "-" reads the list from standard input.
+	Inputs             []string
+	Quality            string
+	Audio              bool
+	OutputDir          string
+	JSON               bool
+	CookiesFromBrowser string
+	Cookies            string
+}
+
+// Download downloads every input in turn and returns the exit code for the
+// run. Cancelling ctx stops the current download and skips the rest.
+func Download(ctx context.Context, opts DownloadOptions, stdin io.Reader, stdout io.Writer) int {
+	out := newReporter(stdout, opts.JSON)
+
+	urls, err := readInputs(opts.Inputs, stdin)
+	if err != nil {
+		out.fatal(err)
+		return ExitFailed
+	}
+
+	if len(urls) == 0 {
+		out.fatal(fmt.Errorf("nothing to download"))
+		return ExitFailed
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Printf("Warning: Failed to load config, using defaults: %v", err)
+		cfg = config.GetDefault()
+	}
+
+	formatID := config.ResolveQuality(opts.Quality)
+	if opts.Quality == "" {
+		formatID = cfg.GetDefaultFormat()
+	}
+	if opts.Audio {
+		formatID = "bestaudio/best"
+	}
+
+	outputDir := opts.OutputDir
+	if outputDir != "" {
+		outputDir = cfg.ExpandPath(outputDir)
+	}
+
+	dm := utils.NewDownloadManager()
+	stop := context.AfterFunc(ctx, func() {
+		if err := dm.Cancel(); err != nil {
+			log.Printf("Failed to cancel download: %v", err)
+		}
+	})
+	defer stop()
+
+	var succeeded, failed int
+	for i, input := range urls {
+		if ctx.Err() != nil {
+			break
+		}
+
+		out.start(i+1, len(urls), input)
+
+		url, video, err := resolveURL(input)
+		if err != nil {
+			out.failed(err.Error())
+			failed++
+			continue
+		}
+
+		req := types.DownloadRequest{
+			URL:                url,
+			FormatID:           formatID,
+			IsAudioTab:         opts.Audio,
+			Title:              input,
+			OutputDir:          outputDir,
+			Options:            utils.ConfigDownloadOptions(cfg),
+			CookiesFromBrowser: opts.CookiesFromBrowser,
+			Cookies:            opts.Cookies,
+		}
+
+		utils.Download(dm, out, req, cfg)
+
+		result := out.result()
+		switch {
+		case ctx.Err() != nil:
+			out.cancelled()
+		case result.Err != "":
+			out.failed(result.Err)
+			failed++
+		default:
+			out.done(result.Destination)
+			succeeded++
+			finishDownload(cfg, video, result.Destination, formatID, opts.Audio, out)
+		}
+	}
+
+	cancelled := ctx.Err() != nil
+	out.summary(succeeded, failed, len(urls)-succeeded-failed, cancelled)
+
+	switch {
+	case cancelled:
+		return ExitCancelled
+	case failed == 0:
+		return ExitOK
+	case succeeded == 0:
+		return ExitFailed
+	default:
+		return ExitPartial
+	}
+}
+
+// readInputs expands the inputs that are files into the URLs they list.
+func readInputs(inputs []string, stdin io.Reader) ([]string, error) {
+	var urls []string
+	for _, input := range inputs {
+		input = strings.TrimSpace(input)
+		switch {
+		case input == "":
+			continue
+
+		case input == "-":
+			listed, err := utils.ReadURLList(stdin)
+			if err != nil {
+				return nil, fmt.Errorf("read standard input: %w", err)
+			}
+			urls = append(urls, listed...)
+
+		case isFile(input):
+			f, err := os.Open(input)
+			if err != nil {
+				return nil, err
+			}
+
+			listed, err := utils.ReadURLList(f)
+			f.Close()
+			if err != nil {
+				return nil, fmt.Errorf("read %s: %w", input, err)
+			}
+			urls = append(urls, listed...)
+
+		default:
+			urls = append(urls, input)
+		}
+	}
+
+	return urls, nil
+}
+
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// resolveURL turns an input into the URL to download. Single videos also
+// come back as a VideoItem so they can be archived and recorded.
+func resolveURL(input string) (string, types.VideoItem, error) {
+	urlType, url := utils.ParseSearchQuery(input)
+	switch urlType {
+	case "video":
+		return url, types.VideoItem{ID: utils.ExtractVideoID(input)}, nil
+	case "playlist":
+		return url, types.VideoItem{}, nil
+	}
+
+	return "", types.VideoItem{}, fmt.Errorf("not a video or playlist URL: %s", input)
+}
+
+// finishDownload does what the TUI does after a download completes: it
+// fills the archive and the downloads log, and runs the post-download
+// hooks.
+func finishDownload(cfg *config.Config, video types.VideoItem, destination, formatID string, isAudio bool, out *reporter) {
+	var title string
+	if destination != "" {
+		title = strings.TrimSuffix(filepath.Base(destination), filepath.Ext(destination))
+	}
+
+	if video.ID != "" {
+		if err := utils.AddToArchive(video.ID); err != nil {
+			log.Printf("Failed to update download archive: %v", err)
+		}
+	}
+
+	if destination == "" {
+		log.Printf("Warning: yt-dlp reported no destination, skipping the downloads log and post-download hooks")
+		return
+	}
+
+	if video.ID != "" {
+		download := utils.CompletedDownload{
+			VideoID:     video.ID,
+			Title:       title,
+			FormatID:    formatID,
+			IsAudio:     isAudio,
+			Destination: destination,
+		}
+		if err := utils.AddDownload(download); err != nil {
+			log.Printf("Failed to record completed download: %v", err)
+		}
+	}
+
+	env := utils.HookEnv{
+		File:     destination,
+		VideoID:  video.ID,
+		Title:    title,
+		FormatID: formatID,
+	}
+	if cmd := utils.RunPostDownloadHooks(cfg.PostDownloadHooks, env, 0, cfg.GetHookTimeout()); cmd != nil {
+		if msg, ok := cmd().(types.HookResultMsg); ok {
+			out.hooks(msg.Results)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package headless
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/xdagiz/xytz/internal/config"
+	"github.com/xdagiz/xytz/internal/utils"
+)
+
+const (
+	okURL   = "https://www.youtube.com/watch?v=aaaaaaaaaaa"
+	failURL = "https://www.youtube.com/watch?v=failfailfai"
+)
+
+// setupFakeDownload points the config, archive and downloads log at a temp
+// dir and makes yt-dlp a script that fails for video IDs starting with
+// "fail". It returns the file the script writes its arguments to.
+func setupFakeDownload(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	origConfigDir := config.GetConfigDir
+	config.GetConfigDir = func() string { return filepath.Join(dir, "config") }
+	origArchive := utils.GetArchiveFilePath
+	utils.GetArchiveFilePath = func() string { return filepath.Join(dir, "archive.txt") }
+	origDownloads := utils.GetDownloadsFilePath
+	utils.GetDownloadsFilePath = func() string { return filepath.Join(dir, "downloads.json") }
+	t.Cleanup(func() {
+		config.GetConfigDir = origConfigDir
+		utils.GetArchiveFilePath = origArchive
+		utils.GetDownloadsFilePath = origDownloads
+	})
+
+	argsPath := filepath.Join(dir, "args.txt")
+	script := `#!/usr/bin/env bash
+printf '%s\n' "$@" >> "` + argsPath + `"
+case "$*" in
+  *v=fail*) echo "ERROR: [youtube] Video unavailable" >&2; exit 1 ;;
+esac
+echo "[download] Destination: /tmp/Some Video.mp4"
+echo "[download]  50.0% of 10.00MiB at 2.00MiB/s ETA 00:03"
+echo "[download] 100.0% of 10.00MiB at 2.00MiB/s ETA 00:00"
+`
+	scriptPath := filepath.Join(dir, "fake-yt-dlp.sh")
+	if err := os.WriteFile(scriptPath, []byte(script), 0o755); err != nil {
+		t.Fatalf("write fake yt-dlp: %v", err)
+	}
+
+	cfg := config.GetDefault()
+	cfg.YTDLPPath = scriptPath
+	cfg.DefaultDownloadPath = filepath.Join(dir, "downloads")
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	return argsPath
+}
+
+func TestDownloadExitCodes(t *testing.T) {
+	tests := []struct {
+		name   string
+		inputs []string
+		want   int
+	}{
+		{"all succeed", []string{okURL}, ExitOK},
+		{"some fail", []string{okURL, failURL}, ExitPartial},
+		{"all fail", []string{failURL}, ExitFailed},
+		{"not a URL", []string{"some search query"}, ExitFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupFakeDownload(t)
+
+			var out bytes.Buffer
+			got := Download(context.Background(), DownloadOptions{Inputs: tt.inputs}, strings.NewReader(""), &out)
+			if got != tt.want {
+				t.Fatalf("exit code = %d, want %d\noutput:\n%s", got, tt.want, out.String())
+			}
+		})
+	}
+}
+
+func TestDownloadCancelled(t *testing.T) {
+	setupFakeDownload(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var out bytes.Buffer
+	if got := Download(ctx, DownloadOptions{Inputs: []string{okURL}}, strings.NewReader(""), &out); got != ExitCancelled {
+		t.Fatalf("exit code = %d, want %d", got, ExitCancelled)
+	}
+	if !strings.Contains(out.String(), "0 downloaded, 0 failed, 1 skipped (cancelled)") {
+		t.Fatalf("unexpected summary:\n%s", out.String())
+	}
+}
+
+func TestDownloadPassesQualityAndOutput(t *testing.T) {
+	argsPath := setupFakeDownload(t)
+	outputDir := t.TempDir()
+
+	var out bytes.Buffer
+	opts := DownloadOptions{Inputs: []string{okURL}, Quality: "720p", OutputDir: outputDir}
+	if got := Download(context.Background(), opts, strings.NewReader(""), &out); got != ExitOK {
+		t.Fatalf("exit code = %d, want %d\noutput:\n%s", got, ExitOK, out.String())
+	}
+
+	data, err := os.ReadFile(argsPath)
+	if err != nil {
+		t.Fatalf("read args: %v", err)
+	}
+	args := strings.Split(strings.TrimSpace(string(data)), "\n")
+
+	i := slices.Index(args, "-f")
+	if i < 0 || args[i+1] != config.ResolveQuality("720p") {
+		t.Fatalf("expected -f %s in args: %v", config.ResolveQuality("720p"), args)
+	}
+	if !slices.ContainsFunc(args, func(arg string) bool { return strings.HasPrefix(arg, outputDir) }) {
+		t.Fatalf("expected output dir %s in args: %v", outputDir, args)
+	}
+
+	if !strings.Contains(out.String(), "[1/1] done: /tmp/Some Video.mp4") {
+		t.Fatalf("unexpected output:\n%s", out.String())
+	}
+
+	if !utils.IsArchived("aaaaaaaaaaa") {
+		t.Fatalf("expected the video to be archived")
+	}
+}
+
+func TestDownloadJSONOutput(t *testing.T) {
+	setupFakeDownload(t)
+
+	var out bytes.Buffer
+	opts := DownloadOptions{Inputs: []string{okURL, failURL}, JSON: true}
+	if got := Download(context.Background(), opts, strings.NewReader(""), &out); got != ExitPartial {
+		t.Fatalf("exit code = %d, want %d", got, ExitPartial)
+	}
+
+	var events []Event
+	for line := range strings.Lines(out.String()) {
+		var event Event
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("line is not JSON: %q", line)
+		}
+		events = append(events, event)
+	}
+
+	var names []string
+	var progress []Event
+	for _, event := range events {
+		if event.Event == "progress" {
+			progress = append(progress, event)
+			continue
+		}
+		names = append(names, event.Event)
+	}
+	want := []string{"start", "done", "start", "error", "summary"}
+	if !slices.Equal(names, want) {
+		t.Fatalf("events = %v, want %v", names, want)
+	}
+
+	if len(progress) == 0 || progress[len(progress)-1].Percent != 100 {
+		t.Fatalf("expected progress up to 100%%, got %+v", progress)
+	}
+	if done := events[slices.IndexFunc(events, func(e Event) bool { return e.Event == "done" })]; done.Destination != "/tmp/Some Video.mp4" || done.Index != 1 || done.Total != 2 {
+		t.Fatalf("unexpected done event: %+v", done)
+	}
+	if failed := events[len(events)-2]; failed.URL != failURL || failed.Error == "" {
+		t.Fatalf("unexpected error event: %+v", failed)
+	}
+	if last := events[len(events)-1]; last.Succeeded != 1 || last.Failed != 1 {
+		t.Fatalf("unexpected summary: %+v", last)
+	}
+}
+
+func TestReadInputs(t *testing.T) {
+	list := filepath.Join(t.TempDir(), "urls.txt")
+	content := "# watch later\n" + okURL + "\n\n  " + failURL + "  \n"
+	if err := os.WriteFile(list, []byte(content), 0o644); err != nil {
+		t.Fatalf("write list: %v", err)
+	}
+
+	got, err := readInputs([]string{"https://youtu.be/bbbbbbbbbbb", list, "-"}, strings.NewReader("https://youtu.be/ccccccccccc\n"))
+	if err != nil {
+		t.Fatalf("readInputs: %v", err)
+	}
+
+	want := []string{"https://youtu.be/bbbbbbbbbbb", okURL, failURL, "https://youtu.be/ccccccccccc"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("readInputs = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,242 @@
+package headless
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/xdagiz/xytz/internal/types"
+	"github.com/xdagiz/xytz/internal/utils"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Event is one line of --json output.
+type Event struct {
+	Event       string  `json:"event"`
+	Index       int     `json:"index,omitempty"`
+	Total       int     `json:"total,omitempty"`
+	URL         string  `json:"url,omitempty"`
+	Percent     float64 `json:"percent,omitempty"`
+	Speed       string  `json:"speed,omitempty"`
+	ETA         string  `json:"eta,omitempty"`
+	Stage       string  `json:"stage,omitempty"`
+	Downloaded  int64   `json:"downloaded_bytes,omitempty"`
+	TotalBytes  int64   `json:"total_bytes,omitempty"`
+	Destination string  `json:"destination,omitempty"`
+	Command     string  `json:"command,omitempty"`
+	ExitCode    int     `json:"exit_code,omitempty"`
+	Error       string  `json:"error,omitempty"`
+	Succeeded   int     `json:"succeeded,omitempty"`
+	Failed      int     `json:"failed,omitempty"`
+	Skipped     int     `json:"skipped,omitempty"`
+	Cancelled   bool    `json:"cancelled,omitempty"`
+}
+
+// reporter is the utils.Sender of a headless download. It writes progress
+// as plain or JSON lines, one whenever the whole percentage or the stage
+// changes, and keeps the download's result.
+type reporter struct {
+	mu   sync.Mutex
+	w    io.Writer
+	json bool
+
+	index, total int
+	url          string
+	lastPercent  int
+	lastStage    string
+	last         types.DownloadResultMsg
+}
+
+func newReporter(w io.Writer, json bool) *reporter {
+	return &reporter{w: w, json: json}
+}
+
+func (r *reporter) Send(msg tea.Msg) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch msg := msg.(type) {
+	case types.ProgressMsg:
+		percent := int(msg.Percent)
+		if percent == r.lastPercent && msg.Stage == r.lastStage {
+			return
+		}
+		r.lastPercent = percent
+		r.lastStage = msg.Stage
+
+		if r.json {
+			r.writeJSON(Event{
+				Event:       "progress",
+				Percent:     msg.Percent,
+				Speed:       msg.Speed,
+				ETA:         msg.Eta,
+				Stage:       msg.Stage,
+				Downloaded:  msg.DownloadedBytes,
+				TotalBytes:  msg.TotalBytes,
+				Destination: msg.Destination,
+			})
+			return
+		}
+
+		r.writeLine(progressText(msg))
+
+	case types.DownloadResultMsg:
+		r.last = msg
+	}
+}
+
+func progressText(msg types.ProgressMsg) string {
+	if msg.Stage != "" {
+		return types.StageDisplayName(msg.Stage)
+	}
+
+	parts := []string{fmt.Sprintf("%.1f%%", msg.Percent)}
+	if msg.TotalBytes > 0 {
+		parts = append(parts, "of "+utils.FormatBytes(msg.TotalBytes))
+	}
+	if msg.Speed != "" {
+		parts = append(parts, "at "+msg.Speed)
+	}
+	if msg.Eta != "" {
+		parts = append(parts, "ETA "+msg.Eta)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func (r *reporter) start(index, total int, url string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.index, r.total, r.url = index, total, url
+	r.lastPercent, r.lastStage = -1, ""
+	r.last = types.DownloadResultMsg{}
+
+	if r.json {
+		r.writeJSON(Event{Event: "start"})
+		return
+	}
+
+	r.writeLine("downloading " + url)
+}
+
+func (r *reporter) result() types.DownloadResultMsg {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.last
+}
+
+func (r *reporter) done(destination string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.json {
+		r.writeJSON(Event{Event: "done", Destination: destination})
+		return
+	}
+
+	if destination == "" {
+		r.writeLine("done")
+		return
+	}
+
+	r.writeLine("done: " + destination)
+}
+
+func (r *reporter) failed(errMsg string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.json {
+		r.writeJSON(Event{Event: "error", Error: errMsg})
+		return
+	}
+
+	r.writeLine("failed: " + errMsg)
+}
+
+func (r *reporter) cancelled() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.json {
+		r.writeJSON(Event{Event: "cancelled"})
+		return
+	}
+
+	r.writeLine("cancelled")
+}
+
+func (r *reporter) hooks(results []types.HookResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, result := range results {
+		if r.json {
+			r.writeJSON(Event{Event: "hook", Command: result.Command, ExitCode: result.ExitCode, Error: result.Err})
+			continue
+		}
+
+		if result.Err != "" {
+			r.writeLine(fmt.Sprintf("hook %q failed (exit %d)", result.Command, result.ExitCode))
+		} else {
+			r.writeLine(fmt.Sprintf("hook %q finished", result.Command))
+		}
+	}
+}
+
+func (r *reporter) summary(succeeded, failed, skipped int, cancelled bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.json {
+		r.writeJSONLine(Event{Event: "summary", Succeeded: succeeded, Failed: failed, Skipped: skipped, Cancelled: cancelled})
+		return
+	}
+
+	line := fmt.Sprintf("%d downloaded, %d failed", succeeded, failed)
+	if skipped > 0 {
+		line += fmt.Sprintf(", %d skipped", skipped)
+	}
+	if cancelled {
+		line += " (cancelled)"
+	}
+	fmt.Fprintln(r.w, line)
+}
+
+func (r *reporter) fatal(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.json {
+		r.writeJSONLine(Event{Event: "error", Error: err.Error()})
+		return
+	}
+
+	fmt.Fprintln(r.w, "error: "+err.Error())
+}
+
+// writeLine prints a line about the current download, prefixed with its
+// place in the run.
+func (r *reporter) writeLine(line string) {
+	fmt.Fprintf(r.w, "[%d/%d] %s\n", r.index, r.total, line)
+}
+
+// writeJSON prints an event about the current download.
+func (r *reporter) writeJSON(event Event) {
+	event.Index, event.Total, event.URL = r.index, r.total, r.url
+	r.writeJSONLine(event)
+}
+
+func (r *reporter) writeJSONLine(event Event) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	fmt.Fprintln(r.w, string(data))
+}
@@ -0,0 +1,209 @@
+package headless
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/xdagiz/xytz/internal/types"
+	"github.com/xdagiz/xytz/internal/utils"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Output formats of a headless search.
+const (
+	FormatTable = "table"
+	FormatJSON  = "json"
+	FormatTSV   = "tsv"
+)
+
+var SearchFormats = []string{FormatTable, FormatJSON, FormatTSV}
+
+type SearchOptions struct {
+	// Query is searched for unless Channel or Playlist is set, in which case
+	// their videos are listed instead.
+	Query              string
+	Channel            string
+	Playlist           string
+	Number             int
+	SortBy             string
+	Format             string
+	CookiesFromBrowser string
+	Cookies            string
+}
+
+// SearchRecord is one search result as printed by --format json.
+type SearchRecord struct {
+	Type           string    `json:"type"`
+	ID             string    `json:"id"`
+	Title          string    `json:"title"`
+	Channel        string    `json:"channel,omitempty"`
+	URL            string    `json:"url"`
+	Duration       float64   `json:"duration,omitempty"`
+	Views          float64   `json:"views,omitempty"`
+	UploadDate     string    `json:"upload_date,omitempty"`
+	LiveStatus     string    `json:"live_status,omitempty"`
+	ScheduledStart time.Time `json:"scheduled_start,omitzero"`
+	Count          int       `json:"count,omitempty"`
+}
+
+// Search lists the results of a query, channel or playlist on stdout and
+// returns the exit code for the run. Errors go to stderr so they never mix
+// with the results.
+func Search(ctx context.Context, opts SearchOptions, stdout, stderr io.Writer) int {
+	format := strings.ToLower(opts.Format)
+	if format == "" {
+		format = FormatTable
+	}
+	if !slices.Contains(SearchFormats, format) {
+		fmt.Fprintf(stderr, "error: unknown format %q (use %s)\n", opts.Format, strings.Join(SearchFormats, ", "))
+		return ExitFailed
+	}
+
+	sm := utils.NewSearchManager()
+	stop := context.AfterFunc(ctx, func() {
+		if err := sm.Cancel(); err != nil {
+			log.Printf("Failed to cancel search: %v", err)
+		}
+	})
+	defer stop()
+
+	var cmd tea.Cmd
+	switch {
+	case opts.Channel != "":
+		cmd = utils.PerformChannelSearch(sm, opts.Channel, opts.Number, opts.CookiesFromBrowser, opts.Cookies)
+	case opts.Playlist != "":
+		cmd = utils.PerformPlaylistSearch(sm, opts.Playlist, opts.Number, opts.CookiesFromBrowser, opts.Cookies)
+	default:
+		searchParam := types.ParseSortBy(opts.SortBy).GetSPParam()
+		cmd = utils.PerformSearch(sm, opts.Query, searchParam, opts.Number, opts.CookiesFromBrowser, opts.Cookies)
+	}
+
+	switch msg := cmd().(type) {
+	case types.SearchResultMsg:
+		if ctx.Err() != nil {
+			return ExitCancelled
+		}
+
+		if msg.Err != "" {
+			fmt.Fprintln(stderr, "error: "+msg.Err)
+			return ExitFailed
+		}
+
+		writeResults(stdout, format, msg.Videos)
+		return ExitOK
+
+	case types.StartFormatMsg:
+		fmt.Fprintf(stderr, "error: %s is a video, not a search (use xytz download)\n", msg.URL)
+		return ExitFailed
+	}
+
+	return ExitCancelled
+}
+
+func searchRecord(item list.Item) (SearchRecord, bool) {
+	switch item := item.(type) {
+	case types.VideoItem:
+		return SearchRecord{
+			Type:           "video",
+			ID:             item.ID,
+			Title:          item.VideoTitle,
+			Channel:        item.Channel,
+			URL:            utils.BuildVideoURL(item.ID),
+			Duration:       item.Duration,
+			Views:          item.Views,
+			UploadDate:     item.UploadDate,
+			LiveStatus:     item.LiveStatus,
+			ScheduledStart: item.ScheduledStart,
+		}, true
+
+	case types.PlaylistItem:
+		return SearchRecord{
+			Type:  "playlist",
+			ID:    item.ID,
+			Title: item.PlaylistTitle,
+			URL:   item.URL,
+			Count: item.Count,
+		}, true
+
+	case types.ChannelItem:
+		return SearchRecord{
+			Type:    "channel",
+			ID:      item.ID,
+			Title:   item.Name,
+			Channel: item.ChannelName,
+			URL:     item.URL,
+		}, true
+	}
+
+	return SearchRecord{}, false
+}
+
+func writeResults(w io.Writer, format string, items []list.Item) {
+	var records []SearchRecord
+	for _, item := range items {
+		if record, ok := searchRecord(item); ok {
+			records = append(records, record)
+		}
+	}
+
+	switch format {
+	case FormatJSON:
+		for _, record := range records {
+			data, err := json.Marshal(record)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintln(w, string(data))
+		}
+
+	case FormatTSV:
+		for _, r := range records {
+			fields := []string{r.Type, r.ID, r.Title, r.Channel, fmt.Sprintf("%.0f", r.Duration), fmt.Sprintf("%.0f", r.Views), r.UploadDate, r.URL}
+			for i, field := range fields {
+				fields[i] = tsvField(field)
+			}
+			fmt.Fprintln(w, strings.Join(fields, "\t"))
+		}
+
+	default:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "TITLE\tCHANNEL\tDURATION\tVIEWS\tUPLOADED\tURL")
+		for _, r := range records {
+			duration, views := "", ""
+			if r.Type == "video" {
+				duration, views = utils.FormatDuration(r.Duration), utils.FormatNumber(r.Views)
+			}
+			switch r.LiveStatus {
+			case types.LiveStatusLive:
+				duration = "LIVE"
+			case types.LiveStatusUpcoming:
+				duration = "UPCOMING"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", truncate(tsvField(r.Title), 60), truncate(tsvField(r.Channel), 24), duration, views, r.UploadDate, r.URL)
+		}
+		tw.Flush()
+	}
+}
+
+// tsvField keeps a value on one line and in one column.
+func tsvField(s string) string {
+	return strings.NewReplacer("\t", " ", "\r", " ", "\n", " ").Replace(s)
+}
+
+func truncate(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+
+	return string(runes[:width-1]) + "…"
+}
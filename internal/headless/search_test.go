@@ -0,0 +1,151 @@
+package headless
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xdagiz/xytz/internal/config"
+)
+
+// setupFakeSearch makes yt-dlp a script that lists two videos, or fails
+// the way yt-dlp does for a missing playlist or a broken extractor. It
+// returns the file the script writes its arguments to.
+func setupFakeSearch(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	origConfigDir := config.GetConfigDir
+	config.GetConfigDir = func() string { return filepath.Join(dir, "config") }
+	t.Cleanup(func() { config.GetConfigDir = origConfigDir })
+
+	argsPath := filepath.Join(dir, "args.txt")
+	script := `#!/usr/bin/env bash
+[ "$1" = "--version" ] && exit 0
+echo "$*" > "` + argsPath + `"
+case "$*" in
+  *list=PLmissing*) echo "ERROR: [youtube:tab] PLmissing: HTTP Error 404: Not Found" >&2; exit 1 ;;
+  *broken*) echo "ERROR: [youtube:search] Unable to extract data" >&2; exit 1 ;;
+esac
+echo '{"id":"aaaaaaaaaaa","title":"First\tVideo","uploader":"Gopher","view_count":1500,"duration":125}'
+echo '{"id":"bbbbbbbbbbb","title":"Second Video","uploader":"Gopher","view_count":20,"duration":3700}'
+`
+	scriptPath := filepath.Join(dir, "fake-yt-dlp.sh")
+	if err := os.WriteFile(scriptPath, []byte(script), 0o755); err != nil {
+		t.Fatalf("write fake yt-dlp: %v", err)
+	}
+
+	cfg := config.GetDefault()
+	cfg.YTDLPPath = scriptPath
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	return argsPath
+}
+
+func TestSearchJSON(t *testing.T) {
+	argsPath := setupFakeSearch(t)
+
+	var stdout, stderr bytes.Buffer
+	opts := SearchOptions{Query: "golang", Number: 2, SortBy: "date", Format: FormatJSON}
+	if got := Search(context.Background(), opts, &stdout, &stderr); got != ExitOK {
+		t.Fatalf("exit code = %d, want %d (stderr: %s)", got, ExitOK, stderr.String())
+	}
+
+	var records []SearchRecord
+	for line := range strings.Lines(stdout.String()) {
+		var record SearchRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("line is not JSON: %q", line)
+		}
+		records = append(records, record)
+	}
+
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+	if r := records[0]; r.Type != "video" || r.ID != "aaaaaaaaaaa" || r.Channel != "Gopher" || r.Duration != 125 || r.URL != "https://www.youtube.com/watch?v=aaaaaaaaaaa" {
+		t.Fatalf("unexpected record: %+v", r)
+	}
+
+	data, err := os.ReadFile(argsPath)
+	if err != nil {
+		t.Fatalf("read args: %v", err)
+	}
+	args := string(data)
+	if !strings.Contains(args, "--playlist-items 1:2") || !strings.Contains(args, "sp=") {
+		t.Fatalf("expected the limit and sort order in args: %s", args)
+	}
+}
+
+func TestSearchTSV(t *testing.T) {
+	setupFakeSearch(t)
+
+	var stdout, stderr bytes.Buffer
+	opts := SearchOptions{Channel: "@gopher", Number: 2, Format: FormatTSV}
+	if got := Search(context.Background(), opts, &stdout, &stderr); got != ExitOK {
+		t.Fatalf("exit code = %d, want %d (stderr: %s)", got, ExitOK, stderr.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2:\n%s", len(lines), stdout.String())
+	}
+
+	want := "video\taaaaaaaaaaa\tFirst Video\tGopher\t125\t1500\t\thttps://www.youtube.com/watch?v=aaaaaaaaaaa"
+	if lines[0] != want {
+		t.Fatalf("line = %q, want %q", lines[0], want)
+	}
+}
+
+func TestSearchTable(t *testing.T) {
+	setupFakeSearch(t)
+
+	var stdout, stderr bytes.Buffer
+	if got := Search(context.Background(), SearchOptions{Query: "golang", Number: 2}, &stdout, &stderr); got != ExitOK {
+		t.Fatalf("exit code = %d, want %d (stderr: %s)", got, ExitOK, stderr.String())
+	}
+
+	out := stdout.String()
+	for _, want := range []string{"TITLE", "Second Video", "1:01:40", "1.5K"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in table:\n%s", want, out)
+		}
+	}
+}
+
+func TestSearchErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		opts SearchOptions
+		want string
+	}{
+		{"classified", SearchOptions{Playlist: "PLmissing"}, "error: Playlist not found\n"},
+		{"from yt-dlp", SearchOptions{Query: "broken"}, "error: [youtube:search] Unable to extract data\n"},
+		{"video URL", SearchOptions{Query: "https://youtu.be/aaaaaaaaaaa"}, "is a video"},
+		{"unknown format", SearchOptions{Query: "golang", Format: "xml"}, `unknown format "xml"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupFakeSearch(t)
+
+			var stdout, stderr bytes.Buffer
+			if got := Search(context.Background(), tt.opts, &stdout, &stderr); got != ExitFailed {
+				t.Fatalf("exit code = %d, want %d", got, ExitFailed)
+			}
+			if stdout.Len() != 0 {
+				t.Fatalf("expected no results, got %q", stdout.String())
+			}
+			if !strings.Contains(stderr.String(), tt.want) {
+				t.Fatalf("stderr = %q, want %q", stderr.String(), tt.want)
+			}
+		})
+	}
+}
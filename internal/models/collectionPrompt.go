@@ -0,0 +1,144 @@
+package models
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/xdagiz/xytz/internal/styles"
+	"github.com/xdagiz/xytz/internal/types"
+	"github.com/xdagiz/xytz/internal/utils"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// CollectionPromptModel asks which collection to save videos to. It starts
+// with the most recently used collection, and Tab cycles through the others.
+type CollectionPromptModel struct {
+	Visible bool
+	Input   textinput.Model
+	Videos  []types.VideoItem
+	names   []string
+	next    int
+}
+
+func NewCollectionPromptModel() CollectionPromptModel {
+	ti := textinput.New()
+	ti.Placeholder = utils.DefaultCollection
+	ti.Prompt = ""
+	ti.CharLimit = 64
+	ti.PlaceholderStyle = ti.PlaceholderStyle.Foreground(styles.MutedColor)
+	ti.TextStyle = ti.TextStyle.Foreground(styles.SecondaryColor)
+
+	return CollectionPromptModel{Input: ti}
+}
+
+func (m *CollectionPromptModel) Show(videos []types.VideoItem) tea.Cmd {
+	collections, err := utils.LoadCollections()
+	if err != nil {
+		log.Printf("Failed to load collections: %v", err)
+	}
+
+	slices.SortStableFunc(collections, func(a, b utils.Collection) int {
+		return b.UpdatedAt.Compare(a.UpdatedAt)
+	})
+
+	m.names = make([]string, len(collections))
+	for i, c := range collections {
+		m.names[i] = c.Name
+	}
+
+	name := utils.DefaultCollection
+	if len(m.names) > 0 {
+		name = m.names[0]
+	}
+
+	m.Visible = true
+	m.Videos = videos
+	m.next = 1
+	m.Input.SetValue(name)
+	m.Input.CursorEnd()
+	m.Input.Focus()
+	return textinput.Blink
+}
+
+func (m *CollectionPromptModel) Hide() {
+	m.Visible = false
+	m.Videos = nil
+	m.Input.Blur()
+}
+
+func (m CollectionPromptModel) Update(msg tea.Msg) (CollectionPromptModel, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "esc":
+			m.Hide()
+			return m, nil
+
+		case "tab":
+			if len(m.names) > 0 {
+				m.Input.SetValue(m.names[m.next%len(m.names)])
+				m.Input.CursorEnd()
+				m.next++
+			}
+
+			return m, nil
+
+		case "enter":
+			name := strings.TrimSpace(m.Input.Value())
+			if name == "" {
+				name = utils.DefaultCollection
+			}
+
+			videos := m.Videos
+			m.Hide()
+			return m, saveToCollection(name, videos)
+		}
+	}
+
+	var cmd tea.Cmd
+	m.Input, cmd = m.Input.Update(msg)
+	return m, cmd
+}
+
+func (m CollectionPromptModel) View() string {
+	if !m.Visible {
+		return ""
+	}
+
+	what := fmt.Sprintf("%d videos", len(m.Videos))
+	if len(m.Videos) == 1 {
+		what = m.Videos[0].Title()
+		if len(what) > 60 {
+			what = what[:57] + "..."
+		}
+	}
+
+	var s strings.Builder
+	s.WriteString(styles.FormatCustomInputPrompt.Render("★  Save to "))
+	s.WriteString(m.Input.View())
+	s.WriteRune('\n')
+	s.WriteString(styles.MutedStyle.Render(what))
+
+	return s.String()
+}
+
+func saveToCollection(name string, videos []types.VideoItem) tea.Cmd {
+	return func() tea.Msg {
+		added, err := utils.AddToCollection(name, videos)
+		if err != nil {
+			return types.ShowToastMsg{Message: fmt.Sprintf("Failed to save to %s: %v", name, err)}
+		}
+
+		switch added {
+		case 0:
+			return types.ShowToastMsg{Message: "Already in " + name}
+		case 1:
+			return types.ShowToastMsg{Message: "Saved to " + name}
+		}
+
+		return types.ShowToastMsg{Message: fmt.Sprintf("Saved %d videos to %s", added, name)}
+	}
+}
@@ -0,0 +1,212 @@
+package models
+
+import (
+	"fmt"
+	"log"
+	"slices"
+
+	"github.com/xdagiz/xytz/internal/styles"
+	"github.com/xdagiz/xytz/internal/types"
+	"github.com/xdagiz/xytz/internal/utils"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+type CollectionItem struct {
+	Collection utils.Collection
+}
+
+func (i CollectionItem) Title() string { return i.Collection.Name }
+func (i CollectionItem) Description() string {
+	desc := fmt.Sprintf("%d videos", len(i.Collection.Videos))
+	if !i.Collection.UpdatedAt.IsZero() {
+		desc += " • " + i.Collection.UpdatedAt.Local().Format("2006-01-02 15:04")
+	}
+
+	return desc
+}
+func (i CollectionItem) FilterValue() string { return i.Collection.Name }
+
+// CollectionsModel lists the saved collections, or the videos of the one
+// that's open.
+type CollectionsModel struct {
+	Visible bool
+	List    list.Model
+	// Open is the name of the collection whose videos are listed.
+	Open   string
+	Width  int
+	Height int
+}
+
+func NewCollectionsModel() CollectionsModel {
+	dl := styles.NewListDelegate()
+	li := list.New([]list.Item{}, dl, 0, 0)
+	li.SetShowStatusBar(false)
+	li.SetShowTitle(false)
+	li.SetShowHelp(false)
+	li.KeyMap.Quit.SetKeys("q")
+	li.FilterInput.Cursor.Style = li.FilterInput.Cursor.Style.Foreground(styles.MauveColor)
+	li.FilterInput.PromptStyle = li.FilterInput.PromptStyle.Foreground(styles.SecondaryColor)
+
+	return CollectionsModel{
+		Visible: false,
+		List:    li,
+		Width:   60,
+		Height:  10,
+	}
+}
+
+func (m *CollectionsModel) Show() {
+	m.Visible = true
+	m.Open = ""
+	m.LoadItems()
+}
+
+func (m *CollectionsModel) Hide() {
+	m.Visible = false
+	m.Open = ""
+	m.List.SetItems([]list.Item{})
+}
+
+func (m *CollectionsModel) LoadItems() {
+	if m.Open != "" {
+		c, err := utils.LoadCollection(m.Open)
+		if err == nil {
+			items := make([]list.Item, len(c.Videos))
+			for i, video := range c.Videos {
+				items[i] = video
+			}
+
+			m.List.SetItems(items)
+			return
+		}
+
+		log.Printf("Failed to load collection %q: %v", m.Open, err)
+		m.Open = ""
+	}
+
+	collections, err := utils.LoadCollections()
+	if err != nil {
+		log.Printf("Failed to load collections: %v", err)
+		m.List.SetItems([]list.Item{})
+		return
+	}
+
+	slices.SortStableFunc(collections, func(a, b utils.Collection) int {
+		return b.UpdatedAt.Compare(a.UpdatedAt)
+	})
+
+	items := make([]list.Item, len(collections))
+	for i, c := range collections {
+		items[i] = CollectionItem{Collection: c}
+	}
+
+	m.List.SetItems(items)
+}
+
+// OpenSelected lists the videos of the highlighted collection.
+func (m *CollectionsModel) OpenSelected() {
+	if item, ok := m.List.SelectedItem().(CollectionItem); ok {
+		m.Open = item.Collection.Name
+		m.List.ResetFilter()
+		m.LoadItems()
+		m.List.ResetSelected()
+	}
+}
+
+// Close goes back from a collection's videos to the collections, with the
+// one that was open highlighted.
+func (m *CollectionsModel) Close() {
+	name := m.Open
+	m.Open = ""
+	m.List.ResetFilter()
+	m.LoadItems()
+
+	for i, item := range m.List.Items() {
+		if c, ok := item.(CollectionItem); ok && c.Collection.Name == name {
+			m.List.Select(i)
+		}
+	}
+}
+
+// SelectedCollection is the open collection, or the highlighted one.
+func (m *CollectionsModel) SelectedCollection() (utils.Collection, bool) {
+	if m.Open == "" {
+		item, ok := m.List.SelectedItem().(CollectionItem)
+		return item.Collection, ok
+	}
+
+	c, err := utils.LoadCollection(m.Open)
+	if err != nil {
+		log.Printf("Failed to load collection %q: %v", m.Open, err)
+		return utils.Collection{}, false
+	}
+
+	return c, true
+}
+
+func (m *CollectionsModel) SelectedVideo() (types.VideoItem, bool) {
+	video, ok := m.List.SelectedItem().(types.VideoItem)
+	return video, ok
+}
+
+// MoveSelected moves the highlighted video delta places within the open
+// collection.
+func (m *CollectionsModel) MoveSelected(delta int) {
+	if m.Open == "" || m.List.FilterState() != list.Unfiltered || len(m.List.Items()) == 0 {
+		return
+	}
+
+	to, err := utils.MoveInCollection(m.Open, m.List.Index(), delta)
+	if err != nil {
+		log.Printf("Failed to reorder collection %q: %v", m.Open, err)
+		return
+	}
+
+	m.LoadItems()
+	m.List.Select(to)
+}
+
+// DeleteSelected removes the highlighted video from the open collection,
+// or deletes the highlighted collection.
+func (m *CollectionsModel) DeleteSelected() {
+	if m.Open != "" {
+		if video, ok := m.SelectedVideo(); ok {
+			if err := utils.RemoveFromCollection(m.Open, video.ID); err != nil {
+				log.Printf("Failed to remove video from collection: %v", err)
+			}
+		}
+	} else if item, ok := m.List.SelectedItem().(CollectionItem); ok {
+		if err := utils.DeleteCollection(item.Collection.Name); err != nil {
+			log.Printf("Failed to delete collection: %v", err)
+		}
+	}
+
+	m.LoadItems()
+}
+
+func (m *CollectionsModel) HandleResize(width, height int) {
+	m.Width = width
+	m.Height = height
+	m.List.SetSize(width, height-7)
+}
+
+func (m *CollectionsModel) View(width, height int) string {
+	if !m.Visible {
+		return ""
+	}
+
+	var headerText string
+	switch {
+	case m.List.FilterState() == list.FilterApplied:
+		headerText = "Filtered Results"
+	case m.Open != "":
+		headerText = fmt.Sprintf("Collection: %s (%d videos)", m.Open, len(m.List.Items()))
+	case len(m.List.Items()) == 0:
+		headerText = "No collections yet. Press w on a video to save it"
+	default:
+		headerText = "Collections"
+	}
+
+	return styles.SectionHeaderStyle.Render(headerText) + "\n" + styles.ListContainer.Render(m.List.View())
+}
@@ -23,22 +23,45 @@ type DownloadModel struct {
 	CurrentSpeed    string
 	CurrentETA      string
 	Phase           string
+	Stage           string
+	StageStartedAt  time.Time
 	Completed       bool
 	Paused          bool
 	Cancelled       bool
 	Destination     string
 	FileDestination string
 	FileExtension   string
-	DownloadManager *utils.DownloadManager
-	IsQueue         bool
-	QueueItems      []types.QueueItem
-	QueueIndex      int
-	QueueTotal      int
-	QueueFormatID   string
-	QueueLabel      string
-	QueueIsAudioTab bool
-	QueueABR        float64
-	QueueError      string
+	DownloadedBytes int64
+	TotalBytes      int64
+	FragmentIndex   int
+	FragmentCount   int
+	// Elapsed is how much of a live stream has been recorded, in seconds.
+	Elapsed           float64
+	StoppingRecording bool
+	DownloadManager   *utils.DownloadManager
+	IsQueue           bool
+	QueueItems        []types.QueueItem
+	QueueIndex        int
+	QueueTotal        int
+	QueueFormatID     string
+	QueueLabel        string
+	QueueIsAudioTab   bool
+	QueueABR          float64
+	// QueueOptions and the cookies are what the queue was started with, so
+	// every item and a later /resume use the same settings.
+	QueueOptions            []types.DownloadOption
+	QueueCookiesFromBrowser string
+	QueueCookies            string
+	QueueError              string
+	QueueHold               string
+	QueueLimit              int
+	RateLimit               string
+	FormatID                string
+	IsAudioTab              bool
+	Clip                    types.ClipRange
+	Subtitles               types.SubtitleRequest
+	Thumbnail               types.ThumbnailItem
+	HookRuns                []types.HookResultMsg
 }
 
 const destinationTitleMaxLen = 16
@@ -53,6 +76,7 @@ func NewDownloadModel() DownloadModel {
 		Progress:        pr,
 		Destination:     destination,
 		DownloadManager: utils.NewDownloadManager(),
+		RateLimit:       cfg.RateLimit,
 	}
 }
 
@@ -67,37 +91,94 @@ func (m DownloadModel) Update(msg tea.Msg) (DownloadModel, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case types.ProgressMsg:
-		cmd = m.Progress.SetPercent(msg.Percent / 100.0)
-		m.CurrentSpeed = msg.Speed
-		m.CurrentETA = msg.Eta
-		m.Phase = msg.Status
-		if msg.Destination != "" {
-			m.FileDestination = msg.Destination
-		}
-		if msg.FileExtension != "" {
-			m.FileExtension = msg.FileExtension
+		isFocused := !m.IsQueue || msg.QueueIndex == 0 || msg.QueueIndex == m.QueueIndex
+		if isFocused {
+			cmd = m.Progress.SetPercent(msg.Percent / 100.0)
+			m.CurrentSpeed = msg.Speed
+			m.CurrentETA = msg.Eta
+			m.Phase = msg.Status
+			if msg.Destination != "" {
+				m.FileDestination = msg.Destination
+			}
+			if msg.FileExtension != "" {
+				m.FileExtension = msg.FileExtension
+			}
+			if msg.TotalBytes > 0 || msg.FragmentCount > 0 {
+				m.DownloadedBytes = msg.DownloadedBytes
+				m.TotalBytes = msg.TotalBytes
+				m.FragmentIndex = msg.FragmentIndex
+				m.FragmentCount = msg.FragmentCount
+			} else if msg.Elapsed > 0 {
+				m.DownloadedBytes = msg.DownloadedBytes
+			}
+			if msg.Elapsed > 0 {
+				m.Elapsed = msg.Elapsed
+			}
+
+			if msg.Stage != "" && msg.Stage != m.Stage {
+				m.Stage = msg.Stage
+				m.StageStartedAt = time.Now()
+				cmd = tea.Batch(cmd, stageTick(m.StageStartedAt))
+			} else if msg.Stage == "" && msg.Status != "" {
+				m.Stage = ""
+			}
 		}
 
-		if m.IsQueue && msg.QueueIndex > 0 && msg.QueueIndex == m.QueueIndex && len(m.QueueItems) >= msg.QueueIndex {
+		if m.IsQueue && msg.QueueIndex > 0 && len(m.QueueItems) >= msg.QueueIndex {
 			item := &m.QueueItems[msg.QueueIndex-1]
 			item.Progress = msg.Percent
 			item.Speed = msg.Speed
 			item.ETA = msg.Eta
+			if msg.Elapsed > 0 {
+				item.Elapsed = msg.Elapsed
+				item.Downloaded = msg.DownloadedBytes
+			}
 			if msg.Destination != "" {
 				item.Destination = msg.Destination
 			}
+			if msg.Stage != "" && msg.Stage != item.Stage {
+				item.Stage = msg.Stage
+				item.StageStartedAt = time.Now()
+			} else if msg.Stage == "" && msg.Status != "" {
+				item.Stage = ""
+			}
 		}
 
+	case types.StageTickMsg:
+		if m.Stage != "" && msg.Started.Equal(m.StageStartedAt) && !m.Completed && !m.Cancelled {
+			cmd = stageTick(msg.Started)
+		}
+
+	case types.HookResultMsg:
+		m.HookRuns = append(m.HookRuns, msg)
+
 	case types.PauseDownloadMsg:
-		m.Paused = true
+		m.SetPaused(msg.QueueIndex, true)
 
 	case types.ResumeDownloadMsg:
-		m.Paused = false
+		m.SetPaused(msg.QueueIndex, false)
 
 	case types.CancelDownloadMsg:
 		m.Cancelled = true
 
+	case types.StopRecordingMsg:
+		if msg.QueueIndex == 0 || msg.QueueIndex == m.QueueIndex {
+			m.StoppingRecording = true
+		}
+
 	case tea.KeyMsg:
+		if m.Completed && m.IsQueue && m.retryable() > 0 {
+			switch msg.String() {
+			case "up", "k", "down", "j", "r":
+				if m.QueueIndex < 1 {
+					m.FocusQueueItem(1)
+				}
+
+				queueCmd, _ := m.handleQueueKey(msg)
+				return m, queueCmd
+			}
+		}
+
 		if m.Completed || m.Cancelled && msg.Type == tea.KeyEnter {
 			cmd = func() tea.Msg {
 				return types.DownloadCompleteMsg{}
@@ -124,7 +205,27 @@ func (m DownloadModel) Update(msg tea.Msg) (DownloadModel, tea.Cmd) {
 		}
 
 		if !m.Completed && !m.Cancelled {
+			if m.QueueHold != "" && msg.String() == "r" {
+				return m, func() tea.Msg {
+					return types.ResumeQueueMsg{}
+				}
+			}
+
+			if m.IsQueue {
+				if queueCmd, handled := m.handleQueueKey(msg); handled {
+					return m, queueCmd
+				}
+			}
+
 			switch msg.String() {
+			case "f":
+				if m.Recording() && !m.StoppingRecording {
+					index := 0
+					if m.IsQueue {
+						index = m.QueueIndex
+					}
+					cmd = utils.StopRecording(m.DownloadManager, index)
+				}
 			case "p", " ":
 				if m.Paused {
 					cmd = utils.ResumeDownload(m.DownloadManager)
@@ -135,6 +236,11 @@ func (m DownloadModel) Update(msg tea.Msg) (DownloadModel, tea.Cmd) {
 				cmd = func() tea.Msg {
 					return types.CancelDownloadMsg{}
 				}
+			case "l":
+				limit := config.NextRateLimit(m.RateLimit)
+				cmd = func() tea.Msg {
+					return types.SetRateLimitMsg{Limit: limit}
+				}
 			case "ctrl+y":
 				if m.SelectedVideo.ID != "" {
 					url := utils.BuildVideoURL(m.SelectedVideo.ID)
@@ -160,6 +266,142 @@ func (m DownloadModel) Update(msg tea.Msg) (DownloadModel, tea.Cmd) {
 	return m, tea.Batch(cmd, downloadCmd)
 }
 
+func (m *DownloadModel) SetPaused(index int, paused bool) {
+	if index > 0 {
+		if len(m.QueueItems) >= index {
+			m.QueueItems[index-1].Paused = paused
+		}
+
+		return
+	}
+
+	m.Paused = paused
+	for i := range m.QueueItems {
+		if m.QueueItems[i].Status == types.QueueStatusDownloading {
+			m.QueueItems[i].Paused = paused
+		}
+	}
+}
+
+// CanRestart reports whether the download at index (0 for a single one) can
+// be restarted to pick up a new rate limit. Paused downloads stay paused,
+// post-processing doesn't download anything, and a live recording would
+// start over from now.
+func (m DownloadModel) CanRestart(index int) bool {
+	paused, stage, video := m.Paused, m.Stage, m.SelectedVideo
+	if index > 0 {
+		if index > len(m.QueueItems) {
+			return false
+		}
+
+		item := m.QueueItems[index-1]
+		paused, stage, video = item.Paused, item.Stage, item.Video
+	}
+
+	return !paused && stage == "" && !video.IsLive() && !video.IsUpcoming()
+}
+
+func (m *DownloadModel) handleQueueKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	index := m.QueueIndex
+	if index <= 0 || index > len(m.QueueItems) {
+		return nil, false
+	}
+
+	item := m.QueueItems[index-1]
+
+	switch msg.String() {
+	case "up", "k":
+		m.FocusQueueItem(index - 1)
+		return nil, true
+	case "down", "j":
+		m.FocusQueueItem(index + 1)
+		return nil, true
+	case "P":
+		if item.Status != types.QueueStatusDownloading {
+			return nil, true
+		}
+
+		if item.Paused {
+			return utils.ResumeDownloadItem(m.DownloadManager, index), true
+		}
+
+		return utils.PauseDownloadItem(m.DownloadManager, index), true
+	case "s":
+		if item.Status != types.QueueStatusPending && item.Status != types.QueueStatusDownloading {
+			return nil, true
+		}
+
+		return func() tea.Msg {
+			return types.SkipCurrentQueueItemMsg{Index: index}
+		}, true
+	case "x":
+		if item.Status != types.QueueStatusDownloading {
+			return nil, true
+		}
+
+		return func() tea.Msg {
+			return types.CancelQueueItemMsg{Index: index}
+		}, true
+	case "r":
+		if item.Status != types.QueueStatusError && item.Status != types.QueueStatusSkipped {
+			return nil, true
+		}
+
+		return func() tea.Msg {
+			return types.RetryCurrentQueueItemMsg{Index: index}
+		}, true
+	}
+
+	return nil, false
+}
+
+// FocusQueueItem moves the queue cursor to index and shows that item's
+// progress in the main download view.
+func (m *DownloadModel) FocusQueueItem(index int) {
+	if index < 1 || index > len(m.QueueItems) {
+		return
+	}
+
+	item := m.QueueItems[index-1]
+	m.QueueIndex = index
+	m.SelectedVideo = item.Video
+	m.Progress.SetPercent(item.Progress / 100.0)
+	m.CurrentSpeed = item.Speed
+	m.CurrentETA = item.ETA
+	m.FileDestination = item.Destination
+	m.Phase = ""
+	m.Stage = item.Stage
+	m.StageStartedAt = item.StageStartedAt
+	m.DownloadedBytes = 0
+	m.TotalBytes = 0
+	m.FragmentIndex = 0
+	m.FragmentCount = 0
+	m.Elapsed = item.Elapsed
+	m.DownloadedBytes = item.Downloaded
+	m.StoppingRecording = false
+}
+
+// Waiting reports whether yt-dlp is waiting for an upcoming stream to start.
+func (m DownloadModel) Waiting() bool {
+	return m.Phase == utils.WaitingStatus && !m.Completed && !m.Cancelled
+}
+
+// Recording reports whether the focused download is a live stream being
+// recorded, which has no percentage to show.
+func (m DownloadModel) Recording() bool {
+	video := m.SelectedVideo
+	return (video.IsLive() || video.IsUpcoming()) && !m.Waiting() && !m.Completed && !m.Cancelled
+}
+
+func recordingText(elapsed float64, downloaded int64) string {
+	text := utils.FormatDuration(elapsed)
+	if downloaded > 0 {
+		text += " • " + utils.FormatBytes(downloaded)
+	}
+
+	return text
+}
+
 func (m DownloadModel) HandleResize(w, h int) DownloadModel {
 	if w > 100 {
 		m.Progress.Width = (w / 2) - 10
@@ -200,6 +442,28 @@ func (m DownloadModel) renderQueueItem(item types.QueueItem, isCurrent bool) str
 
 	line := fmt.Sprintf("%s %s", statusIcon, title)
 
+	if item.Status == types.QueueStatusDownloading {
+		if item.Paused {
+			line = fmt.Sprintf("%s — ⏸ paused at %.1f%%", line, item.Progress)
+		} else if item.Stage != "" {
+			line = fmt.Sprintf("%s — %s", line, stageText(item.Stage, item.StageStartedAt))
+		} else if item.Elapsed > 0 && (item.Video.IsLive() || item.Video.IsUpcoming()) {
+			line = fmt.Sprintf("%s — ● recording %s", line, recordingText(item.Elapsed, item.Downloaded))
+		} else if item.Progress > 0 {
+			line = fmt.Sprintf("%s — %.1f%%", line, item.Progress)
+			if item.Speed != "" {
+				line += " " + item.Speed
+			}
+			if item.ETA != "" {
+				line += " ETA " + item.ETA
+			}
+		}
+	}
+
+	if item.Status == types.QueueStatusSkipped && item.Error != "" {
+		line = fmt.Sprintf("%s — %s", line, item.Error)
+	}
+
 	if item.Status == types.QueueStatusError && item.Error != "" {
 		line = fmt.Sprintf("%s — %s", line, item.Error)
 	}
@@ -222,6 +486,11 @@ func (m DownloadModel) countByStatus(status types.QueueStatus) int {
 	return count
 }
 
+// retryable counts the failed and skipped items r can start again.
+func (m DownloadModel) retryable() int {
+	return m.countByStatus(types.QueueStatusError) + m.countByStatus(types.QueueStatusSkipped)
+}
+
 func (m DownloadModel) currentDisplayDestination() string {
 	if m.FileDestination != "" {
 		return m.FileDestination
@@ -239,6 +508,34 @@ func (m DownloadModel) currentDisplayDestination() string {
 	return filepath.Join(m.Destination, title)
 }
 
+func stageTick(started time.Time) tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return types.StageTickMsg{Started: started}
+	})
+}
+
+func stageText(stage string, started time.Time) string {
+	text := types.StageDisplayName(stage)
+	if !started.IsZero() {
+		text += " " + time.Since(started).Truncate(time.Second).String()
+	}
+
+	return text
+}
+
+func (m DownloadModel) sizeInfo() string {
+	var parts []string
+	if m.TotalBytes > 0 {
+		parts = append(parts, fmt.Sprintf("%s of %s", utils.FormatBytes(m.DownloadedBytes), utils.FormatBytes(m.TotalBytes)))
+	}
+
+	if m.FragmentCount > 0 {
+		parts = append(parts, fmt.Sprintf("fragment %d/%d", m.FragmentIndex, m.FragmentCount))
+	}
+
+	return strings.Join(parts, " • ")
+}
+
 func truncateDestinationTitle(path string, maxTitleLen int) string {
 	if path == "" || maxTitleLen <= 0 {
 		return path
@@ -264,20 +561,58 @@ func truncateDestinationTitle(path string, maxTitleLen int) string {
 	return filepath.Join(dir, truncated)
 }
 
+// WholeVideo reports whether the download fetches the full video, as opposed
+// to a clip, its subtitles or its thumbnail.
+func (m DownloadModel) WholeVideo() bool {
+	return m.Clip.IsZero() && !m.Subtitles.Only && m.Thumbnail.URL == ""
+}
+
+func subtitleText(sub types.SubtitleRequest) string {
+	text := strings.Join(sub.AllLanguages(), ", ")
+	if sub.Only {
+		return text + " (." + sub.Format + " only)"
+	}
+
+	return text + " (embedded)"
+}
+
 func (m DownloadModel) View() string {
 	var s strings.Builder
 	completed := m.countByStatus(types.QueueStatusComplete)
 	failed := m.countByStatus(types.QueueStatusError)
 
 	if m.IsQueue && len(m.QueueItems) > 0 {
-		s.WriteString(styles.SectionHeaderStyle.Foreground(styles.MauveColor).Render(fmt.Sprintf("📋 Queue: Video %d of %d", m.QueueIndex, m.QueueTotal)))
+		header := fmt.Sprintf("📋 Queue: Video %d of %d", m.QueueIndex, m.QueueTotal)
+		if active := m.countByStatus(types.QueueStatusDownloading); active > 1 {
+			header += fmt.Sprintf(" (%d active)", active)
+		}
+		s.WriteString(styles.SectionHeaderStyle.Foreground(styles.MauveColor).Render(header))
 	}
 
 	if m.SelectedVideo.ID != "" {
 		s.WriteString(styles.SectionHeaderStyle.Render(m.SelectedVideo.Title()))
 		s.WriteRune('\n')
-		s.WriteString(styles.MutedStyle.Render(fmt.Sprintf("⏱  %s", utils.FormatDuration(m.SelectedVideo.Duration))))
+		switch {
+		case m.SelectedVideo.IsLive():
+			s.WriteString(styles.MutedStyle.Render("🔴 Live stream"))
+		case m.SelectedVideo.IsUpcoming():
+			s.WriteString(styles.MutedStyle.Render("⏳ " + utils.FormatScheduledStart(m.SelectedVideo.ScheduledStart)))
+		default:
+			s.WriteString(styles.MutedStyle.Render(fmt.Sprintf("⏱  %s", utils.FormatDuration(m.SelectedVideo.Duration))))
+		}
 		s.WriteRune('\n')
+		if !m.Clip.IsZero() {
+			s.WriteString(styles.MutedStyle.Render(fmt.Sprintf("✂  %s", utils.FormatClipRange(m.Clip))))
+			s.WriteRune('\n')
+		}
+		if m.Thumbnail.URL != "" {
+			s.WriteString(styles.MutedStyle.Render(fmt.Sprintf("🖼  Thumbnail %s", m.Thumbnail.Title())))
+			s.WriteRune('\n')
+		}
+		if !m.Subtitles.IsZero() {
+			s.WriteString(styles.MutedStyle.Render(fmt.Sprintf("💬 %s", subtitleText(m.Subtitles))))
+			s.WriteRune('\n')
+		}
 		s.WriteString(styles.MutedStyle.Render(fmt.Sprintf("👁  %s views", utils.FormatNumber(m.SelectedVideo.Views))))
 		s.WriteRune('\n')
 		s.WriteString(styles.MutedStyle.Render(fmt.Sprintf("📺 %s", m.SelectedVideo.Channel)))
@@ -293,8 +628,18 @@ func (m DownloadModel) View() string {
 		statusText = "✓ Download Complete"
 	} else if m.Paused {
 		statusText = "⏸ Paused"
+	} else if m.QueueHold != "" && !m.Cancelled {
+		statusText = "⏸ Queue on hold: " + m.QueueHold
 	} else if m.Cancelled {
 		statusText = "✕ Cancelled"
+	} else if m.Stage != "" {
+		statusText = "⚙ " + stageText(m.Stage, m.StageStartedAt)
+	} else if m.Waiting() {
+		statusText = "⏳ Waiting for the stream to start"
+	} else if m.StoppingRecording {
+		statusText = "● Finishing recording"
+	} else if m.Recording() {
+		statusText = "● Recording"
 	} else if m.Phase != "" {
 		formatInfo := strings.TrimPrefix(m.Phase, "[download] ")
 		if formatInfo != "" && formatInfo != "[download]" {
@@ -327,11 +672,13 @@ func (m DownloadModel) View() string {
 			s.WriteString(styles.SectionHeaderStyle.Render("Queue Summary:"))
 			s.WriteRune('\n')
 
-			for _, item := range m.QueueItems {
-				s.WriteString(m.renderQueueItem(item, false))
+			retryable := m.retryable() > 0
+			for i, item := range m.QueueItems {
+				s.WriteString(m.renderQueueItem(item, retryable && i == m.QueueIndex-1))
 				s.WriteRune('\n')
 			}
 
+			s.WriteString(m.renderHookRuns())
 			s.WriteRune('\n')
 			summaryParts := []string{}
 			if completed > 0 {
@@ -352,12 +699,17 @@ func (m DownloadModel) View() string {
 			}
 			s.WriteRune('\n')
 			s.WriteRune('\n')
-			s.WriteString(styles.HelpStyle.Render("Press Enter to continue"))
+			if retryable {
+				s.WriteString(styles.HelpStyle.Render("[↑/↓] Select  [r] Retry  Press Enter to continue"))
+			} else {
+				s.WriteString(styles.HelpStyle.Render("Press Enter to continue"))
+			}
 		} else {
 			finalPath := m.currentDisplayDestination()
 
 			s.WriteString(styles.CompletionMessageStyle.Render("Video saved to " + fmt.Sprintf("\"%s\"", finalPath)))
 			s.WriteRune('\n')
+			s.WriteString(m.renderHookRuns())
 			s.WriteRune('\n')
 			s.WriteString(styles.HelpStyle.Render("Press Enter to continue"))
 		}
@@ -394,7 +746,26 @@ func (m DownloadModel) View() string {
 			s.WriteRune('\n')
 		}
 	} else {
-		if m.Progress.Percent() == 0 {
+		if m.Waiting() {
+			s.WriteString("Checking again in: " + styles.TimeRemainingStyle.Render(m.CurrentETA))
+			s.WriteRune('\n')
+			s.WriteString(styles.HelpStyle.Render("The download starts on its own once the stream goes live"))
+			s.WriteRune('\n')
+		} else if m.Recording() {
+			if m.Elapsed > 0 {
+				s.WriteString("Recorded: " + styles.SpeedStyle.Render(recordingText(m.Elapsed, m.DownloadedBytes)))
+			} else {
+				s.WriteString(styles.MutedStyle.Render("Starting recording..."))
+			}
+			s.WriteRune('\n')
+
+			s.WriteString("Destination: " + styles.DestinationStyle.Render(truncateDestinationTitle(m.currentDisplayDestination(), destinationTitleMaxLen)))
+			s.WriteRune('\n')
+			if !m.StoppingRecording {
+				s.WriteString(styles.HelpStyle.Render("Press f to finish the recording and keep what was recorded"))
+				s.WriteRune('\n')
+			}
+		} else if m.Progress.Percent() == 0 {
 			s.WriteString(styles.MutedStyle.Render("Starting download..."))
 			s.WriteRune('\n')
 		} else {
@@ -403,22 +774,81 @@ func (m DownloadModel) View() string {
 			s.WriteRune('\n')
 
 			s.WriteString("Speed: " + styles.SpeedStyle.Render(m.CurrentSpeed))
+			s.WriteString(styles.MutedStyle.Render(fmt.Sprintf(" (limit: %s)", config.RateLimitDisplay(m.RateLimit))))
 			s.WriteRune('\n')
 
 			s.WriteString("Time remaining: " + styles.TimeRemainingStyle.Render(m.CurrentETA))
 			s.WriteRune('\n')
 
+			if sizeInfo := m.sizeInfo(); sizeInfo != "" {
+				s.WriteString("Downloaded: " + styles.MutedStyle.Render(sizeInfo))
+				s.WriteRune('\n')
+			}
+
 			s.WriteString("Destination: " + styles.DestinationStyle.Render(truncateDestinationTitle(m.currentDisplayDestination(), destinationTitleMaxLen)))
 			s.WriteRune('\n')
 		}
 
+		if m.QueueHold != "" {
+			s.WriteString(styles.WarningMessageStyle.Render("Free up disk space, then press r to resume the queue"))
+			s.WriteRune('\n')
+		}
+
 		if m.IsQueue && len(m.QueueItems) > 0 {
 			s.WriteString(styles.SectionHeaderStyle.Render("Queue Items:"))
 			s.WriteRune('\n')
+			s.WriteString(styles.HelpStyle.Render("[↑/↓] Select  [P] Pause item  [s] Skip  [x] Cancel item  [r] Retry"))
+			s.WriteRune('\n')
 			for i, item := range m.QueueItems {
 				s.WriteString(m.renderQueueItem(item, i == m.QueueIndex-1))
 				s.WriteRune('\n')
 			}
+
+			s.WriteString(m.renderHookRuns())
+		}
+	}
+
+	return s.String()
+}
+
+const maxHookRunsShown = 5
+
+func (m DownloadModel) renderHookRuns() string {
+	if len(m.HookRuns) == 0 {
+		return ""
+	}
+
+	var s strings.Builder
+	s.WriteRune('\n')
+	s.WriteString(styles.SectionHeaderStyle.Render("Post-download hooks:"))
+	s.WriteRune('\n')
+
+	runs := m.HookRuns
+	if len(runs) > maxHookRunsShown {
+		runs = runs[len(runs)-maxHookRunsShown:]
+	}
+
+	for _, run := range runs {
+		for _, result := range run.Results {
+			line := fmt.Sprintf("✓ %s (exit 0)", result.Command)
+			style := lipgloss.NewStyle().Foreground(styles.SuccessColor)
+			if result.Err != "" {
+				line = fmt.Sprintf("✗ %s (exit %d)", result.Command, result.ExitCode)
+				style = styles.ErrorMessageStyle
+			}
+
+			if m.IsQueue && run.Title != "" {
+				line += " — " + run.Title
+			}
+
+			s.WriteString(style.Render(line))
+			s.WriteRune('\n')
+
+			if result.Output != "" {
+				lines := strings.Split(result.Output, "\n")
+				s.WriteString(styles.MutedStyle.Render("  " + lines[len(lines)-1]))
+				s.WriteRune('\n')
+			}
 		}
 	}
 
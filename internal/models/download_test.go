@@ -4,9 +4,11 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/xdagiz/xytz/internal/types"
+	"github.com/xdagiz/xytz/internal/utils"
 )
 
 func TestTruncateDestinationTitle(t *testing.T) {
@@ -91,3 +93,156 @@ func TestDownloadModelEscKeyDuringQueueErrorEmitsCancelDownloadMsg(t *testing.T)
 		t.Fatalf("ESC key during queue error emitted %T, expected types.CancelDownloadMsg", msg)
 	}
 }
+
+func TestDownloadModelLKeyCyclesRateLimit(t *testing.T) {
+	m := NewDownloadModel()
+	m.RateLimit = "1M"
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'l'}})
+	if cmd == nil {
+		t.Fatalf("'l' key did not emit a command, expected SetRateLimitMsg")
+	}
+
+	msg, ok := cmd().(types.SetRateLimitMsg)
+	if !ok {
+		t.Fatalf("'l' key emitted %T, expected types.SetRateLimitMsg", cmd())
+	}
+	if msg.Limit != "5M" {
+		t.Fatalf("SetRateLimitMsg.Limit = %q, want 5M", msg.Limit)
+	}
+}
+
+func TestDownloadModelCanRestart(t *testing.T) {
+	m := NewDownloadModel()
+	m.IsQueue = true
+	m.QueueItems = []types.QueueItem{
+		{Index: 1, Status: types.QueueStatusDownloading},
+		{Index: 2, Status: types.QueueStatusDownloading, Paused: true},
+		{Index: 3, Status: types.QueueStatusDownloading, Stage: types.StageMerger},
+		{Index: 4, Status: types.QueueStatusDownloading, Video: types.VideoItem{LiveStatus: types.LiveStatusLive}},
+	}
+
+	for index, want := range map[int]bool{1: true, 2: false, 3: false, 4: false, 5: false} {
+		if got := m.CanRestart(index); got != want {
+			t.Errorf("CanRestart(%d) = %v, want %v", index, got, want)
+		}
+	}
+
+	m.Paused = true
+	if m.CanRestart(0) {
+		t.Errorf("CanRestart(0) = true for a paused single download")
+	}
+}
+
+func TestDownloadModelViewShowsStructuredProgress(t *testing.T) {
+	m := NewDownloadModel()
+	m.SelectedVideo = types.VideoItem{ID: "abc", VideoTitle: "Test Video"}
+
+	m, _ = m.Update(types.ProgressMsg{
+		Percent:         25,
+		Status:          "[download] video (137)",
+		DownloadedBytes: 1024,
+		TotalBytes:      4096,
+		FragmentIndex:   1,
+		FragmentCount:   4,
+	})
+	m.Progress.SetPercent(0.25)
+
+	view := m.View()
+	if !strings.Contains(view, "Downloading video (137)") {
+		t.Fatalf("view missing stream label:\n%s", view)
+	}
+	if !strings.Contains(view, "1.00 KiB of 4.00 KiB") || !strings.Contains(view, "fragment 1/4") {
+		t.Fatalf("view missing size info:\n%s", view)
+	}
+
+}
+
+func TestDownloadModelStageShowsElapsedTime(t *testing.T) {
+	m := NewDownloadModel()
+	m.SelectedVideo = types.VideoItem{ID: "abc", VideoTitle: "Test Video"}
+
+	m, cmd := m.Update(types.ProgressMsg{Percent: 100, Stage: types.StageMerger})
+	if cmd == nil {
+		t.Fatalf("expected a stage tick command")
+	}
+	if m.Stage != types.StageMerger {
+		t.Fatalf("Stage = %q, want %q", m.Stage, types.StageMerger)
+	}
+
+	m.StageStartedAt = time.Now().Add(-12 * time.Second)
+	m.Progress.SetPercent(1)
+	if view := m.View(); !strings.Contains(view, "Merging formats 12s") {
+		t.Fatalf("view missing merge stage with elapsed time:\n%s", view)
+	}
+
+	_, cmd = m.Update(types.StageTickMsg{Started: m.StageStartedAt})
+	if cmd == nil {
+		t.Fatalf("expected stage tick to continue while the stage runs")
+	}
+	_, cmd = m.Update(types.StageTickMsg{Started: time.Now().Add(-time.Hour)})
+	if cmd != nil {
+		t.Fatalf("expected stale stage tick to stop")
+	}
+
+	m, _ = m.Update(types.ProgressMsg{Percent: 10, Status: "[download] audio (140)"})
+	if m.Stage != "" {
+		t.Fatalf("Stage = %q, want cleared once downloading resumes", m.Stage)
+	}
+}
+
+func TestDownloadModelQueueItemShowsStage(t *testing.T) {
+	m := NewDownloadModel()
+	m.IsQueue = true
+	m.QueueIndex = 1
+	m.QueueItems = []types.QueueItem{
+		{Index: 1, Video: types.VideoItem{ID: "a", VideoTitle: "First"}, Status: types.QueueStatusDownloading},
+		{Index: 2, Video: types.VideoItem{ID: "b", VideoTitle: "Second"}, Status: types.QueueStatusDownloading},
+	}
+
+	m, _ = m.Update(types.ProgressMsg{Percent: 100, Stage: types.StageExtractAudio, QueueIndex: 2})
+	if got := m.QueueItems[1].Stage; got != types.StageExtractAudio {
+		t.Fatalf("queue item Stage = %q, want %q", got, types.StageExtractAudio)
+	}
+	if line := m.renderQueueItem(m.QueueItems[1], false); !strings.Contains(line, "Extracting audio") {
+		t.Fatalf("queue item line missing stage: %q", line)
+	}
+}
+
+func TestDownloadModelRecordingShowsElapsedAndSize(t *testing.T) {
+	m := NewDownloadModel()
+	m.SelectedVideo = types.VideoItem{ID: "abc", VideoTitle: "Live Show", LiveStatus: types.LiveStatusLive}
+
+	m, _ = m.Update(types.ProgressMsg{
+		Status:          "[download]",
+		DownloadedBytes: 2 * 1024 * 1024,
+		Elapsed:         125,
+	})
+
+	view := m.View()
+	for _, want := range []string{"● Recording", "Recorded: 2:05 • 2.00 MiB", "Press f to finish"} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("view missing %q:\n%s", want, view)
+		}
+	}
+
+	m, _ = m.Update(types.StopRecordingMsg{})
+	if view := m.View(); !strings.Contains(view, "Finishing recording") {
+		t.Fatalf("view missing finishing status:\n%s", view)
+	}
+}
+
+func TestDownloadModelWaitingForUpcomingStream(t *testing.T) {
+	m := NewDownloadModel()
+	m.SelectedVideo = types.VideoItem{ID: "abc", VideoTitle: "Premiere", LiveStatus: types.LiveStatusUpcoming}
+
+	m, _ = m.Update(types.ProgressMsg{Status: utils.WaitingStatus, Eta: "00:59"})
+
+	view := m.View()
+	if !strings.Contains(view, "Waiting for the stream to start") || !strings.Contains(view, "00:59") {
+		t.Fatalf("view missing waiting status:\n%s", view)
+	}
+	if m.Recording() {
+		t.Fatal("Recording() = true while waiting for the stream")
+	}
+}
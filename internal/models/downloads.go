@@ -0,0 +1,144 @@
+package models
+
+import (
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/xdagiz/xytz/internal/styles"
+	"github.com/xdagiz/xytz/internal/utils"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+type DownloadItem struct {
+	Download utils.CompletedDownload
+}
+
+func (i DownloadItem) Title() string {
+	if i.Download.Title != "" {
+		return i.Download.Title
+	}
+
+	return i.Download.VideoID
+}
+
+func (i DownloadItem) Description() string {
+	var parts []string
+	if i.Download.Channel != "" {
+		parts = append(parts, i.Download.Channel)
+	}
+
+	if i.Download.Size > 0 {
+		parts = append(parts, utils.FormatBytes(i.Download.Size))
+	}
+
+	if !i.Download.Timestamp.IsZero() {
+		parts = append(parts, i.Download.Timestamp.Local().Format("2006-01-02 15:04"))
+	}
+
+	if i.Download.Destination != "" {
+		parts = append(parts, i.Download.Destination)
+	}
+
+	return strings.Join(parts, " • ")
+}
+
+func (i DownloadItem) FilterValue() string {
+	return i.Download.Title + " " + i.Download.Channel + " " + i.Download.VideoID + " " + i.Download.Destination
+}
+
+type DownloadsModel struct {
+	Visible bool
+	List    list.Model
+	Width   int
+	Height  int
+}
+
+func NewDownloadsModel() DownloadsModel {
+	dl := styles.NewListDelegate()
+	li := list.New([]list.Item{}, dl, 0, 0)
+	li.SetShowStatusBar(false)
+	li.SetShowTitle(false)
+	li.SetShowHelp(false)
+	li.KeyMap.Quit.SetKeys("q")
+	li.FilterInput.Cursor.Style = li.FilterInput.Cursor.Style.Foreground(styles.MauveColor)
+	li.FilterInput.PromptStyle = li.FilterInput.PromptStyle.Foreground(styles.SecondaryColor)
+
+	return DownloadsModel{
+		Visible: false,
+		List:    li,
+		Width:   60,
+		Height:  10,
+	}
+}
+
+func (m *DownloadsModel) Show() {
+	m.Visible = true
+	m.LoadItems()
+}
+
+func (m *DownloadsModel) Hide() {
+	m.Visible = false
+	m.List.SetItems([]list.Item{})
+}
+
+func (m *DownloadsModel) LoadItems() {
+	items, err := utils.LoadDownloads()
+	if err != nil {
+		log.Printf("Failed to load downloads log: %v", err)
+		m.List.SetItems([]list.Item{})
+		return
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Timestamp.After(items[j].Timestamp)
+	})
+
+	listItems := make([]list.Item, len(items))
+	for i, item := range items {
+		listItems[i] = DownloadItem{Download: item}
+	}
+
+	m.List.SetItems(listItems)
+}
+
+func (m *DownloadsModel) HandleResize(width, height int) {
+	m.Width = width
+	m.Height = height
+	m.List.SetSize(width, height-7)
+}
+
+func (m *DownloadsModel) DeleteSelected() {
+	if item := m.SelectedItem(); item != nil {
+		if err := utils.RemoveDownload(*item); err != nil {
+			log.Printf("Failed to remove download entry: %v", err)
+		}
+
+		m.LoadItems()
+	}
+}
+
+func (m *DownloadsModel) SelectedItem() *utils.CompletedDownload {
+	if item, ok := m.List.SelectedItem().(DownloadItem); ok {
+		download := item.Download
+		return &download
+	}
+
+	return nil
+}
+
+func (m *DownloadsModel) View(width, height int) string {
+	if !m.Visible {
+		return ""
+	}
+
+	var headerText string
+	if m.List.FilterState() == list.FilterApplied {
+		headerText = "Filtered Results"
+	} else {
+		headerText = "Downloads"
+	}
+
+	return styles.SectionHeaderStyle.Render(headerText) + "\n" + styles.ListContainer.Render(m.List.View())
+}
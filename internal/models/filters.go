@@ -0,0 +1,118 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/xdagiz/xytz/internal/styles"
+	"github.com/xdagiz/xytz/internal/types"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	filterRowUploadDate = iota
+	filterRowDuration
+	filterRowType
+	filterRowFeatures
+)
+
+// FilterPanelModel edits the search filters. Its rows are the upload date,
+// duration and type, followed by one row per feature.
+type FilterPanelModel struct {
+	Visible bool
+	Filters types.SearchFilters
+	cursor  int
+}
+
+func NewFilterPanelModel(filters types.SearchFilters) FilterPanelModel {
+	return FilterPanelModel{Filters: filters}
+}
+
+func (m *FilterPanelModel) Show() {
+	m.Visible = true
+}
+
+func (m *FilterPanelModel) Hide() {
+	m.Visible = false
+}
+
+func (m FilterPanelModel) rows() int {
+	return filterRowFeatures + len(types.SearchFeatures)
+}
+
+func (m FilterPanelModel) Update(msg tea.KeyMsg) FilterPanelModel {
+	switch msg.String() {
+	case "esc", "enter", "ctrl+f":
+		m.Hide()
+
+	case "up", "k", "ctrl+p":
+		m.cursor = (m.cursor - 1 + m.rows()) % m.rows()
+
+	case "down", "j", "ctrl+n", "tab":
+		m.cursor = (m.cursor + 1) % m.rows()
+
+	case "right", "l", " ":
+		m.change(1)
+
+	case "left", "h":
+		m.change(-1)
+
+	case "x", "backspace":
+		m.Filters = types.SearchFilters{}
+	}
+
+	return m
+}
+
+func (m *FilterPanelModel) change(delta int) {
+	switch m.cursor {
+	case filterRowUploadDate:
+		m.Filters.UploadDate = types.CycleChoice(types.UploadDates, m.Filters.UploadDate, delta)
+	case filterRowDuration:
+		m.Filters.Duration = types.CycleChoice(types.Durations, m.Filters.Duration, delta)
+	case filterRowType:
+		m.Filters.Type = types.CycleChoice(types.ResultTypes, m.Filters.Type, delta)
+	default:
+		m.Filters.ToggleFeature(types.SearchFeatures[m.cursor-filterRowFeatures])
+	}
+}
+
+func (m FilterPanelModel) View() string {
+	var s strings.Builder
+
+	s.WriteString(styles.SortTitle.Render("Search Filters"))
+	s.WriteString(styles.SortHelp.Render("(←/→ to change)"))
+	s.WriteRune('\n')
+
+	choices := []struct {
+		name  string
+		value string
+	}{
+		{"Upload date", m.Filters.UploadDate.GetDisplayName()},
+		{"Duration", m.Filters.Duration.GetDisplayName()},
+		{"Type", m.Filters.Type.GetDisplayName()},
+	}
+	for i, choice := range choices {
+		fmt.Fprintf(&s, "%s %-12s ‹ %s ›\n", styles.SortItem.Render(m.pointer(i)), choice.name, choice.value)
+	}
+
+	for i, feature := range types.SearchFeatures {
+		indicator := "○"
+		if m.Filters.HasFeature(feature) {
+			indicator = "◉"
+		}
+
+		fmt.Fprintf(&s, "%s %s %s\n", styles.SortItem.Render(m.pointer(filterRowFeatures+i)), styles.SortItem.Render(indicator), feature.GetDisplayName())
+	}
+
+	return s.String()
+}
+
+func (m FilterPanelModel) pointer(row int) string {
+	if row == m.cursor {
+		return ">"
+	}
+
+	return " "
+}
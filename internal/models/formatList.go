@@ -3,9 +3,12 @@ package models
 import (
 	"fmt"
 	"log"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/xdagiz/xytz/internal/config"
 	"github.com/xdagiz/xytz/internal/styles"
 	"github.com/xdagiz/xytz/internal/types"
 	"github.com/xdagiz/xytz/internal/utils"
@@ -22,10 +25,11 @@ const (
 	FormatTabVideo FormatTab = iota
 	FormatTabAudio
 	FormatTabThumbnail
+	FormatTabSubtitles
 	FormatTabCustom
 )
 
-var formatTabNames = []string{"Video", "Audio", "Thumbnail", "Custom"}
+var formatTabNames = []string{"Video", "Audio", "Thumbnail", "Subtitles", "Custom"}
 
 type FormatListModel struct {
 	Width            int
@@ -37,13 +41,39 @@ type FormatListModel struct {
 	SelectedVideo    types.VideoItem
 	IsQueue          bool
 	QueueVideos      []types.VideoItem
+	QueueLabel       string
 	DownloadOptions  []types.DownloadOption
 	ActiveTab        FormatTab
 	VideoFormats     []list.Item
 	AudioFormats     []list.Item
 	ThumbnailFormats []list.Item
+	SubtitleFormats  []list.Item
 	AllFormats       []list.Item
 	ShowVideoInfo    bool
+	VideoTemplate    string
+	AudioTemplate    string
+	VideoFormat      string
+	AudioFormat      string
+	ThumbnailFormat  string
+	Clip             types.ClipRange
+	ClipEditing      bool
+	ClipStartInput   textinput.Model
+	ClipEndInput     textinput.Model
+	ClipErr          string
+	// SelectedSubtitles are embedded into video downloads, or downloaded on
+	// their own as SubtitleFormat files from the Subtitles tab.
+	SelectedSubtitles []types.SubtitleItem
+	SubtitleFormat    string
+	Confirm           ConfirmPrompt
+	SavePrompt        CollectionPromptModel
+}
+
+// ConfirmPrompt asks whether to start a download anyway, when it likely
+// won't fit on disk or is already in the archive. Pending is sent again when
+// the user confirms.
+type ConfirmPrompt struct {
+	Message string
+	Pending tea.Msg
 }
 
 func NewFormatListModel() FormatListModel {
@@ -64,14 +94,39 @@ func NewFormatListModel() FormatListModel {
 	ti.TextStyle = ti.TextStyle.Foreground(styles.SecondaryColor)
 	ti.Focus()
 
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = config.GetDefault()
+	}
+
 	return FormatListModel{
-		List:         li,
-		CustomInput:  ti,
-		Autocomplete: NewFormatAutocompleteModel(),
-		ActiveTab:    FormatTabVideo,
+		List:            li,
+		CustomInput:     ti,
+		Autocomplete:    NewFormatAutocompleteModel(),
+		ActiveTab:       FormatTabVideo,
+		VideoTemplate:   cfg.VideoOutputTemplate,
+		AudioTemplate:   cfg.AudioOutputTemplate,
+		VideoFormat:     cfg.VideoFormat,
+		AudioFormat:     cfg.AudioFormat,
+		ThumbnailFormat: cfg.ThumbnailFormat,
+		ClipStartInput:  newClipInput("start"),
+		ClipEndInput:    newClipInput("end"),
+		SubtitleFormat:  types.SubtitleFormatSRT,
+		SavePrompt:      NewCollectionPromptModel(),
 	}
 }
 
+func newClipInput(placeholder string) textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = placeholder
+	ti.Prompt = ""
+	ti.Width = 10
+	ti.CharLimit = 12
+	ti.PlaceholderStyle = ti.PlaceholderStyle.Foreground(styles.MutedColor)
+	ti.TextStyle = ti.TextStyle.Foreground(styles.SecondaryColor)
+	return ti
+}
+
 func (m FormatListModel) Init() tea.Cmd {
 	return nil
 }
@@ -115,6 +170,28 @@ func (m FormatListModel) View() string {
 		s.WriteRune('\n')
 	}
 
+	if preview := m.OutputPreview(); preview != "" {
+		s.WriteString(styles.MutedStyle.Render(fmt.Sprintf("💾 %s", preview)))
+		s.WriteRune('\n')
+	}
+
+	if clip := m.clipView(); clip != "" {
+		s.WriteString(clip)
+		s.WriteRune('\n')
+	}
+
+	if m.SavePrompt.Visible {
+		s.WriteString(m.SavePrompt.View())
+		s.WriteRune('\n')
+	}
+
+	if m.Confirming() {
+		s.WriteString(styles.ErrorMessageStyle.Render("⚠  " + m.Confirm.Message))
+		s.WriteRune('\n')
+		s.WriteString(styles.MutedStyle.Render("Download anyway? (y/n)"))
+		s.WriteRune('\n')
+	}
+
 	s.WriteString(styles.SectionHeaderStyle.Foreground(styles.MauveColor).Padding(1, 0).Render("Select a Format"))
 	s.WriteRune('\n')
 
@@ -134,12 +211,85 @@ func (m FormatListModel) View() string {
 			s.WriteString(styles.CustomFormatContainerStyle.Render(styles.FormatCustomHelpStyle.Render("Type to search formats.")))
 		}
 	} else {
+		if m.ActiveTab == FormatTabSubtitles {
+			s.WriteString(container.Render(styles.FormatCustomHelpStyle.Render(m.subtitleHelp())))
+			s.WriteRune('\n')
+		}
+
 		s.WriteString(container.Render(styles.ListContainer.Render(m.List.View())))
 	}
 
 	return s.String()
 }
 
+func (m FormatListModel) subtitleHelp() string {
+	if len(m.SubtitleFormats) == 0 {
+		return "No subtitles available for this video."
+	}
+
+	selected := "none selected"
+	if len(m.SelectedSubtitles) > 0 {
+		keys := make([]string, len(m.SelectedSubtitles))
+		for i, sub := range m.SelectedSubtitles {
+			keys[i] = sub.Key()
+		}
+		selected = strings.Join(keys, ", ")
+	}
+
+	return fmt.Sprintf("%s • ␣ select • t: .%s • Enter: download subtitles only\nSelected languages are embedded in video downloads.", selected, m.SubtitleFormat)
+}
+
+// OutputPreview resolves the configured output template for the selected
+// video, so the filename can be checked before the download starts.
+func (m FormatListModel) OutputPreview() string {
+	video := m.SelectedVideo
+	if m.IsQueue && len(m.QueueVideos) > 0 {
+		video = m.QueueVideos[0]
+	}
+
+	if video.ID == "" {
+		return ""
+	}
+
+	tmpl, ext := m.VideoTemplate, m.VideoFormat
+	switch m.ActiveTab {
+	case FormatTabAudio:
+		tmpl, ext = m.AudioTemplate, m.AudioFormat
+	case FormatTabThumbnail:
+		ext = m.ThumbnailFormat
+	}
+
+	if tmpl == "" {
+		return ""
+	}
+
+	return utils.ResolveOutputTemplate(tmpl, utils.OutputTemplateFields(video, ext))
+}
+
+func (m FormatListModel) clipView() string {
+	if m.ClipEditing {
+		var s strings.Builder
+		s.WriteString(styles.FormatCustomInputPrompt.Render("✂  Clip "))
+		s.WriteString(m.ClipStartInput.View())
+		s.WriteString(styles.MutedStyle.Render(" → "))
+		s.WriteString(m.ClipEndInput.View())
+		s.WriteRune('\n')
+		if m.ClipErr != "" {
+			s.WriteString(styles.ErrorMessageStyle.Render(m.ClipErr))
+		} else {
+			s.WriteString(styles.MutedStyle.Render("Leave both empty to download the whole video"))
+		}
+
+		return s.String()
+	}
+
+	if !m.Clip.IsZero() {
+		return styles.MutedStyle.Render(fmt.Sprintf("✂  Clip %s (%s)", utils.FormatClipRange(m.Clip), utils.FormatDuration(m.Clip.Duration())))
+	}
+
+	return ""
+}
+
 func (m FormatListModel) renderTabs() string {
 	var tabBar strings.Builder
 
@@ -165,7 +315,25 @@ func (m FormatListModel) HandleResize(w, h int) FormatListModel {
 	m.Width = w
 	m.Height = h
 
-	baseReserved := 16
+	baseReserved := 17
+	if m.ActiveTab == FormatTabSubtitles {
+		baseReserved += 3
+	}
+
+	if m.ClipEditing {
+		baseReserved += 2
+	} else if !m.Clip.IsZero() {
+		baseReserved++
+	}
+
+	if m.Confirming() {
+		baseReserved += 2
+	}
+
+	if m.SavePrompt.Visible {
+		baseReserved += 2
+	}
+
 	if m.IsQueue && len(m.QueueVideos) > 0 {
 		display := min(len(m.QueueVideos), 10)
 		queueLines := 3 + display
@@ -189,6 +357,19 @@ func (m FormatListModel) Update(msg tea.Msg) (FormatListModel, tea.Cmd) {
 		listCmd tea.Cmd
 	)
 
+	if m.ClipEditing {
+		return m.updateClip(msg)
+	}
+
+	if m.Confirming() {
+		return m.updateConfirm(msg)
+	}
+
+	if m.SavePrompt.Visible {
+		m.SavePrompt, cmd = m.SavePrompt.Update(msg)
+		return m.HandleResize(m.Width, m.Height), cmd
+	}
+
 	handled, autocompleteCmd := m.Autocomplete.Update(msg)
 	if handled {
 		if keyMsg, ok := msg.(tea.KeyMsg); ok {
@@ -224,10 +405,16 @@ func (m FormatListModel) Update(msg tea.Msg) (FormatListModel, tea.Cmd) {
 		switch {
 		case key.Matches(msg, formatTabNext):
 			m.nextTab()
-			return m, nil
+			return m.HandleResize(m.Width, m.Height), nil
 		case key.Matches(msg, formatTabPrev):
 			m.prevTab()
-			return m, nil
+			return m.HandleResize(m.Width, m.Height), nil
+		}
+
+		if m.ActiveTab == FormatTabSubtitles && m.List.FilterState() != list.Filtering {
+			if handled, subCmd := m.updateSubtitles(msg); handled {
+				return m, subCmd
+			}
 		}
 
 		switch msg.Type {
@@ -235,24 +422,12 @@ func (m FormatListModel) Update(msg tea.Msg) (FormatListModel, tea.Cmd) {
 			if m.ActiveTab == FormatTabCustom {
 				formatID := strings.TrimSpace(m.CustomInput.Value())
 				if formatID != "" {
-					cmd = func() tea.Msg {
-						if m.IsQueue && len(m.QueueVideos) > 0 {
-							return types.StartQueueDownloadMsg{
-								FormatID:        formatID,
-								IsAudioTab:      false,
-								ABR:             0,
-								DownloadOptions: m.DownloadOptions,
-								Videos:          m.QueueVideos,
-							}
-						}
-
-						return types.StartDownloadMsg{
-							URL:             m.URL,
-							FormatID:        formatID,
-							IsAudioTab:      false,
-							ABR:             0,
-							DownloadOptions: m.DownloadOptions,
-						}
+					size := m.customFormatSize(formatID)
+					cmd = m.startDownload(formatID, false, 0, size)
+					if size == 0 {
+						cmd = tea.Batch(cmd, func() tea.Msg {
+							return types.ShowToastMsg{Message: "size of " + formatID + " unknown: free space not checked"}
+						})
 					}
 				}
 
@@ -273,35 +448,27 @@ func (m FormatListModel) Update(msg tea.Msg) (FormatListModel, tea.Cmd) {
 				return m, nil
 			}
 
+			if thumb, ok := item.(types.ThumbnailItem); ok {
+				return m, m.startThumbnailDownload(thumb)
+			}
+
 			format, ok := item.(types.FormatItem)
 			if !ok {
 				return m, nil
 			}
 
-			if m.IsQueue && len(m.QueueVideos) > 0 {
-				cmd = func() tea.Msg {
-					return types.StartQueueDownloadMsg{
-						FormatID:        format.FormatValue,
-						IsAudioTab:      m.ActiveTab == FormatTabAudio,
-						ABR:             format.ABR,
-						DownloadOptions: m.DownloadOptions,
-						Videos:          m.QueueVideos,
-					}
-				}
-			} else {
-				cmd = func() tea.Msg {
-					return types.StartDownloadMsg{
-						URL:             m.URL,
-						FormatID:        format.FormatValue,
-						IsAudioTab:      m.ActiveTab == FormatTabAudio,
-						ABR:             format.ABR,
-						DownloadOptions: m.DownloadOptions,
-					}
-				}
-			}
+			cmd = m.startDownload(format.FormatValue, m.ActiveTab == FormatTabAudio, format.ABR, format.Bytes())
 		}
 
 		switch msg.String() {
+		case "ctrl+x":
+			if !m.IsQueue {
+				m.startClipEdit()
+				return m.HandleResize(m.Width, m.Height), textinput.Blink
+			}
+
+			return m, nil
+
 		case "ctrl+y":
 			if m.SelectedVideo.ID != "" {
 				url := utils.BuildVideoURL(m.SelectedVideo.ID)
@@ -315,6 +482,21 @@ func (m FormatListModel) Update(msg tea.Msg) (FormatListModel, tea.Cmd) {
 
 				return m, cmd
 			}
+
+		case "ctrl+w":
+			var videos []types.VideoItem
+			if m.IsQueue {
+				videos = m.QueueVideos
+			} else if m.SelectedVideo.ID != "" {
+				videos = []types.VideoItem{m.SelectedVideo}
+			}
+
+			if len(videos) > 0 {
+				cmd = m.SavePrompt.Show(videos)
+				return m.HandleResize(m.Width, m.Height), cmd
+			}
+
+			return m, nil
 		}
 	}
 
@@ -362,6 +544,8 @@ func (m *FormatListModel) updateListForTab() {
 		m.List.SetItems(m.AudioFormats)
 	case FormatTabThumbnail:
 		m.List.SetItems(m.ThumbnailFormats)
+	case FormatTabSubtitles:
+		m.List.SetItems(m.subtitleItems())
 	case FormatTabCustom:
 		m.List.SetItems([]list.Item{})
 	}
@@ -377,6 +561,172 @@ func (m *FormatListModel) SetFormats(videoFormats, audioFormats, thumbnailFormat
 	m.updateListForTab()
 }
 
+// startDownload starts formatID for the queue or the single video, with the
+// clip and subtitles picked on this screen. size is the format's expected
+// size in bytes, 0 if unknown.
+func (m FormatListModel) startDownload(formatID string, isAudio bool, abr float64, size int64) tea.Cmd {
+	if m.IsQueue && len(m.QueueVideos) > 0 {
+		return func() tea.Msg {
+			return types.StartQueueDownloadMsg{
+				FormatID:        formatID,
+				IsAudioTab:      isAudio,
+				ABR:             abr,
+				DownloadOptions: m.DownloadOptions,
+				Videos:          m.QueueVideos,
+				EstimatedSize:   size,
+				Label:           m.QueueLabel,
+			}
+		}
+	}
+
+	return func() tea.Msg {
+		return types.StartDownloadMsg{
+			URL:             m.URL,
+			FormatID:        formatID,
+			IsAudioTab:      isAudio,
+			ABR:             abr,
+			DownloadOptions: m.DownloadOptions,
+			Clip:            m.Clip,
+			Subtitles:       m.subtitleRequest(false),
+			EstimatedSize:   m.estimatedSize(size),
+		}
+	}
+}
+
+func (m FormatListModel) startThumbnailDownload(thumb types.ThumbnailItem) tea.Cmd {
+	if m.IsQueue {
+		return func() tea.Msg {
+			return types.ShowToastMsg{Message: "thumbnail downloads are only available for single videos"}
+		}
+	}
+
+	return func() tea.Msg {
+		return types.StartDownloadMsg{
+			URL:             m.URL,
+			DownloadOptions: m.DownloadOptions,
+			Thumbnail:       thumb,
+		}
+	}
+}
+
+// SetSubtitles replaces the subtitle tracks and drops the previous
+// selection, which belonged to another video.
+func (m *FormatListModel) SetSubtitles(items []list.Item) {
+	m.SubtitleFormats = items
+	m.SelectedSubtitles = nil
+	if m.ActiveTab == FormatTabSubtitles {
+		m.updateListForTab()
+	}
+}
+
+func (m FormatListModel) subtitleItems() []list.Item {
+	items := make([]list.Item, len(m.SubtitleFormats))
+	for i, item := range m.SubtitleFormats {
+		if sub, ok := item.(types.SubtitleItem); ok {
+			sub.IsSelected = m.isSubtitleSelected(sub)
+			item = sub
+		}
+
+		items[i] = item
+	}
+
+	return items
+}
+
+func (m FormatListModel) isSubtitleSelected(sub types.SubtitleItem) bool {
+	for _, s := range m.SelectedSubtitles {
+		if s.Key() == sub.Key() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *FormatListModel) toggleSubtitle(sub types.SubtitleItem) {
+	for i, s := range m.SelectedSubtitles {
+		if s.Key() == sub.Key() {
+			m.SelectedSubtitles = append(m.SelectedSubtitles[:i], m.SelectedSubtitles[i+1:]...)
+			return
+		}
+	}
+
+	sub.IsSelected = false
+	m.SelectedSubtitles = append(m.SelectedSubtitles, sub)
+}
+
+// refreshSubtitleItems redraws the selection marks without moving the cursor.
+func (m *FormatListModel) refreshSubtitleItems() {
+	index := m.List.Index()
+	m.List.SetItems(m.subtitleItems())
+	m.List.Select(index)
+}
+
+func (m FormatListModel) subtitleRequest(only bool) types.SubtitleRequest {
+	req := types.SubtitleRequest{Format: m.SubtitleFormat, Only: only}
+	for _, sub := range m.SelectedSubtitles {
+		langs := &req.Languages
+		if sub.Auto {
+			langs = &req.AutoLanguages
+		}
+
+		if !slices.Contains(*langs, sub.Language) {
+			*langs = append(*langs, sub.Language)
+		}
+	}
+
+	return req
+}
+
+func (m *FormatListModel) updateSubtitles(msg tea.KeyMsg) (bool, tea.Cmd) {
+	switch msg.String() {
+	case " ":
+		if sub, ok := m.List.SelectedItem().(types.SubtitleItem); ok {
+			m.toggleSubtitle(sub)
+			m.refreshSubtitleItems()
+		}
+
+		return true, nil
+
+	case "t":
+		if m.SubtitleFormat == types.SubtitleFormatSRT {
+			m.SubtitleFormat = types.SubtitleFormatVTT
+		} else {
+			m.SubtitleFormat = types.SubtitleFormatSRT
+		}
+
+		return true, nil
+
+	case "enter":
+		if m.IsQueue {
+			return true, func() tea.Msg {
+				return types.ShowToastMsg{Message: "subtitle downloads are only available for single videos"}
+			}
+		}
+
+		if len(m.SelectedSubtitles) == 0 {
+			sub, ok := m.List.SelectedItem().(types.SubtitleItem)
+			if !ok {
+				return true, nil
+			}
+
+			m.toggleSubtitle(sub)
+			m.refreshSubtitleItems()
+		}
+
+		req := m.subtitleRequest(true)
+		return true, func() tea.Msg {
+			return types.StartDownloadMsg{
+				URL:             m.URL,
+				DownloadOptions: m.DownloadOptions,
+				Subtitles:       req,
+			}
+		}
+	}
+
+	return false, nil
+}
+
 func (m *FormatListModel) ClearSelection() {
 	m.List.Select(-1)
 	m.CustomInput.SetValue("")
@@ -390,6 +740,158 @@ func (m *FormatListModel) ResetTab() {
 	m.updateListForTab()
 }
 
+func (m *FormatListModel) startClipEdit() {
+	m.ClipEditing = true
+	m.ClipErr = ""
+	m.ClipStartInput.SetValue("")
+	m.ClipEndInput.SetValue("")
+	if !m.Clip.IsZero() {
+		m.ClipStartInput.SetValue(utils.FormatDuration(m.Clip.Start))
+		m.ClipEndInput.SetValue(utils.FormatDuration(m.Clip.End))
+	}
+
+	m.ClipStartInput.CursorEnd()
+	m.ClipEndInput.CursorEnd()
+	m.ClipStartInput.Focus()
+	m.ClipEndInput.Blur()
+}
+
+func (m *FormatListModel) stopClipEdit() {
+	m.ClipEditing = false
+	m.ClipErr = ""
+	m.ClipStartInput.Blur()
+	m.ClipEndInput.Blur()
+}
+
+// customFormatSize adds up the sizes of the formats in a custom format ID
+// like "137+140". It returns 0 when any part is a selector like "bestvideo"
+// or has no known size.
+func (m FormatListModel) customFormatSize(formatID string) int64 {
+	var total int64
+	for _, id := range strings.Split(formatID, "+") {
+		var size int64
+		for _, item := range m.AllFormats {
+			if format, ok := item.(types.FormatItem); ok && format.FormatValue == id {
+				size = format.Bytes()
+				break
+			}
+		}
+
+		if size == 0 {
+			return 0
+		}
+
+		total += size
+	}
+
+	return total
+}
+
+// estimatedSize scales the format size down to the clip, if there is one.
+func (m FormatListModel) estimatedSize(size int64) int64 {
+	if !m.Clip.IsZero() && m.SelectedVideo.Duration > 0 {
+		return int64(float64(size) * m.Clip.Duration() / m.SelectedVideo.Duration)
+	}
+
+	return size
+}
+
+// applyClip validates the editor against the video duration. Leaving both
+// fields empty clears the clip.
+func (m *FormatListModel) applyClip() bool {
+	start := strings.TrimSpace(m.ClipStartInput.Value())
+	end := strings.TrimSpace(m.ClipEndInput.Value())
+	if start == "" && end == "" {
+		m.Clip = types.ClipRange{}
+		return true
+	}
+
+	if start == "" {
+		start = "0"
+	}
+
+	if end == "" && m.SelectedVideo.Duration > 0 {
+		end = strconv.FormatFloat(m.SelectedVideo.Duration, 'f', -1, 64)
+	}
+
+	clip, err := utils.ParseClipRange(start, end, m.SelectedVideo.Duration)
+	if err != nil {
+		m.ClipErr = err.Error()
+		return false
+	}
+
+	m.Clip = clip
+	return true
+}
+
+func (m FormatListModel) updateClip(msg tea.Msg) (FormatListModel, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "esc":
+			m.stopClipEdit()
+			return m.HandleResize(m.Width, m.Height), nil
+
+		case "enter":
+			if m.applyClip() {
+				m.stopClipEdit()
+				return m.HandleResize(m.Width, m.Height), nil
+			}
+
+			return m, nil
+
+		case "tab", "shift+tab":
+			if m.ClipStartInput.Focused() {
+				m.ClipStartInput.Blur()
+				m.ClipEndInput.Focus()
+			} else {
+				m.ClipEndInput.Blur()
+				m.ClipStartInput.Focus()
+			}
+
+			return m, nil
+		}
+	}
+
+	var startCmd, endCmd tea.Cmd
+	m.ClipStartInput, startCmd = m.ClipStartInput.Update(msg)
+	m.ClipEndInput, endCmd = m.ClipEndInput.Update(msg)
+	return m, tea.Batch(startCmd, endCmd)
+}
+
+func (m FormatListModel) Confirming() bool {
+	return m.Confirm.Pending != nil
+}
+
+func (m FormatListModel) AskConfirm(message string, pending tea.Msg) FormatListModel {
+	m.Confirm = ConfirmPrompt{Message: message, Pending: pending}
+	return m.HandleResize(m.Width, m.Height)
+}
+
+func (m FormatListModel) updateConfirm(msg tea.Msg) (FormatListModel, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "y", "enter":
+		pending := m.Confirm.Pending
+		m.Confirm = ConfirmPrompt{}
+		return m.HandleResize(m.Width, m.Height), func() tea.Msg { return pending }
+
+	case "n", "esc":
+		m.Confirm = ConfirmPrompt{}
+		return m.HandleResize(m.Width, m.Height), nil
+	}
+
+	return m, nil
+}
+
+func (m *FormatListModel) ResetClip() {
+	m.stopClipEdit()
+	m.Clip = types.ClipRange{}
+}
+
 var (
 	formatTabNext = key.NewBinding(key.WithKeys("tab"))
 	formatTabPrev = key.NewBinding(key.WithKeys("shift+tab"))
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/charmbracelet/bubbles/list"
@@ -104,7 +105,7 @@ func TestFormatListCustomEnterQueueReturnsStartQueueDownload(t *testing.T) {
 	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
 	m = updated
 
-	msg := cmdMsg(t, cmd)
+	msg := batchMsgs(t, cmd)[0]
 	got, ok := msg.(types.StartQueueDownloadMsg)
 	if !ok {
 		t.Fatalf("cmd msg type = %T, want types.StartQueueDownloadMsg", msg)
@@ -116,3 +117,367 @@ func TestFormatListCustomEnterQueueReturnsStartQueueDownload(t *testing.T) {
 		t.Fatalf("Videos len = %d, want 2", len(got.Videos))
 	}
 }
+
+// batchMsgs runs cmd and the commands of a batch it returns.
+func batchMsgs(t *testing.T, cmd tea.Cmd) []tea.Msg {
+	t.Helper()
+
+	batch, ok := cmdMsg(t, cmd).(tea.BatchMsg)
+	if !ok {
+		return []tea.Msg{cmd()}
+	}
+
+	var msgs []tea.Msg
+	for _, c := range batch {
+		if c != nil {
+			msgs = append(msgs, c())
+		}
+	}
+
+	return msgs
+}
+
+func TestFormatListCustomEnterEstimatesSize(t *testing.T) {
+	setupModelTestEnv(t)
+
+	m := NewFormatListModel()
+	m.URL = "https://www.youtube.com/watch?v=abc"
+	m.SelectedVideo = types.VideoItem{ID: "abc", Duration: 100}
+	m.AllFormats = []list.Item{
+		types.FormatItem{FormatValue: "137", VideoSize: 1000},
+		types.FormatItem{FormatValue: "140", AudioSize: 200},
+	}
+	m.ActiveTab = FormatTabCustom
+
+	m.CustomInput.SetValue("137+140")
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	msgs := batchMsgs(t, cmd)
+	if got, ok := msgs[0].(types.StartDownloadMsg); len(msgs) != 1 || !ok || got.EstimatedSize != 1200 {
+		t.Fatalf("msgs = %+v, want one download of 1200 bytes", msgs)
+	}
+
+	m.CustomInput.SetValue("bestvideo+140")
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	msgs = batchMsgs(t, cmd)
+	if len(msgs) != 2 {
+		t.Fatalf("msgs = %+v, want the download and a toast", msgs)
+	}
+	if got := msgs[0].(types.StartDownloadMsg); got.EstimatedSize != 0 {
+		t.Fatalf("EstimatedSize = %d, want 0 for a selector", got.EstimatedSize)
+	}
+	if toast, ok := msgs[1].(types.ShowToastMsg); !ok || toast.Message != "size of bestvideo+140 unknown: free space not checked" {
+		t.Fatalf("msgs[1] = %+v, want an unknown size toast", msgs[1])
+	}
+}
+
+func TestFormatListCustomEnterKeepsClip(t *testing.T) {
+	setupModelTestEnv(t)
+
+	m := NewFormatListModel()
+	m.URL = "https://www.youtube.com/watch?v=abc"
+	m.ActiveTab = FormatTabCustom
+	m.Clip = types.ClipRange{Start: 10, End: 40}
+	m.CustomInput.SetValue("137+140")
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	got, ok := batchMsgs(t, cmd)[0].(types.StartDownloadMsg)
+	if !ok {
+		t.Fatalf("cmd msg type = %T, want types.StartDownloadMsg", batchMsgs(t, cmd)[0])
+	}
+	if got.FormatID != "137+140" || got.Clip != m.Clip {
+		t.Fatalf("StartDownloadMsg = %+v, want the custom format with the clip", got)
+	}
+}
+
+func TestFormatListCustomEnterEmbedsSelectedSubtitles(t *testing.T) {
+	setupModelTestEnv(t)
+
+	m := NewFormatListModel()
+	m.URL = "https://www.youtube.com/watch?v=abc"
+	m.SubtitleFormat = types.SubtitleFormatSRT
+	m.SelectedSubtitles = []types.SubtitleItem{
+		{Language: "en", Name: "English", Formats: []string{"vtt"}},
+		{Language: "de", Name: "German", Auto: true, Formats: []string{"vtt"}},
+	}
+	m.ActiveTab = FormatTabCustom
+	m.CustomInput.SetValue("bestvideo+bestaudio")
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	got, ok := batchMsgs(t, cmd)[0].(types.StartDownloadMsg)
+	if !ok {
+		t.Fatalf("cmd msg type = %T, want types.StartDownloadMsg", batchMsgs(t, cmd)[0])
+	}
+
+	want := types.SubtitleRequest{Languages: []string{"en"}, AutoLanguages: []string{"de"}, Format: types.SubtitleFormatSRT}
+	if !reflect.DeepEqual(got.Subtitles, want) {
+		t.Fatalf("Subtitles = %+v, want %+v", got.Subtitles, want)
+	}
+}
+
+func TestFormatListOutputPreviewFollowsActiveTab(t *testing.T) {
+	setupModelTestEnv(t)
+
+	m := NewFormatListModel()
+	m.SelectedVideo = types.VideoItem{ID: "abc", VideoTitle: "Song", Channel: "Band", UploadDate: "20240101"}
+	m.VideoTemplate = "%(upload_date)s - %(title)s [%(id)s].%(ext)s"
+	m.AudioTemplate = "%(channel)s - %(title)s.%(ext)s"
+	m.VideoFormat = "mkv"
+	m.AudioFormat = "opus"
+
+	if got, want := m.OutputPreview(), "20240101 - Song [abc].mkv"; got != want {
+		t.Fatalf("video OutputPreview() = %q, want %q", got, want)
+	}
+
+	m.ActiveTab = FormatTabAudio
+	if got, want := m.OutputPreview(), "Band - Song.opus"; got != want {
+		t.Fatalf("audio OutputPreview() = %q, want %q", got, want)
+	}
+
+	m.SelectedVideo = types.VideoItem{}
+	if got := m.OutputPreview(); got != "" {
+		t.Fatalf("OutputPreview() without video = %q, want empty", got)
+	}
+}
+
+func TestFormatListClipEditAppliesRangeToDownload(t *testing.T) {
+	setupModelTestEnv(t)
+
+	m := NewFormatListModel()
+	m.URL = "https://www.youtube.com/watch?v=abc"
+	m.SelectedVideo = types.VideoItem{ID: "abc", VideoTitle: "Stream", Duration: 7200}
+	m.SetFormats(
+		[]list.Item{types.FormatItem{FormatTitle: "1080p", FormatValue: "137+140"}},
+		nil,
+		nil,
+		nil,
+	)
+	m.List.Select(0)
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlX})
+	m = updated
+	if !m.ClipEditing {
+		t.Fatalf("expected ctrl+x to open the clip editor")
+	}
+
+	steps := []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune("1:00:00")},
+		{Type: tea.KeyTab},
+		{Type: tea.KeyRunes, Runes: []rune("1:01:30")},
+		{Type: tea.KeyEnter},
+	}
+	for _, step := range steps {
+		updated, _ = m.Update(step)
+		m = updated
+	}
+
+	if m.ClipEditing {
+		t.Fatalf("expected enter to close the clip editor, error: %q", m.ClipErr)
+	}
+	want := types.ClipRange{Start: 3600, End: 3690}
+	if m.Clip != want {
+		t.Fatalf("Clip = %+v, want %+v", m.Clip, want)
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated
+
+	msg := cmdMsg(t, cmd)
+	got, ok := msg.(types.StartDownloadMsg)
+	if !ok {
+		t.Fatalf("cmd msg type = %T, want types.StartDownloadMsg", msg)
+	}
+	if got.Clip != want {
+		t.Fatalf("StartDownloadMsg.Clip = %+v, want %+v", got.Clip, want)
+	}
+}
+
+func TestFormatListClipEditRejectsRangePastDuration(t *testing.T) {
+	setupModelTestEnv(t)
+
+	m := NewFormatListModel()
+	m.SelectedVideo = types.VideoItem{ID: "abc", Duration: 120}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlX})
+	m = updated
+	m.ClipStartInput.SetValue("1:00")
+	m.ClipEndInput.SetValue("3:00")
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated
+
+	if !m.ClipEditing {
+		t.Fatalf("expected the clip editor to stay open on an invalid range")
+	}
+	if m.ClipErr == "" {
+		t.Fatalf("expected a validation error")
+	}
+	if !m.Clip.IsZero() {
+		t.Fatalf("Clip = %+v, want none", m.Clip)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated
+	if m.ClipEditing {
+		t.Fatalf("expected esc to close the clip editor")
+	}
+}
+
+func TestFormatListSubtitlesTabDownloadsSelectedLanguagesOnly(t *testing.T) {
+	setupModelTestEnv(t)
+
+	m := NewFormatListModel()
+	m.URL = "https://www.youtube.com/watch?v=abc"
+	m.SetSubtitles([]list.Item{
+		types.SubtitleItem{Language: "en", Name: "English", Formats: []string{"vtt"}},
+		types.SubtitleItem{Language: "de", Name: "German", Auto: true, Formats: []string{"vtt"}},
+	})
+	m.SetFormats(nil, nil, nil, nil)
+	m.ActiveTab = FormatTabSubtitles
+	m.updateListForTab()
+
+	steps := []tea.KeyMsg{
+		{Type: tea.KeySpace, Runes: []rune{' '}},
+		{Type: tea.KeyDown},
+		{Type: tea.KeySpace, Runes: []rune{' '}},
+		{Type: tea.KeyRunes, Runes: []rune{'t'}},
+	}
+	for _, step := range steps {
+		updated, _ := m.Update(step)
+		m = updated
+	}
+
+	if len(m.SelectedSubtitles) != 2 {
+		t.Fatalf("SelectedSubtitles = %+v, want two", m.SelectedSubtitles)
+	}
+	if sub, ok := m.List.Items()[0].(types.SubtitleItem); !ok || !sub.IsSelected {
+		t.Fatalf("first list item = %+v, want it marked as selected", m.List.Items()[0])
+	}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	msg := cmdMsg(t, cmd)
+	got, ok := msg.(types.StartDownloadMsg)
+	if !ok {
+		t.Fatalf("cmd msg type = %T, want types.StartDownloadMsg", msg)
+	}
+
+	want := types.SubtitleRequest{Languages: []string{"en"}, AutoLanguages: []string{"de"}, Format: types.SubtitleFormatVTT, Only: true}
+	if !reflect.DeepEqual(got.Subtitles, want) {
+		t.Fatalf("Subtitles = %+v, want %+v", got.Subtitles, want)
+	}
+}
+
+func TestFormatListVideoDownloadEmbedsSelectedSubtitles(t *testing.T) {
+	setupModelTestEnv(t)
+
+	m := NewFormatListModel()
+	m.SetSubtitles([]list.Item{types.SubtitleItem{Language: "fr"}})
+	m.SetFormats([]list.Item{types.FormatItem{FormatTitle: "1080p", FormatValue: "137+140"}}, nil, nil, nil)
+	m.toggleSubtitle(types.SubtitleItem{Language: "fr"})
+	m.List.Select(0)
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	msg := cmdMsg(t, cmd)
+	got, ok := msg.(types.StartDownloadMsg)
+	if !ok {
+		t.Fatalf("cmd msg type = %T, want types.StartDownloadMsg", msg)
+	}
+	if got.Subtitles.Only || !reflect.DeepEqual(got.Subtitles.Languages, []string{"fr"}) {
+		t.Fatalf("Subtitles = %+v, want fr embedded", got.Subtitles)
+	}
+}
+
+func TestFormatListEnterOnThumbnailStartsThumbnailDownload(t *testing.T) {
+	setupModelTestEnv(t)
+
+	thumb := types.ThumbnailItem{ID: "2", URL: "https://i.ytimg.com/vi_webp/abc/maxresdefault.webp", Width: 1280, Height: 720}
+
+	m := NewFormatListModel()
+	m.URL = "https://www.youtube.com/watch?v=abc"
+	m.SetFormats(nil, nil, []list.Item{thumb}, nil)
+	m.ActiveTab = FormatTabThumbnail
+	m.updateListForTab()
+	m.List.Select(0)
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	msg := cmdMsg(t, cmd)
+	got, ok := msg.(types.StartDownloadMsg)
+	if !ok {
+		t.Fatalf("cmd msg type = %T, want types.StartDownloadMsg", msg)
+	}
+	if got.Thumbnail != thumb || got.FormatID != "" {
+		t.Fatalf("StartDownloadMsg = %+v, want thumbnail %+v without a format", got, thumb)
+	}
+}
+
+func TestFormatListEnterSendsEstimatedSize(t *testing.T) {
+	setupModelTestEnv(t)
+
+	m := NewFormatListModel()
+	m.URL = "https://www.youtube.com/watch?v=abc"
+	m.SetFormats(
+		[]list.Item{types.FormatItem{FormatTitle: "1080p", FormatValue: "137+140", VideoSize: 900, AudioSize: 100}},
+		nil,
+		nil,
+		nil,
+	)
+	m.List.Select(0)
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated
+
+	got, ok := cmdMsg(t, cmd).(types.StartDownloadMsg)
+	if !ok {
+		t.Fatalf("expected a StartDownloadMsg")
+	}
+	if got.EstimatedSize != 1000 {
+		t.Fatalf("EstimatedSize = %d, want 1000", got.EstimatedSize)
+	}
+}
+
+func TestFormatListConfirmPrompt(t *testing.T) {
+	setupModelTestEnv(t)
+
+	pending := types.StartDownloadMsg{URL: "u1", SkipSpaceCheck: true}
+
+	m := NewFormatListModel().AskConfirm("Not enough disk space", pending)
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if updated.Confirming() || cmd != nil {
+		t.Fatalf("expected n to dismiss the prompt without downloading")
+	}
+
+	m = NewFormatListModel().AskConfirm("Not enough disk space", pending)
+	updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	if updated.Confirming() {
+		t.Fatalf("expected y to close the prompt")
+	}
+	if got := cmdMsg(t, cmd); !reflect.DeepEqual(got, pending) {
+		t.Fatalf("cmd msg = %+v, want the pending download", got)
+	}
+}
+
+func TestFormatListCtrlWSavesQueueToCollection(t *testing.T) {
+	setupModelTestEnv(t)
+
+	m := NewFormatListModel()
+	m.ActiveTab = FormatTabCustom
+	m.IsQueue = true
+	m.QueueVideos = []types.VideoItem{
+		{ID: "a", VideoTitle: "Video A"},
+		{ID: "b", VideoTitle: "Video B"},
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlW})
+	if !m.SavePrompt.Visible || len(m.SavePrompt.Videos) != 2 {
+		t.Fatalf("expected the save prompt for both queued videos")
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("!")})
+	if m.CustomInput.Value() != "" || m.SavePrompt.Input.Value() != "Watch Later!" {
+		t.Fatalf("typing went to %q/%q, want the save prompt", m.CustomInput.Value(), m.SavePrompt.Input.Value())
+	}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if toast := cmdMsg(t, cmd).(types.ShowToastMsg); toast.Message != "Saved 2 videos to Watch Later!" {
+		t.Fatalf("unexpected toast: %+v", toast)
+	}
+}
@@ -51,13 +51,19 @@ func NewHelpModel() HelpModel {
 				Content: ` /channel <username>      Search videos from a channel
  /playlist <url or id>    Search video for a playlist
  /play <url>							Play a video from a url
+ /import <path>           Queue the URLs listed in a file
+ /subscribe <username>    Subscribe to a channel
+ /unsubscribe <username>  Unsubscribe from a channel
+ /feed                    List new uploads from subscriptions
  /resume                  Resume unfinished downloads
+ /collections             Browse saved collections
  /help                    Show this help message`,
 			},
 			{
 				Title: "navigation",
 				Content: ` ↑ / ctrl+p    Previous search in history
  ↓ / ctrl+n    Next search in history
+ w             Save a video to a collection
  b             Go back`,
 			},
 			{
@@ -12,23 +12,66 @@ import (
 )
 
 type ResumeItem struct {
-	URL      string
-	URLs     []string
-	Videos   []types.VideoItem
-	TitleVal string
-	FormatID string
-	Desc     string
+	Download utils.UnfinishedDownload
 }
 
-func (i ResumeItem) Title() string { return i.TitleVal }
+func (i ResumeItem) Title() string { return i.Download.Title }
 func (i ResumeItem) Description() string {
-	if i.Desc != "" {
-		return i.Desc
+	desc := i.Download.URL
+	if i.Download.Desc != "" {
+		desc = i.Download.Desc
 	}
 
-	return i.URL
+	if i.Download.IsAudio {
+		desc += " • audio"
+	}
+
+	if !i.Download.Clip.IsZero() {
+		desc += " • clip " + utils.FormatClipRange(i.Download.Clip)
+	}
+
+	return desc
+}
+func (i ResumeItem) FilterValue() string {
+	return i.Download.Title + " " + i.Download.URL + " " + i.Download.Desc
+}
+
+// resumeDownloadMsg rebuilds the request an unfinished download was started
+// with. Saved option states are applied on top of options.
+func resumeDownloadMsg(d utils.UnfinishedDownload, options []types.DownloadOption) types.StartResumeDownloadMsg {
+	items := make([]types.QueueItem, len(d.Items))
+	for i, it := range d.Items {
+		status := it.Status
+		if status != types.QueueStatusComplete && status != types.QueueStatusSkipped {
+			status = types.QueueStatusPending
+		}
+
+		items[i] = types.QueueItem{
+			Index:           i + 1,
+			Video:           it.Video,
+			URL:             it.URL,
+			Status:          status,
+			OutputDir:       it.OutputDir,
+			ForceRedownload: it.ForceRedownload,
+		}
+	}
+
+	return types.StartResumeDownloadMsg{
+		URL:                d.URL,
+		Title:              d.Title,
+		FormatID:           d.FormatID,
+		IsAudioTab:         d.IsAudio,
+		ABR:                d.ABR,
+		Options:            utils.RestoreOptions(options, d.Options),
+		CookiesFromBrowser: d.CookiesFromBrowser,
+		Cookies:            d.Cookies,
+		Clip:               d.Clip,
+		Subtitles:          d.Subtitles,
+		ForceRedownload:    d.ForceRedownload,
+		Queue:              d.IsQueue(),
+		Items:              items,
+	}
 }
-func (i ResumeItem) FilterValue() string { return i.TitleVal + " " + i.URL + " " + i.Desc }
 
 type ResumeModel struct {
 	Visible bool
@@ -78,14 +121,7 @@ func (m *ResumeModel) LoadItems() {
 
 	listItems := make([]list.Item, len(items))
 	for i, item := range items {
-		listItems[i] = ResumeItem{
-			URL:      item.URL,
-			URLs:     item.URLs,
-			Videos:   item.Videos,
-			TitleVal: item.Title,
-			FormatID: item.FormatID,
-			Desc:     item.Desc,
-		}
+		listItems[i] = ResumeItem{Download: item}
 	}
 
 	m.List.SetItems(listItems)
@@ -99,7 +135,7 @@ func (m *ResumeModel) HandleResize(width, height int) {
 
 func (m *ResumeModel) DeleteSelected() {
 	if item, ok := m.List.SelectedItem().(ResumeItem); ok {
-		if err := utils.RemoveUnfinished(item.URL); err != nil {
+		if err := utils.RemoveUnfinished(item.Download.URL); err != nil {
 			fmt.Printf("unable to remove unfinished download item: %v", err)
 		}
 
@@ -109,14 +145,7 @@ func (m *ResumeModel) DeleteSelected() {
 
 func (m *ResumeModel) SelectedItem() *utils.UnfinishedDownload {
 	if item, ok := m.List.SelectedItem().(ResumeItem); ok {
-		return &utils.UnfinishedDownload{
-			URL:      item.URL,
-			URLs:     item.URLs,
-			Videos:   item.Videos,
-			Title:    item.TitleVal,
-			FormatID: item.FormatID,
-			Desc:     item.Desc,
-		}
+		return &item.Download
 	}
 
 	return nil
@@ -1,7 +1,9 @@
 package models
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/xdagiz/xytz/internal/config"
@@ -21,9 +23,14 @@ import (
 type CLIOptions struct {
 	SearchLimit        int
 	SortBy             string
+	UploadDate         string
+	Duration           string
+	Type               string
+	Features           []string
 	Query              string
 	Channel            string
 	Playlist           string
+	BatchFile          string
 	CookiesFromBrowser string
 	Cookies            string
 }
@@ -34,9 +41,12 @@ type SearchModel struct {
 	Input              textinput.Model
 	Autocomplete       SlashModel
 	ResumeList         ResumeModel
+	DownloadsList      DownloadsModel
+	CollectionsList    CollectionsModel
 	Help               HelpModel
 	History            HistoryNavigator
 	SortBy             types.SortBy
+	Filters            FilterPanelModel
 	SearchLimit        int
 	DownloadOptions    []types.DownloadOption
 	Options            *CLIOptions
@@ -64,6 +74,7 @@ func NewSearchModelWithOptions(opts *CLIOptions) SearchModel {
 
 	var (
 		defaultSort        types.SortBy
+		filters            types.SearchFilters
 		searchLimit        int
 		cookiesFromBrowser string
 		cookies            string
@@ -71,11 +82,13 @@ func NewSearchModelWithOptions(opts *CLIOptions) SearchModel {
 
 	if opts != nil {
 		defaultSort = types.ParseSortBy(opts.SortBy)
+		filters = types.ParseSearchFilters(opts.UploadDate, opts.Duration, opts.Type, opts.Features)
 		searchLimit = opts.SearchLimit
 		cookiesFromBrowser = opts.CookiesFromBrowser
 		cookies = opts.Cookies
 	} else {
 		defaultSort = types.ParseSortBy(cfg.SortByDefault)
+		filters = types.ParseSearchFilters(cfg.FilterUploadDate, cfg.FilterDuration, cfg.FilterType, cfg.FilterFeatures)
 		searchLimit = cfg.SearchLimit
 		cookiesFromBrowser = cfg.CookiesBrowser
 		cookies = cfg.CookiesFile
@@ -83,25 +96,18 @@ func NewSearchModelWithOptions(opts *CLIOptions) SearchModel {
 
 	hasFFmpeg := utils.HasFFmpeg(cfg.FFmpegPath)
 
-	options := types.DownloadOptions()
-	for i := range options {
-		switch options[i].ConfigField {
-		case "EmbedSubtitles":
-			options[i].Enabled = cfg.EmbedSubtitles
-		case "EmbedMetadata":
-			options[i].Enabled = cfg.EmbedMetadata
-		case "EmbedChapters":
-			options[i].Enabled = cfg.EmbedChapters
-		}
-	}
+	options := utils.ConfigDownloadOptions(cfg)
 
 	return SearchModel{
 		Input:              ti,
 		Autocomplete:       NewSlashModel(),
 		ResumeList:         NewResumeModel(),
+		DownloadsList:      NewDownloadsModel(),
+		CollectionsList:    NewCollectionsModel(),
 		Help:               NewHelpModel(),
 		History:            NewHistoryNavigator(),
 		SortBy:             defaultSort,
+		Filters:            NewFilterPanelModel(filters),
 		SearchLimit:        searchLimit,
 		DownloadOptions:    options,
 		Options:            opts,
@@ -158,12 +164,27 @@ func (m SearchModel) View() string {
 			s.WriteString("\n")
 			s.WriteString(resumeView)
 		}
+	} else if m.DownloadsList.Visible {
+		downloadsView := m.DownloadsList.View(m.Width, m.Height)
+		if downloadsView != "" {
+			s.WriteString("\n")
+			s.WriteString(downloadsView)
+		}
+	} else if m.CollectionsList.Visible {
+		collectionsView := m.CollectionsList.View(m.Width, m.Height)
+		if collectionsView != "" {
+			s.WriteString("\n")
+			s.WriteString(collectionsView)
+		}
 	} else if m.Help.Visible {
 		helpView := m.Help.View()
 		if helpView != "" {
 			s.WriteString("\n")
 			s.WriteString(helpView)
 		}
+	} else if m.Filters.Visible {
+		s.WriteRune('\n')
+		s.WriteString(m.Filters.View())
 	} else {
 		s.WriteRune('\n')
 		s.WriteString(styles.SortTitle.Render("Sort By"))
@@ -172,6 +193,11 @@ func (m SearchModel) View() string {
 		currentSort := styles.SortItem.Render(">", m.SortBy.GetDisplayName())
 		s.WriteString(currentSort)
 		s.WriteRune('\n')
+		s.WriteString(styles.SortTitle.Render("Filters"))
+		s.WriteString(styles.SortHelp.Render("(ctrl+f to edit)"))
+		s.WriteRune('\n')
+		s.WriteString(styles.SortItem.Render(">", m.Filters.Filters.Summary()))
+		s.WriteRune('\n')
 		s.WriteString(styles.SortTitle.Render("Download Options"))
 		s.WriteRune('\n')
 
@@ -183,6 +209,9 @@ func (m SearchModel) View() string {
 				}
 				keyName := keyTypeToString(opt.KeyBinding)
 				fmt.Fprintf(&s, "%s %s (%s)", styles.SortItem.Render(indicator), opt.Name, keyName)
+				if opt.Enabled && opt.Mode != "" {
+					s.WriteString(styles.SortHelp.Render(opt.Mode))
+				}
 				s.WriteRune('\n')
 			} else {
 				fmt.Fprintf(&s, "%s %s", styles.SortItem.Render("×"), opt.Name)
@@ -195,6 +224,11 @@ func (m SearchModel) View() string {
 	return s.String()
 }
 
+// SearchParam is the sp parameter for the sort order and filters chosen.
+func (m SearchModel) SearchParam() string {
+	return types.SearchParam(m.SortBy, m.Filters.Filters)
+}
+
 func (m SearchModel) HandleResize(w, h int) SearchModel {
 	m.Width = w
 	m.Height = h
@@ -202,6 +236,8 @@ func (m SearchModel) HandleResize(w, h int) SearchModel {
 	m.Autocomplete.HandleResize(w, h)
 	m.Help.HandleResize(w)
 	m.ResumeList.HandleResize(w, h)
+	m.DownloadsList.HandleResize(w, h)
+	m.CollectionsList.HandleResize(w, h)
 	return m
 }
 
@@ -212,6 +248,11 @@ func (m SearchModel) Update(msg tea.Msg) (SearchModel, tea.Cmd) {
 		}
 	}
 
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && m.Filters.Visible {
+		m.Filters = m.Filters.Update(keyMsg)
+		return m, nil
+	}
+
 	if keyMsg, ok := msg.(tea.KeyMsg); ok {
 		switch keyMsg.Type {
 		case tea.KeyEsc:
@@ -219,6 +260,14 @@ func (m SearchModel) Update(msg tea.Msg) (SearchModel, tea.Cmd) {
 				return updated, cmd
 			}
 
+			if updated, cmd, handled := m.handleDownloadsEsc(); handled {
+				return updated, cmd
+			}
+
+			if updated, cmd, handled := m.handleCollectionsEsc(); handled {
+				return updated, cmd
+			}
+
 			m.Help.Hide()
 		}
 	}
@@ -263,12 +312,28 @@ func (m SearchModel) Update(msg tea.Msg) (SearchModel, tea.Cmd) {
 	case list.FilterMatchesMsg:
 		if m.ResumeList.Visible {
 			m.ResumeList.List, cmd = m.ResumeList.List.Update(msg)
+		} else if m.DownloadsList.Visible {
+			m.DownloadsList.List, cmd = m.DownloadsList.List.Update(msg)
+		} else if m.CollectionsList.Visible {
+			m.CollectionsList.List, cmd = m.CollectionsList.List.Update(msg)
 		}
 		return m, cmd
 
 	case tea.KeyMsg:
 		m.ErrMsg = ""
 
+		if m.DownloadsList.Visible {
+			if updated, cmd, handled := m.handleDownloadsKey(msg); handled {
+				return updated, cmd
+			}
+		}
+
+		if m.CollectionsList.Visible {
+			if updated, cmd, handled := m.handleCollectionsKey(msg); handled {
+				return updated, cmd
+			}
+		}
+
 		switch msg.Type {
 		case tea.KeyEnter:
 			return m.handleEnterKey()
@@ -277,7 +342,7 @@ func (m SearchModel) Update(msg tea.Msg) (SearchModel, tea.Cmd) {
 			m.updateAutocompleteFilter()
 
 		case tea.KeyRunes:
-			if string(msg.Runes) == "/" && !m.Autocomplete.Visible && !m.ResumeList.Visible {
+			if string(msg.Runes) == "/" && !m.Autocomplete.Visible && !m.ResumeList.Visible && !m.DownloadsList.Visible && !m.CollectionsList.Visible {
 				currentValue := m.Input.Value()
 				if currentValue == "" {
 					m.Autocomplete.Show("/")
@@ -287,13 +352,13 @@ func (m SearchModel) Update(msg tea.Msg) (SearchModel, tea.Cmd) {
 			}
 
 		case tea.KeyUp, tea.KeyCtrlP:
-			if !m.ResumeList.Visible {
+			if !m.ResumeList.Visible && !m.DownloadsList.Visible && !m.CollectionsList.Visible {
 				m.History.Navigate(1, m.Input.Value, m.Input.SetValue)
 				m.Input.CursorEnd()
 			}
 
 		case tea.KeyDown, tea.KeyCtrlN:
-			if !m.ResumeList.Visible {
+			if !m.ResumeList.Visible && !m.DownloadsList.Visible && !m.CollectionsList.Visible {
 				m.History.Navigate(-1, m.Input.Value, m.Input.SetValue)
 				m.Input.CursorEnd()
 			}
@@ -306,14 +371,24 @@ func (m SearchModel) Update(msg tea.Msg) (SearchModel, tea.Cmd) {
 			m.SortBy = m.SortBy.Prev()
 			return m, nil
 
-		case tea.KeyCtrlS, tea.KeyCtrlJ, tea.KeyCtrlL:
+		case tea.KeyCtrlF:
+			if !m.ResumeList.Visible && !m.DownloadsList.Visible && !m.CollectionsList.Visible {
+				m.Filters.Show()
+				return m, nil
+			}
+
+		case tea.KeyCtrlS, tea.KeyCtrlJ, tea.KeyCtrlL, tea.KeyCtrlT, tea.KeyCtrlG, tea.KeyCtrlE:
 			for i := range m.DownloadOptions {
 				if m.DownloadOptions[i].KeyBinding == msg.Type {
 					if m.DownloadOptions[i].RequiresFFmpeg && !m.HasFFmpeg {
 						return m, nil
 					}
 
-					m.DownloadOptions[i].Enabled = !m.DownloadOptions[i].Enabled
+					if len(m.DownloadOptions[i].Modes) > 0 {
+						m.DownloadOptions[i].NextMode()
+					} else {
+						m.DownloadOptions[i].Enabled = !m.DownloadOptions[i].Enabled
+					}
 					return m, nil
 				}
 			}
@@ -336,6 +411,16 @@ func (m SearchModel) Update(msg tea.Msg) (SearchModel, tea.Cmd) {
 		return m, tea.Batch(cmd, autocompleteCmd)
 	}
 
+	if m.DownloadsList.Visible {
+		m.DownloadsList.List, cmd = m.DownloadsList.List.Update(msg)
+		return m, tea.Batch(cmd, autocompleteCmd)
+	}
+
+	if m.CollectionsList.Visible {
+		m.CollectionsList.List, cmd = m.CollectionsList.List.Update(msg)
+		return m, tea.Batch(cmd, autocompleteCmd)
+	}
+
 	m.Input, inputCmd = m.Input.Update(msg)
 	newValue := m.Input.Value()
 
@@ -381,6 +466,212 @@ func (m SearchModel) handleResumeEsc() (SearchModel, tea.Cmd, bool) {
 	return m, nil, true
 }
 
+func (m SearchModel) handleDownloadsEsc() (SearchModel, tea.Cmd, bool) {
+	if !m.DownloadsList.Visible {
+		return m, nil, false
+	}
+
+	if HandleListEsc(m.DownloadsList.List) {
+		m.DownloadsList.Hide()
+		m.DownloadsList.List.ResetFilter()
+		m.Input.SetValue("")
+		return m, nil, true
+	}
+
+	m.DownloadsList.List.SetFilterState(list.Unfiltered)
+	return m, nil, true
+}
+
+// handleDownloadsKey runs the actions of the /downloads list: Enter opens the
+// file, Ctrl+P plays it in mpv, Ctrl+Y copies its path, Ctrl+R downloads the
+// video again and Del/Ctrl+D removes the entry from the log.
+func (m SearchModel) handleDownloadsKey(msg tea.KeyMsg) (SearchModel, tea.Cmd, bool) {
+	if m.DownloadsList.List.SettingFilter() && msg.Type != tea.KeyEnter {
+		return m, nil, false
+	}
+
+	switch msg.Type {
+	case tea.KeyEnter, tea.KeyCtrlP, tea.KeyCtrlY, tea.KeyCtrlR, tea.KeyDelete, tea.KeyCtrlD:
+	default:
+		return m, nil, false
+	}
+
+	if msg.Type == tea.KeyEnter && m.DownloadsList.List.FilterState() == list.Filtering {
+		m.DownloadsList.List.SetFilterState(list.FilterApplied)
+		return m, nil, true
+	}
+
+	item := m.DownloadsList.SelectedItem()
+	if item == nil {
+		return m, nil, true
+	}
+
+	video := types.VideoItem{
+		ID:         item.VideoID,
+		VideoTitle: item.Title,
+		Channel:    item.Channel,
+	}
+
+	switch msg.Type {
+	case tea.KeyEnter, tea.KeyCtrlP:
+		if _, err := os.Stat(item.Destination); item.Destination == "" || err != nil {
+			m.ErrMsg = "File not found: " + item.Destination
+			return m, nil, true
+		}
+
+		if msg.Type == tea.KeyEnter {
+			utils.OpenURL(item.Destination)
+			return m, nil, true
+		}
+
+		path := item.Destination
+		return m, func() tea.Msg {
+			return types.PlayFileMsg{Path: path, SelectedVideo: video}
+		}, true
+
+	case tea.KeyCtrlY:
+		if item.Destination == "" {
+			return m, nil, true
+		}
+
+		if err := utils.CopyToClipboard(item.Destination); err != nil {
+			m.ErrMsg = "Failed to copy path: " + err.Error()
+			return m, nil, true
+		}
+
+		return m, func() tea.Msg {
+			return types.ShowToastMsg{Message: "Path copied to clipboard"}
+		}, true
+
+	case tea.KeyCtrlR:
+		if item.VideoID == "" {
+			m.ErrMsg = "Unknown video ID for this download"
+			return m, nil, true
+		}
+
+		m.DownloadsList.Hide()
+		m.DownloadsList.List.ResetFilter()
+		download := *item
+		return m, func() tea.Msg {
+			return types.StartDownloadMsg{
+				URL:             utils.BuildVideoURL(download.VideoID),
+				FormatID:        download.FormatID,
+				IsAudioTab:      download.IsAudio,
+				SelectedVideo:   video,
+				ForceRedownload: true,
+			}
+		}, true
+
+	default:
+		m.DownloadsList.DeleteSelected()
+		return m, nil, true
+	}
+}
+
+func (m SearchModel) handleCollectionsEsc() (SearchModel, tea.Cmd, bool) {
+	if !m.CollectionsList.Visible {
+		return m, nil, false
+	}
+
+	if !HandleListEsc(m.CollectionsList.List) {
+		m.CollectionsList.List.SetFilterState(list.Unfiltered)
+		return m, nil, true
+	}
+
+	if m.CollectionsList.Open != "" {
+		m.CollectionsList.Close()
+		return m, nil, true
+	}
+
+	m.CollectionsList.Hide()
+	m.CollectionsList.List.ResetFilter()
+	m.Input.SetValue("")
+	return m, nil, true
+}
+
+// handleCollectionsKey runs the actions of the /collections list: Enter
+// opens a collection or plays one of its videos, Ctrl+P plays the whole
+// collection in mpv, Ctrl+A queues it for download, Ctrl+E exports it as an
+// M3U playlist, Shift+↑/↓ (K/J) moves a video and Del/Ctrl+D removes it or
+// deletes the collection.
+func (m SearchModel) handleCollectionsKey(msg tea.KeyMsg) (SearchModel, tea.Cmd, bool) {
+	if m.CollectionsList.List.SettingFilter() && msg.Type != tea.KeyEnter {
+		return m, nil, false
+	}
+
+	switch msg.String() {
+	case "enter", "ctrl+p", "ctrl+a", "ctrl+e", "delete", "ctrl+d":
+	case "shift+up", "K":
+		m.CollectionsList.MoveSelected(-1)
+		return m, nil, true
+	case "shift+down", "J":
+		m.CollectionsList.MoveSelected(1)
+		return m, nil, true
+	default:
+		return m, nil, false
+	}
+
+	if msg.Type == tea.KeyEnter && m.CollectionsList.List.FilterState() == list.Filtering {
+		m.CollectionsList.List.SetFilterState(list.FilterApplied)
+		return m, nil, true
+	}
+
+	switch msg.Type {
+	case tea.KeyEnter:
+		if m.CollectionsList.Open == "" {
+			m.CollectionsList.OpenSelected()
+			return m, nil, true
+		}
+
+		video, ok := m.CollectionsList.SelectedVideo()
+		if !ok {
+			return m, nil, true
+		}
+
+		return m, func() tea.Msg {
+			return types.PlayVideoMsg{SelectedVideo: video}
+		}, true
+
+	case tea.KeyDelete, tea.KeyCtrlD:
+		m.CollectionsList.DeleteSelected()
+		return m, nil, true
+	}
+
+	c, ok := m.CollectionsList.SelectedCollection()
+	if !ok {
+		return m, nil, true
+	}
+
+	if len(c.Videos) == 0 && msg.Type != tea.KeyCtrlE {
+		m.ErrMsg = c.Name + " is empty"
+		return m, nil, true
+	}
+
+	switch msg.Type {
+	case tea.KeyCtrlP:
+		return m, func() tea.Msg {
+			return types.PlayCollectionMsg{Name: c.Name, Videos: c.Videos}
+		}, true
+
+	case tea.KeyCtrlA:
+		m.CollectionsList.Hide()
+		m.CollectionsList.List.ResetFilter()
+		return m, func() tea.Msg {
+			return types.StartQueueConfirmMsg{Videos: c.Videos, Label: c.Name}
+		}, true
+
+	default:
+		return m, func() tea.Msg {
+			path, err := utils.ExportCollection(c.Name)
+			if err != nil {
+				return types.ShowToastMsg{Message: fmt.Sprintf("Failed to export %s: %v", c.Name, err)}
+			}
+
+			return types.ShowToastMsg{Message: "Exported to " + path}
+		}, true
+	}
+}
+
 func (m SearchModel) handleEnterKey() (SearchModel, tea.Cmd) {
 	if m.ResumeList.Visible {
 		if m.ResumeList.List.FilterState() == list.Filtering {
@@ -390,14 +681,9 @@ func (m SearchModel) handleEnterKey() (SearchModel, tea.Cmd) {
 
 		if item := m.ResumeList.SelectedItem(); item != nil {
 			m.ResumeList.Hide()
+			msg := resumeDownloadMsg(*item, m.DownloadOptions)
 			cmd := func() tea.Msg {
-				return types.StartResumeDownloadMsg{
-					URL:      item.URL,
-					URLs:     item.URLs,
-					Videos:   item.Videos,
-					FormatID: item.FormatID,
-					Title:    item.Title,
-				}
+				return msg
 			}
 
 			return m, cmd
@@ -474,10 +760,46 @@ func (m *SearchModel) executeSlashCommand(slashCmd, query, args string) tea.Cmd
 			}
 		}
 
+	case "import":
+		if args == "" {
+			m.Input.SetValue("/import ")
+			m.Input.CursorEnd()
+		} else {
+			m.History.Add(query)
+			cmd = func() tea.Msg {
+				return types.StartImportMsg{Path: args}
+			}
+		}
+
+	case "subscribe", "unsubscribe":
+		if args == "" {
+			m.Input.SetValue("/" + slashCmd + " ")
+			m.Input.CursorEnd()
+		} else if len(strings.SplitAfter(args, " ")) > 1 {
+			m.ErrMsg = "Channel username cannot contain spaces"
+		} else {
+			m.Input.SetValue("")
+			cmd = subscriptionCmd(slashCmd == "subscribe", args)
+		}
+
+	case "feed":
+		m.Input.SetValue("")
+		cmd = func() tea.Msg {
+			return types.StartFeedMsg{}
+		}
+
 	case "resume":
 		m.ResumeList.Show()
 		m.Input.SetValue("")
 
+	case "downloads":
+		m.DownloadsList.Show()
+		m.Input.SetValue("")
+
+	case "collections":
+		m.CollectionsList.Show()
+		m.Input.SetValue("")
+
 	case "help":
 		m.Help.Toggle()
 		m.Input.SetValue("")
@@ -486,6 +808,34 @@ func (m *SearchModel) executeSlashCommand(slashCmd, query, args string) tea.Cmd
 	return cmd
 }
 
+// subscriptionCmd subscribes to or unsubscribes from a channel and reports
+// the outcome in a toast.
+func subscriptionCmd(subscribe bool, input string) tea.Cmd {
+	return func() tea.Msg {
+		if subscribe {
+			channel, err := utils.AddSubscription(input)
+			switch {
+			case errors.Is(err, utils.ErrAlreadySubscribed):
+				return types.ShowToastMsg{Message: "Already subscribed to @" + channel}
+			case err != nil:
+				return types.ShowToastMsg{Message: fmt.Sprintf("Failed to subscribe: %v", err)}
+			}
+
+			return types.ShowToastMsg{Message: "Subscribed to @" + channel}
+		}
+
+		channel, err := utils.RemoveSubscription(input)
+		switch {
+		case errors.Is(err, utils.ErrNotSubscribed):
+			return types.ShowToastMsg{Message: "Not subscribed to @" + channel}
+		case err != nil:
+			return types.ShowToastMsg{Message: fmt.Sprintf("Failed to unsubscribe: %v", err)}
+		}
+
+		return types.ShowToastMsg{Message: "Unsubscribed from @" + channel}
+	}
+}
+
 func (m *SearchModel) updateAutocompleteFilter() {
 	if !m.Autocomplete.Visible {
 		return
@@ -521,6 +871,12 @@ func keyTypeToString(key tea.KeyType) string {
 		return "Ctrl+j"
 	case tea.KeyCtrlL:
 		return "Ctrl+l"
+	case tea.KeyCtrlT:
+		return "Ctrl+t"
+	case tea.KeyCtrlG:
+		return "Ctrl+g"
+	case tea.KeyCtrlE:
+		return "Ctrl+e"
 	default:
 		return ""
 	}
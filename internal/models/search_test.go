@@ -1,7 +1,11 @@
 package models
 
 import (
+	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -17,6 +21,9 @@ func setupModelTestEnv(t *testing.T) {
 	origConfigDir := config.GetConfigDir
 	origUnfinishedPath := utils.GetUnfinishedFilePath
 	origHistoryPath := utils.GetHistoryFilePath
+	origDownloadsPath := utils.GetDownloadsFilePath
+	origSubscriptionsPath := utils.GetSubscriptionsFilePath
+	origCollectionsPath := utils.GetCollectionsFilePath
 
 	tmpDir := t.TempDir()
 	config.GetConfigDir = func() string {
@@ -28,11 +35,23 @@ func setupModelTestEnv(t *testing.T) {
 	utils.GetHistoryFilePath = func() string {
 		return filepath.Join(tmpDir, "history")
 	}
+	utils.GetDownloadsFilePath = func() string {
+		return filepath.Join(tmpDir, "downloads.json")
+	}
+	utils.GetSubscriptionsFilePath = func() string {
+		return filepath.Join(tmpDir, "subscriptions.json")
+	}
+	utils.GetCollectionsFilePath = func() string {
+		return filepath.Join(tmpDir, "collections.json")
+	}
 
 	t.Cleanup(func() {
 		config.GetConfigDir = origConfigDir
 		utils.GetUnfinishedFilePath = origUnfinishedPath
 		utils.GetHistoryFilePath = origHistoryPath
+		utils.GetDownloadsFilePath = origDownloadsPath
+		utils.GetSubscriptionsFilePath = origSubscriptionsPath
+		utils.GetCollectionsFilePath = origCollectionsPath
 	})
 }
 
@@ -104,8 +123,7 @@ func TestSearchModelResumeSlashAndEnterStartsResumeDownload(t *testing.T) {
 	err := utils.SaveUnfinished([]utils.UnfinishedDownload{
 		{
 			URL:       "queue:test",
-			URLs:      []string{"https://example.com/v1"},
-			Videos:    []types.VideoItem{{ID: "v1", VideoTitle: "Video 1"}},
+			Items:     []utils.UnfinishedItem{{URL: "https://example.com/v1", Video: types.VideoItem{ID: "v1", VideoTitle: "Video 1"}, Status: types.QueueStatusPending}},
 			FormatID:  "best",
 			Title:     "Queued downloads",
 			Desc:      "1 item left",
@@ -138,8 +156,8 @@ func TestSearchModelResumeSlashAndEnterStartsResumeDownload(t *testing.T) {
 	if resumeMsg.FormatID != "best" {
 		t.Fatalf("FormatID = %q, want best", resumeMsg.FormatID)
 	}
-	if len(resumeMsg.URLs) != 1 || resumeMsg.URLs[0] != "https://example.com/v1" {
-		t.Fatalf("URLs = %#v, want one expected URL", resumeMsg.URLs)
+	if !resumeMsg.Queue || len(resumeMsg.Items) != 1 || resumeMsg.Items[0].URL != "https://example.com/v1" {
+		t.Fatalf("Items = %#v, want one queued item with the expected URL", resumeMsg.Items)
 	}
 }
 
@@ -203,3 +221,390 @@ func TestSearchModelResumeNavigationDoesNotTypeIntoInput(t *testing.T) {
 		t.Fatalf("input polluted by resume navigation: %q", m.Input.Value())
 	}
 }
+
+func TestSearchModelDownloadsSlashAndRedownload(t *testing.T) {
+	setupModelTestEnv(t)
+
+	err := utils.AddDownload(utils.CompletedDownload{
+		VideoID:     "abc123",
+		Title:       "Downloaded Video",
+		Channel:     "Channel",
+		FormatID:    "140",
+		IsAudio:     true,
+		Destination: filepath.Join(t.TempDir(), "missing.m4a"),
+	})
+	if err != nil {
+		t.Fatalf("AddDownload error: %v", err)
+	}
+
+	m := NewSearchModel()
+	m.Input.SetValue("/downloads")
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated
+
+	if cmd != nil {
+		t.Fatalf("expected nil command when opening downloads list")
+	}
+	if !m.DownloadsList.Visible {
+		t.Fatalf("expected downloads list to be visible")
+	}
+
+	updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated
+	if cmd != nil {
+		t.Fatalf("expected nil command when opening a missing file")
+	}
+	if !strings.HasPrefix(m.ErrMsg, "File not found") {
+		t.Fatalf("ErrMsg = %q, want file not found", m.ErrMsg)
+	}
+
+	updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	m = updated
+	msg := cmdMsg(t, cmd)
+	got, ok := msg.(types.StartDownloadMsg)
+	if !ok {
+		t.Fatalf("cmd msg type = %T, want types.StartDownloadMsg", msg)
+	}
+	if got.URL != utils.BuildVideoURL("abc123") || got.FormatID != "140" || !got.IsAudioTab || !got.ForceRedownload {
+		t.Fatalf("unexpected re-download msg: %+v", got)
+	}
+	if m.DownloadsList.Visible {
+		t.Fatalf("expected downloads list to be hidden after re-download")
+	}
+}
+
+func TestSearchModelDownloadsPlayAndDelete(t *testing.T) {
+	setupModelTestEnv(t)
+
+	file := filepath.Join(t.TempDir(), "video.mp4")
+	if err := os.WriteFile(file, []byte("data"), 0o644); err != nil {
+		t.Fatalf("WriteFile error: %v", err)
+	}
+	if err := utils.AddDownload(utils.CompletedDownload{VideoID: "abc123", Title: "Video", Destination: file}); err != nil {
+		t.Fatalf("AddDownload error: %v", err)
+	}
+
+	m := NewSearchModel()
+	m.DownloadsList.Show()
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	m = updated
+	msg := cmdMsg(t, cmd)
+	play, ok := msg.(types.PlayFileMsg)
+	if !ok {
+		t.Fatalf("cmd msg type = %T, want types.PlayFileMsg", msg)
+	}
+	if play.Path != file || play.SelectedVideo.ID != "abc123" {
+		t.Fatalf("unexpected play msg: %+v", play)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlD})
+	m = updated
+	if len(m.DownloadsList.List.Items()) != 0 {
+		t.Fatalf("expected entry to be removed, got %d items", len(m.DownloadsList.List.Items()))
+	}
+
+	downloads, err := utils.LoadDownloads()
+	if err != nil {
+		t.Fatalf("LoadDownloads error: %v", err)
+	}
+	if len(downloads) != 0 {
+		t.Fatalf("expected log to be empty, got %d entries", len(downloads))
+	}
+}
+
+func TestSearchModelSponsorBlockOptionCyclesModes(t *testing.T) {
+	setupModelTestEnv(t)
+
+	m := NewSearchModel()
+	m.HasFFmpeg = true
+
+	want := []string{"mark", "remove", ""}
+	for _, mode := range want {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+		m = updated
+
+		if got := types.OptionMode(m.DownloadOptions, "SponsorBlock"); got != mode {
+			t.Fatalf("SponsorBlock mode = %q, want %q", got, mode)
+		}
+	}
+}
+
+func TestSearchModelSponsorBlockOptionLoadsFromConfig(t *testing.T) {
+	setupModelTestEnv(t)
+
+	cfg := config.GetDefault()
+	cfg.SponsorBlockMode = config.SponsorBlockRemove
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("cfg.Save() error: %v", err)
+	}
+
+	m := NewSearchModel()
+	if got := types.OptionMode(m.DownloadOptions, "SponsorBlock"); got != config.SponsorBlockRemove {
+		t.Fatalf("SponsorBlock mode = %q, want %q", got, config.SponsorBlockRemove)
+	}
+}
+
+func TestSearchModelLiveFromStartOptionLoadsFromConfig(t *testing.T) {
+	setupModelTestEnv(t)
+
+	cfg := config.GetDefault()
+	cfg.LiveFromStart = true
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("cfg.Save() error: %v", err)
+	}
+
+	m := NewSearchModel()
+	if !types.IsOptionEnabled(m.DownloadOptions, "LiveFromStart") {
+		t.Fatalf("expected Live From Start to be enabled from the config")
+	}
+}
+
+func TestSearchModelFilterPanelBuildsSearchParam(t *testing.T) {
+	setupModelTestEnv(t)
+
+	m := NewSearchModel()
+	m.SortBy = types.SortByDate
+
+	keys := []tea.KeyMsg{
+		{Type: tea.KeyCtrlF},
+		{Type: tea.KeyDown},
+		{Type: tea.KeyDown},
+		{Type: tea.KeyRight},
+		{Type: tea.KeyRunes, Runes: []rune("j")},
+		{Type: tea.KeyRunes, Runes: []rune(" ")},
+		{Type: tea.KeyEnter},
+	}
+	for _, key := range keys {
+		m, _ = m.Update(key)
+	}
+
+	if m.Filters.Visible {
+		t.Fatal("filter panel still visible after Enter")
+	}
+	if m.Input.Value() != "" {
+		t.Fatalf("input = %q, want the filter keys kept out of it", m.Input.Value())
+	}
+
+	want := types.SearchFilters{Type: types.ResultTypeVideo, Features: []types.SearchFeature{types.Feature4K}}
+	if got := m.Filters.Filters; got.Type != want.Type || !slices.Equal(got.Features, want.Features) {
+		t.Fatalf("Filters = %+v, want %+v", got, want)
+	}
+	if got, want := m.SearchParam(), types.SearchParam(types.SortByDate, want); got != want {
+		t.Fatalf("SearchParam() = %q, want %q", got, want)
+	}
+}
+
+func TestSearchModelFiltersLoadFromConfig(t *testing.T) {
+	setupModelTestEnv(t)
+
+	cfg := config.GetDefault()
+	cfg.FilterUploadDate = "week"
+	cfg.FilterFeatures = []string{"hd", "bogus"}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("cfg.Save() error: %v", err)
+	}
+
+	m := NewSearchModel()
+	if got := m.Filters.Filters; got.UploadDate != types.UploadDateWeek || !slices.Equal(got.Features, []types.SearchFeature{types.FeatureHD}) {
+		t.Fatalf("Filters = %+v, want this week in HD", got)
+	}
+}
+
+func TestSearchModelResumeKeepsClipRangeAndSubtitles(t *testing.T) {
+	setupModelTestEnv(t)
+
+	clip := types.ClipRange{Start: 3600, End: 3690}
+	subs := types.SubtitleRequest{Languages: []string{"en"}, AutoLanguages: []string{"de"}, Format: types.SubtitleFormatSRT}
+	err := utils.SaveUnfinished([]utils.UnfinishedDownload{
+		{
+			URL:             "https://www.youtube.com/watch?v=abc",
+			FormatID:        "137+140",
+			Title:           "Stream",
+			Clip:            clip,
+			Subtitles:       subs,
+			ForceRedownload: true,
+			Timestamp:       time.Now(),
+		},
+	})
+	if err != nil {
+		t.Fatalf("SaveUnfinished error: %v", err)
+	}
+
+	m := NewSearchModel()
+	m.Input.SetValue("/resume")
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	msg := cmdMsg(t, cmd)
+	resumeMsg, ok := msg.(types.StartResumeDownloadMsg)
+	if !ok {
+		t.Fatalf("cmd msg type = %T, want types.StartResumeDownloadMsg", msg)
+	}
+	if resumeMsg.Clip != clip {
+		t.Fatalf("Clip = %+v, want %+v", resumeMsg.Clip, clip)
+	}
+	if !reflect.DeepEqual(resumeMsg.Subtitles, subs) || !resumeMsg.ForceRedownload {
+		t.Fatalf("Subtitles/ForceRedownload = %+v/%v, want %+v/true", resumeMsg.Subtitles, resumeMsg.ForceRedownload, subs)
+	}
+}
+
+func TestSearchModelResumeRestoresAudioSettings(t *testing.T) {
+	setupModelTestEnv(t)
+
+	err := utils.SaveUnfinished([]utils.UnfinishedDownload{
+		{
+			URL:      "queue:albums",
+			FormatID: "140",
+			Title:    "albums",
+			IsAudio:  true,
+			ABR:      128,
+			Options:  []utils.UnfinishedOption{{Field: "EmbedThumbnail", Enabled: true}},
+			Items: []utils.UnfinishedItem{
+				{URL: "u1", Video: types.VideoItem{ID: "id1"}, Status: types.QueueStatusComplete},
+				{URL: "u2", Video: types.VideoItem{ID: "id2"}, Status: types.QueueStatusError, Error: "boom"},
+			},
+			Timestamp: time.Now(),
+		},
+	})
+	if err != nil {
+		t.Fatalf("SaveUnfinished error: %v", err)
+	}
+
+	m := NewSearchModel()
+	m.Input.SetValue("/resume")
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	resumeMsg, ok := cmdMsg(t, cmd).(types.StartResumeDownloadMsg)
+	if !ok {
+		t.Fatalf("expected a StartResumeDownloadMsg")
+	}
+	if !resumeMsg.IsAudioTab || resumeMsg.ABR != 128 {
+		t.Fatalf("IsAudioTab/ABR = %v/%v, want true/128", resumeMsg.IsAudioTab, resumeMsg.ABR)
+	}
+	if !types.IsOptionEnabled(resumeMsg.Options, "EmbedThumbnail") {
+		t.Fatalf("Options = %+v, want Embed Thumbnail enabled", resumeMsg.Options)
+	}
+	if resumeMsg.Items[0].Status != types.QueueStatusComplete || resumeMsg.Items[1].Status != types.QueueStatusPending {
+		t.Fatalf("Items = %+v, want complete item kept and failed item retried", resumeMsg.Items)
+	}
+}
+
+func TestSearchModelSlashImportReturnsStartImportMsg(t *testing.T) {
+	setupModelTestEnv(t)
+
+	m := NewSearchModel()
+	m.Input.SetValue("/import ~/My Lists/urls.txt")
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	msg := cmdMsg(t, cmd)
+	got, ok := msg.(types.StartImportMsg)
+	if !ok {
+		t.Fatalf("cmd msg type = %T, want types.StartImportMsg", msg)
+	}
+	if got.Path != "~/My Lists/urls.txt" {
+		t.Fatalf("Path = %q, want ~/My Lists/urls.txt", got.Path)
+	}
+}
+
+func TestSearchModelSubscribeAndUnsubscribeSlash(t *testing.T) {
+	setupModelTestEnv(t)
+
+	run := func(input string) string {
+		t.Helper()
+
+		m := NewSearchModel()
+		m.Input.SetValue(input)
+		_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+		msg, ok := cmdMsg(t, cmd).(types.ShowToastMsg)
+		if !ok {
+			t.Fatalf("%s: expected a toast", input)
+		}
+		return msg.Message
+	}
+
+	if got := run("/subscribe @gopher"); got != "Subscribed to @gopher" {
+		t.Fatalf("subscribe toast = %q", got)
+	}
+	if got := run("/subscribe https://www.youtube.com/@gopher"); got != "Already subscribed to @gopher" {
+		t.Fatalf("second subscribe toast = %q", got)
+	}
+
+	subs, err := utils.LoadSubscriptions()
+	if err != nil || len(subs) != 1 || subs[0].Channel != "gopher" {
+		t.Fatalf("subscriptions = %+v, %v", subs, err)
+	}
+
+	if got := run("/unsubscribe @gopher"); got != "Unsubscribed from @gopher" {
+		t.Fatalf("unsubscribe toast = %q", got)
+	}
+	if got := run("/unsubscribe @gopher"); got != "Not subscribed to @gopher" {
+		t.Fatalf("second unsubscribe toast = %q", got)
+	}
+}
+
+func TestSearchModelSlashFeedReturnsStartFeedMsg(t *testing.T) {
+	setupModelTestEnv(t)
+
+	m := NewSearchModel()
+	m.Input.SetValue("/feed")
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if msg := cmdMsg(t, cmd); msg != (types.StartFeedMsg{}) {
+		t.Fatalf("cmd msg = %T, want types.StartFeedMsg", msg)
+	}
+}
+
+func TestSearchModelCollectionsBrowser(t *testing.T) {
+	setupModelTestEnv(t)
+
+	videos := []types.VideoItem{
+		{ID: "a", VideoTitle: "Video A"},
+		{ID: "b", VideoTitle: "Video B"},
+		{ID: "c", VideoTitle: "Video C"},
+	}
+	if _, err := utils.AddToCollection("Later", videos); err != nil {
+		t.Fatalf("AddToCollection() error = %v", err)
+	}
+
+	m := NewSearchModel()
+	m.executeSlashCommand("collections", "/collections", "")
+	if !m.CollectionsList.Visible || len(m.CollectionsList.List.Items()) != 1 {
+		t.Fatalf("expected the collections list with one collection")
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.CollectionsList.Open != "Later" || len(m.CollectionsList.List.Items()) != 3 {
+		t.Fatalf("Open = %q with %d items, want Later with 3", m.CollectionsList.Open, len(m.CollectionsList.List.Items()))
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("J")})
+	c, _ := utils.LoadCollection("Later")
+	if c.Videos[0].ID != "b" || c.Videos[1].ID != "a" || m.CollectionsList.List.Index() != 1 {
+		t.Fatalf("order after move = %v, cursor %d", c.Videos, m.CollectionsList.List.Index())
+	}
+
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	play, ok := cmdMsg(t, cmd).(types.PlayCollectionMsg)
+	if !ok || play.Name != "Later" || len(play.Videos) != 3 || play.Videos[0].ID != "b" {
+		t.Fatalf("unexpected play msg: %+v", play)
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if !m.CollectionsList.Visible || m.CollectionsList.Open != "" {
+		t.Fatalf("esc should go back to the collections")
+	}
+
+	m, cmd = m.Update(tea.KeyMsg{Type: tea.KeyCtrlA})
+	queue, ok := cmdMsg(t, cmd).(types.StartQueueConfirmMsg)
+	if !ok || queue.Label != "Later" || len(queue.Videos) != 3 {
+		t.Fatalf("unexpected queue msg: %+v", queue)
+	}
+	if m.CollectionsList.Visible {
+		t.Fatalf("expected the collections list to close when queueing")
+	}
+}
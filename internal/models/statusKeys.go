@@ -14,8 +14,11 @@ type StatusKeys struct {
 	Enter           key.Binding
 	PlayVideo       key.Binding
 	Pause           key.Binding
+	StopRecording   key.Binding
+	RateLimit       key.Binding
 	Cancel          key.Binding
 	Tab             key.Binding
+	Clip            key.Binding
 	Help            key.Binding
 	Up              key.Binding
 	Down            key.Binding
@@ -26,7 +29,11 @@ type StatusKeys struct {
 	DownloadDefault key.Binding
 	SelectVideos    key.Binding
 	SelectAll       key.Binding
+	LoadMore        key.Binding
+	LoadAll         key.Binding
+	Filters         key.Binding
 	CopyURL         key.Binding
+	Save            key.Binding
 	StarOnGithub    key.Binding
 }
 
@@ -72,6 +79,41 @@ func newPauseKey() key.Binding {
 	)
 }
 
+func newStopRecordingKey() key.Binding {
+	return key.NewBinding(
+		key.WithKeys("f"),
+		key.WithHelp("f", "finish recording"),
+	)
+}
+
+func newFiltersKey() key.Binding {
+	return key.NewBinding(
+		key.WithKeys("ctrl+f"),
+		key.WithHelp("Ctrl+f", "filters"),
+	)
+}
+
+func newLoadMoreKey() key.Binding {
+	return key.NewBinding(
+		key.WithKeys("m"),
+		key.WithHelp("m", "load more"),
+	)
+}
+
+func newLoadAllKey() key.Binding {
+	return key.NewBinding(
+		key.WithKeys("M"),
+		key.WithHelp("M", "load all"),
+	)
+}
+
+func newRateLimitKey() key.Binding {
+	return key.NewBinding(
+		key.WithKeys("l"),
+		key.WithHelp("l", "rate limit"),
+	)
+}
+
 func newPlayVideoKey() key.Binding {
 	return key.NewBinding(
 		key.WithKeys("p"),
@@ -128,6 +170,27 @@ func newCopyURLKey() key.Binding {
 	)
 }
 
+func newClipKey() key.Binding {
+	return key.NewBinding(
+		key.WithKeys("ctrl+x"),
+		key.WithHelp("Ctrl+x", "clip"),
+	)
+}
+
+func newSaveKey() key.Binding {
+	return key.NewBinding(
+		key.WithKeys("w"),
+		key.WithHelp("w", "save"),
+	)
+}
+
+func newSaveCtrlWKey() key.Binding {
+	return key.NewBinding(
+		key.WithKeys("ctrl+w"),
+		key.WithHelp("Ctrl+w", "save"),
+	)
+}
+
 func newStarOnGithubKey() key.Binding {
 	return key.NewBinding(
 		key.WithKeys("ctrl+o"),
@@ -143,6 +206,7 @@ func GetStatusKeys(state types.State, resumeVisible bool) StatusKeys {
 	switch state {
 	case types.StateSearchInput:
 		keys.Quit = newQuitCtrlCKey()
+		keys.Filters = newFiltersKey()
 		keys.StarOnGithub = newStarOnGithubKey()
 		if resumeVisible {
 			keys.Cancel = newCancelEscKey()
@@ -155,16 +219,23 @@ func GetStatusKeys(state types.State, resumeVisible bool) StatusKeys {
 		keys.DownloadDefault = newDownloadDefaultKey()
 		keys.SelectVideos = newSelectVideosKey()
 		keys.SelectAll = newSelectAllKey()
+		keys.LoadMore = newLoadMoreKey()
+		keys.LoadAll = newLoadAllKey()
 		keys.CopyURL = newCopyURLKey()
+		keys.Save = newSaveKey()
 
 	case types.StateFormatList:
 		keys.Back = newBackEscBKey()
+		keys.Clip = newClipKey()
 		keys.CopyURL = newCopyURLKey()
+		keys.Save = newSaveCtrlWKey()
 
 	case types.StateDownload:
 		keys.Back = newBackBKey()
 		keys.Enter = newEnterBackToSearchKey()
 		keys.Pause = newPauseKey()
+		keys.StopRecording = newStopRecordingKey()
+		keys.RateLimit = newRateLimitKey()
 		keys.Cancel = newCancelEscCKey()
 		keys.CopyURL = newCopyURLKey()
 
@@ -190,6 +261,123 @@ func SearchHelpStatusKeys(helpKeys HelpKeys) StatusKeys {
 	}
 }
 
+// DownloadsStatusKeys describes the actions of the /downloads list.
+func DownloadsStatusKeys() StatusKeys {
+	return StatusKeys{
+		Enter: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("Enter", "open"),
+		),
+		PlayVideo: key.NewBinding(
+			key.WithKeys("ctrl+p"),
+			key.WithHelp("Ctrl+p", "play"),
+		),
+		CopyURL: key.NewBinding(
+			key.WithKeys("ctrl+y"),
+			key.WithHelp("Ctrl+y", "copy path"),
+		),
+		DownloadDefault: key.NewBinding(
+			key.WithKeys("ctrl+r"),
+			key.WithHelp("Ctrl+r", "re-download"),
+		),
+		Delete: newDeleteKey(),
+		Cancel: newCancelEscKey(),
+	}
+}
+
+// CollectionsStatusKeys describes the actions of the /collections list,
+// with a collection open or not.
+func CollectionsStatusKeys(open bool) StatusKeys {
+	keys := StatusKeys{
+		Enter: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("Enter", "open"),
+		),
+		PlayVideo: key.NewBinding(
+			key.WithKeys("ctrl+p"),
+			key.WithHelp("Ctrl+p", "play all"),
+		),
+		DownloadDefault: key.NewBinding(
+			key.WithKeys("ctrl+a"),
+			key.WithHelp("Ctrl+a", "download all"),
+		),
+		Save: key.NewBinding(
+			key.WithKeys("ctrl+e"),
+			key.WithHelp("Ctrl+e", "export m3u"),
+		),
+		Delete: newDeleteKey(),
+		Cancel: newCancelEscKey(),
+	}
+
+	if open {
+		keys.Enter = key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("Enter", "play"),
+		)
+		keys.Up = key.NewBinding(
+			key.WithKeys("shift+up", "K"),
+			key.WithHelp("Shift+↑/K", "move up"),
+		)
+		keys.Down = key.NewBinding(
+			key.WithKeys("shift+down", "J"),
+			key.WithHelp("Shift+↓/J", "move down"),
+		)
+		keys.Cancel = key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("Esc", "back"),
+		)
+	}
+
+	return keys
+}
+
+// CollectionPromptStatusKeys describes the keys of the prompt that saves
+// videos to a collection.
+func CollectionPromptStatusKeys() StatusKeys {
+	return StatusKeys{
+		Enter: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("Enter", "save"),
+		),
+		Tab: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("Tab", "next collection"),
+		),
+		Cancel: newCancelEscKey(),
+	}
+}
+
+// ClipEditStatusKeys describes the keys of the clip range editor on the
+// format screen.
+func ClipEditStatusKeys() StatusKeys {
+	return StatusKeys{
+		Enter: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("Enter", "apply"),
+		),
+		Tab: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("Tab", "next field"),
+		),
+		Cancel: newCancelEscKey(),
+	}
+}
+
+// ConfirmPromptStatusKeys describes the keys of the download confirmation
+// on the format screen.
+func ConfirmPromptStatusKeys() StatusKeys {
+	return StatusKeys{
+		Enter: key.NewBinding(
+			key.WithKeys("y", "enter"),
+			key.WithHelp("y/Enter", "download anyway"),
+		),
+		Cancel: key.NewBinding(
+			key.WithKeys("n", "esc"),
+			key.WithHelp("n/Esc", "cancel"),
+		),
+	}
+}
+
 func formatKey(binding key.Binding, italic bool) string {
 	help := binding.Help()
 	if help.Desc == "" && help.Key == "" {
@@ -225,8 +413,11 @@ func orderedStatusFields(keys StatusKeys) []statusKeyField {
 		{name: "Enter", binding: keys.Enter},
 		{name: "PlayVideo", binding: keys.PlayVideo},
 		{name: "Pause", binding: keys.Pause},
+		{name: "StopRecording", binding: keys.StopRecording},
+		{name: "RateLimit", binding: keys.RateLimit},
 		{name: "Cancel", binding: keys.Cancel},
 		{name: "Tab", binding: keys.Tab},
+		{name: "Clip", binding: keys.Clip},
 		{name: "Help", binding: keys.Help},
 		{name: "Up", binding: keys.Up},
 		{name: "Down", binding: keys.Down},
@@ -237,7 +428,11 @@ func orderedStatusFields(keys StatusKeys) []statusKeyField {
 		{name: "DownloadDefault", binding: keys.DownloadDefault},
 		{name: "SelectVideos", binding: keys.SelectVideos},
 		{name: "SelectAll", binding: keys.SelectAll},
+		{name: "LoadMore", binding: keys.LoadMore},
+		{name: "LoadAll", binding: keys.LoadAll},
+		{name: "Filters", binding: keys.Filters},
 		{name: "CopyURL", binding: keys.CopyURL},
+		{name: "Save", binding: keys.Save},
 		{name: "StarOnGithub", binding: keys.StarOnGithub},
 	}
 }
@@ -265,3 +460,29 @@ func FormatKeysForStatusBarItalic(keys StatusKeys, italicKey string) string {
 func FormatSingleKey(binding key.Binding) string {
 	return formatKey(binding, false)
 }
+
+// FilterPanelStatusKeys describes the keys of the search filter panel.
+func FilterPanelStatusKeys() StatusKeys {
+	return StatusKeys{
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "up"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "down"),
+		),
+		Select: key.NewBinding(
+			key.WithKeys("left", "right", " "),
+			key.WithHelp("←/→/Space", "change"),
+		),
+		Delete: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "clear all"),
+		),
+		Cancel: key.NewBinding(
+			key.WithKeys("esc", "enter"),
+			key.WithHelp("Esc/Enter", "close"),
+		),
+	}
+}
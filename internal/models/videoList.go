@@ -28,6 +28,28 @@ type VideoListModel struct {
 	ErrMsg           string
 	DownloadOptions  []types.DownloadOption
 	SelectedVideos   []types.VideoItem
+	// IsFeed is set while the list shows the subscriptions feed, of which
+	// FeedNew items are new.
+	IsFeed  bool
+	FeedNew int
+	// SavePrompt asks which collection to save videos to.
+	SavePrompt CollectionPromptModel
+	// SourceURL is the page the items were listed from, and Next the
+	// playlist index to load more from, or 0 once everything is listed.
+	SourceURL      string
+	Next           int
+	loadingMoreURL string
+	// ChannelTab is the tab shown for a channel. Tabs are fetched the first
+	// time they're opened and kept in channelTabs.
+	ChannelTab  types.ChannelTab
+	LoadingTab  types.ChannelTab
+	channelTabs map[types.ChannelTab]channelTabPage
+}
+
+type channelTabPage struct {
+	items []list.Item
+	url   string
+	next  int
 }
 
 func NewVideoListModel() VideoListModel {
@@ -48,6 +70,7 @@ func NewVideoListModel() VideoListModel {
 		PlaylistName:     "",
 		PlaylistURL:      "",
 		ErrMsg:           "",
+		SavePrompt:       NewCollectionPromptModel(),
 	}
 }
 
@@ -74,30 +97,192 @@ func (m VideoListModel) View() string {
 			headerText = fmt.Sprintf("An Error Occured: %s", m.ErrMsg)
 		}
 	} else if m.IsChannelSearch {
-		headerText = fmt.Sprintf("Videos for channel @%s", m.ChannelName)
+		headerText = fmt.Sprintf("%s for channel @%s", m.ChannelTab.DisplayName(), m.ChannelName)
 		headerStyle = styles.SectionHeaderStyle
 	} else if m.IsPlaylistSearch {
 		headerText = fmt.Sprintf("Playlist: %s", m.PlaylistName)
 		headerStyle = styles.SectionHeaderStyle
+	} else if m.IsFeed {
+		headerText = fmt.Sprintf("Feed: %d new since the last check", m.FeedNew)
+		headerStyle = styles.SectionHeaderStyle
 	} else {
 		headerText = fmt.Sprintf("Search Results for: %s", m.CurrentQuery)
 		headerStyle = styles.SectionHeaderStyle
 	}
 
 	s.WriteString(headerStyle.Render(headerText))
+	if m.LoadingMore() {
+		s.WriteString(styles.FormatTabHelpStyle.Render("  loading more..."))
+	}
 	s.WriteRune('\n')
+	if m.SavePrompt.Visible {
+		s.WriteString(m.SavePrompt.View())
+		s.WriteRune('\n')
+	}
+	if m.showChannelTabs() {
+		s.WriteString(m.renderChannelTabs())
+		s.WriteString("\n\n")
+	}
 	s.WriteString(styles.ListContainer.Render(m.List.View()))
 
 	return s.String()
 }
 
+func (m VideoListModel) renderChannelTabs() string {
+	var tabBar strings.Builder
+	for i, tab := range types.ChannelTabs {
+		style := styles.TabInactiveStyle
+		if tab == m.ChannelTab {
+			style = styles.TabActiveStyle
+		}
+
+		if i > 0 {
+			tabBar.WriteString(" ")
+		}
+
+		tabBar.WriteString(style.Render(" " + tab.DisplayName() + " "))
+	}
+
+	tabBar.WriteString(styles.FormatTabHelpStyle.Render("   (tab to switch)"))
+	return tabBar.String()
+}
+
+func (m VideoListModel) showChannelTabs() bool {
+	return m.IsChannelSearch && m.ErrMsg == ""
+}
+
 func (m VideoListModel) HandleResize(w, h int) VideoListModel {
 	m.Width = w
 	m.Height = h
-	m.List.SetSize(w, h-7)
+	listHeight := h - 7
+	if m.showChannelTabs() {
+		listHeight -= 2
+	}
+	if m.SavePrompt.Visible {
+		listHeight -= 2
+	}
+	m.List.SetSize(w, listHeight)
 	return m
 }
 
+// SetResults shows the first page of a search, channel or playlist.
+func (m *VideoListModel) SetResults(items []list.Item, sourceURL string, next int) {
+	m.SetItems(items)
+	m.SourceURL = sourceURL
+	m.Next = next
+	m.loadingMoreURL = ""
+}
+
+// SetChannelTabItems shows the entries of a channel tab and keeps them for
+// when the tab is opened again.
+func (m *VideoListModel) SetChannelTabItems(tab types.ChannelTab, items []list.Item, sourceURL string, next int) {
+	if m.channelTabs == nil {
+		m.channelTabs = make(map[types.ChannelTab]channelTabPage)
+	}
+
+	m.channelTabs[tab] = channelTabPage{items: items, url: sourceURL, next: next}
+	m.showChannelTab(tab)
+}
+
+// ResetChannelTabs forgets the tabs of the previous channel.
+func (m *VideoListModel) ResetChannelTabs() {
+	m.ChannelTab = types.ChannelTabVideos
+	m.LoadingTab = ""
+	m.channelTabs = nil
+}
+
+func (m *VideoListModel) showChannelTab(tab types.ChannelTab) {
+	page := m.channelTabs[tab]
+	m.ChannelTab = tab
+	m.List.ResetFilter()
+	m.SetItems(page.items)
+	m.UpdateListItems()
+	m.List.ResetSelected()
+	m.SourceURL = page.url
+	m.Next = page.next
+}
+
+// CanLoadMore reports whether there are more results to list and none are
+// being listed already.
+func (m VideoListModel) CanLoadMore() bool {
+	return m.ErrMsg == "" && m.Next > 0 && m.SourceURL != "" && !m.LoadingMore()
+}
+
+func (m VideoListModel) LoadingMore() bool {
+	return m.loadingMoreURL != "" && m.loadingMoreURL == m.SourceURL
+}
+
+// StartLoadMore marks the next page as being listed. It returns false when
+// there's nothing to load.
+func (m *VideoListModel) StartLoadMore() bool {
+	if !m.CanLoadMore() {
+		return false
+	}
+
+	m.loadingMoreURL = m.SourceURL
+	return true
+}
+
+// AppendResults adds a page listed by StartLoadMore after the items already
+// shown, keeping the cursor and the selected videos. Pages of a channel tab
+// that's no longer shown go to that tab. Pages nobody is waiting for anymore
+// are dropped and false is returned.
+func (m *VideoListModel) AppendResults(msg types.MoreResultsMsg) bool {
+	if msg.URL == "" || msg.URL != m.loadingMoreURL {
+		return false
+	}
+
+	m.loadingMoreURL = ""
+	if msg.Err != "" {
+		return true
+	}
+
+	if msg.URL == m.SourceURL {
+		items := append(append([]list.Item{}, m.List.Items()...), msg.Items...)
+		m.SetItems(items)
+		m.UpdateListItems()
+		m.Next = msg.Next
+	}
+
+	for tab, page := range m.channelTabs {
+		if page.url != msg.URL {
+			continue
+		}
+
+		if tab == m.ChannelTab && msg.URL == m.SourceURL {
+			page.items = m.List.Items()
+		} else {
+			page.items = append(append([]list.Item{}, page.items...), msg.Items...)
+		}
+		page.next = msg.Next
+		m.channelTabs[tab] = page
+	}
+
+	return true
+}
+
+// switchChannelTab moves delta tabs along, fetching the tab unless it was
+// opened before.
+func (m *VideoListModel) switchChannelTab(delta int) tea.Cmd {
+	current := 0
+	for i, tab := range types.ChannelTabs {
+		if tab == m.ChannelTab {
+			current = i
+		}
+	}
+
+	n := len(types.ChannelTabs)
+	next := types.ChannelTabs[(current+delta+n)%n]
+	if _, ok := m.channelTabs[next]; ok {
+		m.showChannelTab(next)
+		return nil
+	}
+
+	return func() tea.Msg {
+		return types.StartChannelTabMsg{Tab: next}
+	}
+}
+
 func (m VideoListModel) isVideoSelected(video types.VideoItem) bool {
 	for _, v := range m.SelectedVideos {
 		if v.ID == video.ID {
@@ -148,6 +333,11 @@ func (m VideoListModel) Update(msg tea.Msg) (VideoListModel, tea.Cmd) {
 		listCmd tea.Cmd
 	)
 
+	if m.SavePrompt.Visible {
+		m.SavePrompt, cmd = m.SavePrompt.Update(msg)
+		return m.HandleResize(m.Width, m.Height), cmd
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.String() {
@@ -182,21 +372,7 @@ func (m VideoListModel) Update(msg tea.Msg) (VideoListModel, tea.Cmd) {
 					return m, nil
 				}
 
-				var url string
-				if m.IsPlaylistSearch && m.PlaylistURL != "" {
-					url = utils.BuildPlaylistURL(m.PlaylistURL)
-				} else {
-					url = utils.BuildVideoURL(video.ID)
-				}
-
-				cmd = func() tea.Msg {
-					return types.StartDownloadMsg{
-						URL:             url,
-						FormatID:        formatID,
-						SelectedVideo:   video,
-						DownloadOptions: m.DownloadOptions,
-					}
-				}
+				cmd = m.downloadWithFormat(video, formatID)
 			}
 
 		case "p":
@@ -240,9 +416,39 @@ func (m VideoListModel) Update(msg tea.Msg) (VideoListModel, tea.Cmd) {
 
 				return m, cmd
 			}
+
+		case "w":
+			if !m.List.SettingFilter() {
+				if m.ErrMsg != "" || len(m.List.Items()) == 0 {
+					return m, nil
+				}
+
+				videos := m.SelectedVideos
+				if len(videos) == 0 {
+					video, ok := m.selectedVideo()
+					if !ok || video.ID == "" {
+						return m, nil
+					}
+
+					videos = []types.VideoItem{video}
+				}
+
+				cmd = m.SavePrompt.Show(videos)
+				return m.HandleResize(m.Width, m.Height), cmd
+			}
 		}
 
 		switch msg.Type {
+		case tea.KeyTab, tea.KeyShiftTab:
+			if m.showChannelTabs() && !m.List.SettingFilter() {
+				delta := 1
+				if msg.Type == tea.KeyShiftTab {
+					delta = -1
+				}
+
+				return m, m.switchChannelTab(delta)
+			}
+
 		case tea.KeyEnter:
 			if m.List.SettingFilter() {
 				m.List.SetFilterState(list.FilterApplied)
@@ -257,6 +463,17 @@ func (m VideoListModel) Update(msg tea.Msg) (VideoListModel, tea.Cmd) {
 				return m, nil
 			}
 
+			switch item := m.List.SelectedItem().(type) {
+			case types.PlaylistItem:
+				return m, func() tea.Msg {
+					return types.StartPlaylistURLMsg{Query: item.URL, Title: item.Title()}
+				}
+			case types.ChannelItem:
+				return m, func() tea.Msg {
+					return types.StartChannelURLMsg{URL: item.URL, ChannelName: item.ChannelName}
+				}
+			}
+
 			video, ok := m.selectedVideo()
 			if !ok {
 				return m, nil
@@ -270,6 +487,15 @@ func (m VideoListModel) Update(msg tea.Msg) (VideoListModel, tea.Cmd) {
 				cmd = func() tea.Msg {
 					return types.StartQueueConfirmMsg{Videos: m.SelectedVideos}
 				}
+			} else if video.IsUpcoming() {
+				// The formats of a stream that hasn't started can't be
+				// listed, so wait for it with the default format.
+				cfg, err := config.Load()
+				if err != nil {
+					log.Printf("Warning: Failed to load config: %v", err)
+				}
+
+				cmd = m.downloadWithFormat(video, cfg.GetDefaultFormat())
 			} else {
 				var url string
 				if m.IsPlaylistSearch && m.PlaylistURL != "" {
@@ -301,13 +527,57 @@ func (m VideoListModel) Update(msg tea.Msg) (VideoListModel, tea.Cmd) {
 			if m.ErrMsg == "" {
 				m.SelectAll()
 			}
+
+		case "m":
+			if !m.List.SettingFilter() && m.CanLoadMore() {
+				return m, loadMore(false)
+			}
+
+		case "M":
+			if !m.List.SettingFilter() && m.IsPlaylistSearch && m.CanLoadMore() {
+				return m, loadMore(true)
+			}
 		}
 	}
 
 	m.List, listCmd = m.List.Update(msg)
+	if _, ok := msg.(tea.KeyMsg); ok && m.atEndOfList() && m.CanLoadMore() {
+		cmd = tea.Batch(cmd, loadMore(false))
+	}
+
 	return m, tea.Batch(cmd, listCmd)
 }
 
+// atEndOfList reports whether the cursor is on the last of all the items.
+func (m VideoListModel) atEndOfList() bool {
+	items := len(m.List.Items())
+	return items > 0 && m.List.FilterState() == list.Unfiltered && m.List.Index() == items-1
+}
+
+func loadMore(all bool) tea.Cmd {
+	return func() tea.Msg {
+		return types.LoadMoreMsg{All: all}
+	}
+}
+
+func (m VideoListModel) downloadWithFormat(video types.VideoItem, formatID string) tea.Cmd {
+	var url string
+	if m.IsPlaylistSearch && m.PlaylistURL != "" {
+		url = utils.BuildPlaylistURL(m.PlaylistURL)
+	} else {
+		url = utils.BuildVideoURL(video.ID)
+	}
+
+	return func() tea.Msg {
+		return types.StartDownloadMsg{
+			URL:             url,
+			FormatID:        formatID,
+			SelectedVideo:   video,
+			DownloadOptions: m.DownloadOptions,
+		}
+	}
+}
+
 func ToggleVideoSelection(selected []types.VideoItem, video types.VideoItem) []types.VideoItem {
 	return toggleVideoSelection(selected, video)
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/xdagiz/xytz/internal/types"
+	"github.com/xdagiz/xytz/internal/utils"
 )
 
 func TestVideoListSpaceTogglesSelection(t *testing.T) {
@@ -138,3 +139,197 @@ func TestVideoListPWhileFilteringDoesNothing(t *testing.T) {
 		t.Fatalf("did not expect types.PlayVideoMsg while filtering")
 	}
 }
+
+func TestVideoListEnterOnUpcomingStreamStartsDownload(t *testing.T) {
+	setupModelTestEnv(t)
+
+	video := types.VideoItem{ID: "soon", VideoTitle: "Premiere", LiveStatus: types.LiveStatusUpcoming}
+	m := NewVideoListModel()
+	m.SetItems([]list.Item{video})
+	m.List.Select(0)
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	msg := cmdMsg(t, cmd)
+	got, ok := msg.(types.StartDownloadMsg)
+	if !ok {
+		t.Fatalf("cmd msg type = %T, want types.StartDownloadMsg", msg)
+	}
+	if got.SelectedVideo.ID != "soon" || got.FormatID == "" {
+		t.Fatalf("StartDownloadMsg = %+v, want the upcoming video with the default format", got)
+	}
+}
+
+func TestVideoListTabOpensNextChannelTab(t *testing.T) {
+	setupModelTestEnv(t)
+
+	m := NewVideoListModel()
+	m.IsChannelSearch = true
+	m.ResetChannelTabs()
+	m.SetChannelTabItems(types.ChannelTabVideos, []list.Item{types.VideoItem{ID: "v1", VideoTitle: "Video"}}, "https://www.youtube.com/@c/videos", 0)
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyTab})
+
+	msg := cmdMsg(t, cmd)
+	got, ok := msg.(types.StartChannelTabMsg)
+	if !ok {
+		t.Fatalf("cmd msg type = %T, want types.StartChannelTabMsg", msg)
+	}
+	if got.Tab != types.ChannelTabShorts {
+		t.Fatalf("StartChannelTabMsg.Tab = %q, want %q", got.Tab, types.ChannelTabShorts)
+	}
+}
+
+func TestVideoListTabShowsLoadedChannelTabWithoutFetching(t *testing.T) {
+	setupModelTestEnv(t)
+
+	m := NewVideoListModel()
+	m.IsChannelSearch = true
+	m.ResetChannelTabs()
+	m.SetChannelTabItems(types.ChannelTabVideos, []list.Item{types.VideoItem{ID: "v1", VideoTitle: "Video"}}, "https://www.youtube.com/@c/videos", 0)
+	m.SetChannelTabItems(types.ChannelTabShorts, []list.Item{types.VideoItem{ID: "s1", VideoTitle: "Short"}}, "https://www.youtube.com/@c/shorts", 0)
+
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	if cmd != nil {
+		t.Fatalf("shift+tab to a loaded tab returned a cmd")
+	}
+	if m.ChannelTab != types.ChannelTabVideos {
+		t.Fatalf("ChannelTab = %q, want %q", m.ChannelTab, types.ChannelTabVideos)
+	}
+	if video, ok := m.selectedVideo(); !ok || video.ID != "v1" {
+		t.Fatalf("selected video = %+v, want v1", video)
+	}
+}
+
+func TestVideoListEnterOnPlaylistOpensIt(t *testing.T) {
+	setupModelTestEnv(t)
+
+	playlist := types.PlaylistItem{ID: "PL1", PlaylistTitle: "Mixes", URL: "https://www.youtube.com/playlist?list=PL1"}
+	m := NewVideoListModel()
+	m.IsChannelSearch = true
+	m.ResetChannelTabs()
+	m.SetChannelTabItems(types.ChannelTabPlaylists, []list.Item{playlist}, "https://www.youtube.com/@c/playlists", 0)
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	msg := cmdMsg(t, cmd)
+	got, ok := msg.(types.StartPlaylistURLMsg)
+	if !ok {
+		t.Fatalf("cmd msg type = %T, want types.StartPlaylistURLMsg", msg)
+	}
+	if got.Query != playlist.URL || got.Title != "Mixes" {
+		t.Fatalf("StartPlaylistURLMsg = %+v", got)
+	}
+}
+
+func TestVideoListCursorAtEndLoadsMore(t *testing.T) {
+	setupModelTestEnv(t)
+
+	m := NewVideoListModel()
+	m.SetResults([]list.Item{
+		types.VideoItem{ID: "a", VideoTitle: "A"},
+		types.VideoItem{ID: "b", VideoTitle: "B"},
+	}, "https://www.youtube.com/results?search_query=lofi", 3)
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyDown})
+
+	msg := cmdMsg(t, cmd)
+	if got, ok := msg.(types.LoadMoreMsg); !ok || got.All {
+		t.Fatalf("cmd msg = %#v, want types.LoadMoreMsg for the next page", msg)
+	}
+}
+
+func TestVideoListAppendResultsKeepsCursorAndSelection(t *testing.T) {
+	setupModelTestEnv(t)
+
+	const sourceURL = "https://www.youtube.com/playlist?list=PL1"
+	m := NewVideoListModel()
+	m.IsPlaylistSearch = true
+	m.SetResults([]list.Item{
+		types.VideoItem{ID: "a", VideoTitle: "A"},
+		types.VideoItem{ID: "b", VideoTitle: "B"},
+	}, sourceURL, 3)
+	m.List.Select(1)
+	m.SelectedVideos = []types.VideoItem{{ID: "a", VideoTitle: "A"}}
+	m.UpdateListItems()
+
+	if !m.StartLoadMore() {
+		t.Fatal("StartLoadMore() = false, want true")
+	}
+	if m.StartLoadMore() {
+		t.Fatal("StartLoadMore() while loading = true, want false")
+	}
+
+	if !m.AppendResults(types.MoreResultsMsg{URL: sourceURL, Items: []list.Item{types.VideoItem{ID: "c", VideoTitle: "C"}}}) {
+		t.Fatal("AppendResults() = false, want true")
+	}
+
+	if len(m.List.Items()) != 3 || m.List.Index() != 1 || m.Next != 0 {
+		t.Fatalf("items = %d, index = %d, Next = %d; want 3, 1, 0", len(m.List.Items()), m.List.Index(), m.Next)
+	}
+	if first := m.List.Items()[0].(types.SelectableVideoItem); !first.IsSelected || len(m.SelectedVideos) != 1 {
+		t.Fatalf("selection lost after appending: %+v, %v", first, m.SelectedVideos)
+	}
+	if m.CanLoadMore() {
+		t.Fatal("CanLoadMore() = true after the last page")
+	}
+}
+
+func TestVideoListLoadAllOnlyForPlaylists(t *testing.T) {
+	setupModelTestEnv(t)
+
+	items := []list.Item{types.VideoItem{ID: "a", VideoTitle: "A"}, types.VideoItem{ID: "b", VideoTitle: "B"}}
+	m := NewVideoListModel()
+	m.SetResults(items, "https://www.youtube.com/results?search_query=lofi", 3)
+
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("M")}); cmd != nil {
+		if msg, ok := cmd().(types.LoadMoreMsg); ok && msg.All {
+			t.Fatal("M on search results loaded everything")
+		}
+	}
+
+	m.IsPlaylistSearch = true
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("M")})
+
+	msg := cmdMsg(t, cmd)
+	if got, ok := msg.(types.LoadMoreMsg); !ok || !got.All {
+		t.Fatalf("cmd msg = %#v, want types.LoadMoreMsg{All: true}", msg)
+	}
+}
+
+func TestVideoListWSavesToCollection(t *testing.T) {
+	setupModelTestEnv(t)
+
+	m := NewVideoListModel()
+	m.SetItems([]list.Item{
+		types.VideoItem{ID: "a", VideoTitle: "Video A"},
+		types.VideoItem{ID: "b", VideoTitle: "Video B"},
+	})
+	m.List.Select(0)
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("w")})
+	if !m.SavePrompt.Visible || m.SavePrompt.Input.Value() != utils.DefaultCollection {
+		t.Fatalf("expected the save prompt with %q, got %q", utils.DefaultCollection, m.SavePrompt.Input.Value())
+	}
+
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if toast, ok := cmdMsg(t, cmd).(types.ShowToastMsg); !ok || toast.Message != "Saved to Watch Later" {
+		t.Fatalf("unexpected toast: %+v", toast)
+	}
+	if m.SavePrompt.Visible {
+		t.Fatalf("expected the save prompt to close")
+	}
+
+	m.SelectAll()
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("w")})
+	m.SavePrompt.Input.SetValue("Music")
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if toast := cmdMsg(t, cmd).(types.ShowToastMsg); toast.Message != "Saved 2 videos to Music" {
+		t.Fatalf("unexpected toast: %+v", toast)
+	}
+
+	c, err := utils.LoadCollection("music")
+	if err != nil || len(c.Videos) != 2 {
+		t.Fatalf("LoadCollection() = %+v, %v", c, err)
+	}
+}
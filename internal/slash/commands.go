@@ -32,12 +32,48 @@ var AllCommands = []Command{
 		Usage:       "/play <url>",
 		HasArg:      true,
 	},
+	{
+		Name:        "import",
+		Description: "Queue the URLs listed in a text file",
+		Usage:       "/import <path>",
+		HasArg:      true,
+	},
+	{
+		Name:        "subscribe",
+		Description: "Subscribe to a channel for the feed",
+		Usage:       "/subscribe <username>",
+		HasArg:      true,
+	},
+	{
+		Name:        "unsubscribe",
+		Description: "Unsubscribe from a channel",
+		Usage:       "/unsubscribe <username>",
+		HasArg:      true,
+	},
+	{
+		Name:        "feed",
+		Description: "List new uploads from subscribed channels",
+		Usage:       "/feed",
+		HasArg:      false,
+	},
 	{
 		Name:        "resume",
 		Description: "Resume unfinished download",
 		Usage:       "/resume",
 		HasArg:      false,
 	},
+	{
+		Name:        "downloads",
+		Description: "Browse completed downloads",
+		Usage:       "/downloads",
+		HasArg:      false,
+	},
+	{
+		Name:        "collections",
+		Description: "Browse saved collections",
+		Usage:       "/collections",
+		HasArg:      false,
+	},
 	{
 		Name:        "help",
 		Description: "Show available commands",
@@ -0,0 +1,214 @@
+// Package store reads and writes the files in the data directory. Every
+// write goes to a temporary file that is renamed over the original, and
+// read-modify-write cycles hold an advisory lock so several xytz instances
+// can share the directory without losing entries.
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+)
+
+// ErrCorrupt can be returned by an Update function to have the file moved
+// aside and the update retried on empty contents.
+var ErrCorrupt = errors.New("corrupt data file")
+
+const lockSuffix = ".lock"
+
+// Lock takes an exclusive advisory lock for path. The lock lives in a
+// separate "<path>.lock" file because the data file itself is replaced on
+// every write.
+func Lock(path string) (unlock func(), err error) {
+	return lockPath(path, true)
+}
+
+func lockPath(path string, exclusive bool) (func(), error) {
+	f, err := os.OpenFile(path+lockSuffix, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := lockFile(f, exclusive); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("locking %s: %w", path, err)
+	}
+
+	return func() {
+		if err := unlockFile(f); err != nil {
+			log.Printf("Failed to unlock %s: %v", path, err)
+		}
+		f.Close()
+	}, nil
+}
+
+// Read returns the contents of path under a shared lock. A missing file
+// reads as empty.
+func Read(path string) ([]byte, error) {
+	unlock, err := lockPath(path, false)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// The directory is missing, so the file is too.
+			return nil, nil
+		}
+
+		return nil, err
+	}
+	defer unlock()
+
+	return readFile(path)
+}
+
+// Write atomically replaces the contents of path.
+func Write(path string, data []byte) error {
+	return Update(path, func([]byte) ([]byte, error) {
+		return data, nil
+	})
+}
+
+// Update hands the contents of path to fn and atomically replaces the file
+// with what it returns, holding the lock throughout. If fn returns
+// ErrCorrupt the file is backed up and fn is called again with no data.
+func Update(path string, fn func(data []byte) ([]byte, error)) error {
+	unlock, err := Lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	data, err := readFile(path)
+	if err != nil {
+		return err
+	}
+
+	updated, err := fn(data)
+	if errors.Is(err, ErrCorrupt) {
+		if err := backup(path); err != nil {
+			return err
+		}
+
+		updated, err = fn(nil)
+	}
+	if err != nil {
+		return err
+	}
+
+	return WriteAtomic(path, updated)
+}
+
+// LoadJSON decodes path into v. A corrupt file is backed up and v is left
+// as its zero value, so the store starts fresh.
+func LoadJSON(path string, v any) error {
+	data, err := Read(path)
+	if err != nil {
+		return err
+	}
+
+	if err := decodeJSON(data, v); err == nil {
+		return nil
+	}
+
+	// Recover under the exclusive lock, in case another instance has
+	// rewritten the file since it was read.
+	return UpdateJSON(path, v, func() error { return nil })
+}
+
+// UpdateJSON decodes path into v, calls fn to modify it and writes v back.
+func UpdateJSON(path string, v any, fn func() error) error {
+	return Update(path, func(data []byte) ([]byte, error) {
+		if err := decodeJSON(data, v); err != nil {
+			return nil, err
+		}
+
+		if err := fn(); err != nil {
+			return nil, err
+		}
+
+		return json.MarshalIndent(v, "", "  ")
+	})
+}
+
+func decodeJSON(data []byte, v any) error {
+	reflect.ValueOf(v).Elem().SetZero()
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		reflect.ValueOf(v).Elem().SetZero()
+		return fmt.Errorf("%w: %v", ErrCorrupt, err)
+	}
+
+	return nil
+}
+
+// WriteAtomic writes data to a temporary file next to path and renames it
+// into place, so readers see either the old or the new contents. Callers
+// that modify existing contents should use Update instead.
+func WriteAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+
+	tmpPath := tmp.Name()
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if err := os.Chmod(tmpPath, 0o644); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	return nil
+}
+
+func readFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	return data, err
+}
+
+// backup moves a corrupt file to "<path>.corrupt-<time>" so it can be
+// inspected later.
+func backup(path string) error {
+	dst := fmt.Sprintf("%s.corrupt-%s", path, time.Now().Format("20060102-150405"))
+	if err := os.Rename(path, dst); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return fmt.Errorf("backing up corrupt %s: %w", path, err)
+	}
+
+	log.Printf("Warning: %s was corrupt and has been moved to %s", path, dst)
+	return nil
+}
@@ -0,0 +1,120 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestWriteAtomicLeavesNoTempFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data.json")
+
+	if err := Write(path, []byte("first")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := Write(path, []byte("second")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if string(data) != "second" {
+		t.Errorf("contents = %q, want %q", data, "second")
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	for _, e := range entries {
+		if strings.Contains(e.Name(), ".tmp-") {
+			t.Errorf("temporary file %q left behind", e.Name())
+		}
+	}
+}
+
+func TestReadMissingFile(t *testing.T) {
+	data, err := Read(filepath.Join(t.TempDir(), "missing", "data.json"))
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(data) != 0 {
+		t.Errorf("Read() = %q, want empty", data)
+	}
+}
+
+func TestUpdateIsSerialized(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "counter")
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := Update(path, func(data []byte) ([]byte, error) {
+				n, _ := strconv.Atoi(string(data))
+				return []byte(strconv.Itoa(n + 1)), nil
+			})
+			if err != nil {
+				t.Errorf("Update() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	data, err := Read(path)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if string(data) != "20" {
+		t.Errorf("counter = %q, want 20", data)
+	}
+}
+
+func TestLoadJSONBacksUpCorruptFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data.json")
+	if err := os.WriteFile(path, []byte(`[{"name": "trunc`), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	var items []map[string]string
+	if err := LoadJSON(path, &items); err != nil {
+		t.Fatalf("LoadJSON() error = %v", err)
+	}
+	if len(items) != 0 {
+		t.Errorf("LoadJSON() = %v, want empty", items)
+	}
+
+	backups, _ := filepath.Glob(path + ".corrupt-*")
+	if len(backups) != 1 {
+		t.Fatalf("backups = %v, want one", backups)
+	}
+
+	data, _ := os.ReadFile(backups[0])
+	if string(data) != `[{"name": "trunc` {
+		t.Errorf("backup contents = %q", data)
+	}
+
+	err := UpdateJSON(path, &items, func() error {
+		items = append(items, map[string]string{"name": "fresh"})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("UpdateJSON() error = %v", err)
+	}
+
+	items = nil
+	if err := LoadJSON(path, &items); err != nil {
+		t.Fatalf("LoadJSON() error = %v", err)
+	}
+	if len(items) != 1 || items[0]["name"] != "fresh" {
+		t.Errorf("LoadJSON() = %v, want the fresh entry", items)
+	}
+}
@@ -71,6 +71,10 @@ var (
 	SortHelp  = sortStyle.Foreground(MutedColor).Italic(true)
 	SortItem  = sortStyle.Foreground(MauveColor).PaddingLeft(1).Italic(true)
 
+	LiveBadgeStyle     = lipgloss.NewStyle().Foreground(BlackColor).Background(ErrorColor).Bold(true).Padding(0, 1)
+	UpcomingBadgeStyle = lipgloss.NewStyle().Foreground(BlackColor).Background(WarningColor).Bold(true).Padding(0, 1)
+	NewBadgeStyle      = lipgloss.NewStyle().Foreground(BlackColor).Background(SuccessColor).Bold(true).Padding(0, 1)
+
 	TabActiveStyle   = lipgloss.NewStyle().Foreground(BlackColor).Background(MauveColor)
 	TabInactiveStyle = lipgloss.NewStyle().Foreground(SecondaryColor)
 
@@ -0,0 +1,81 @@
+package types
+
+import "github.com/charmbracelet/bubbles/list"
+
+// ChannelTab is a tab of a channel page, named after its URL path.
+type ChannelTab string
+
+const (
+	ChannelTabVideos    ChannelTab = "videos"
+	ChannelTabShorts    ChannelTab = "shorts"
+	ChannelTabLive      ChannelTab = "streams"
+	ChannelTabPlaylists ChannelTab = "playlists"
+	ChannelTabReleases  ChannelTab = "releases"
+)
+
+var ChannelTabs = []ChannelTab{
+	ChannelTabVideos,
+	ChannelTabShorts,
+	ChannelTabLive,
+	ChannelTabPlaylists,
+	ChannelTabReleases,
+}
+
+func (t ChannelTab) DisplayName() string {
+	switch t {
+	case ChannelTabShorts:
+		return "Shorts"
+	case ChannelTabLive:
+		return "Live"
+	case ChannelTabPlaylists:
+		return "Playlists"
+	case ChannelTabReleases:
+		return "Releases"
+	}
+
+	return "Videos"
+}
+
+// ListsPlaylists reports whether the tab's entries are playlists rather
+// than videos.
+func (t ChannelTab) ListsPlaylists() bool {
+	return t == ChannelTabPlaylists || t == ChannelTabReleases
+}
+
+// PlaylistItem is a playlist or album listed on a channel tab.
+type PlaylistItem struct {
+	ID            string
+	PlaylistTitle string
+	Desc          string
+	URL           string
+	Count         int
+}
+
+func (i PlaylistItem) Title() string       { return i.PlaylistTitle }
+func (i PlaylistItem) Description() string { return i.Desc }
+func (i PlaylistItem) FilterValue() string { return i.PlaylistTitle }
+
+// ChannelItem is a channel found by a search.
+type ChannelItem struct {
+	ID          string
+	Name        string
+	ChannelName string
+	Desc        string
+	URL         string
+}
+
+func (i ChannelItem) Title() string       { return i.Name }
+func (i ChannelItem) Description() string { return i.Desc }
+func (i ChannelItem) FilterValue() string { return i.Name }
+
+type StartChannelTabMsg struct {
+	Tab ChannelTab
+}
+
+type ChannelTabResultMsg struct {
+	Tab   ChannelTab
+	Items []list.Item
+	URL   string
+	Next  int
+	Err   string
+}
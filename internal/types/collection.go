@@ -0,0 +1,7 @@
+package types
+
+// PlayCollectionMsg plays the videos of a collection in order in mpv.
+type PlayCollectionMsg struct {
+	Name   string
+	Videos []VideoItem
+}
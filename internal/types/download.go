@@ -1,6 +1,11 @@
 package types
 
-import tea "github.com/charmbracelet/bubbletea"
+import (
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
 
 type DownloadOption struct {
 	Name           string
@@ -8,6 +13,28 @@ type DownloadOption struct {
 	ConfigField    string
 	RequiresFFmpeg bool
 	Enabled        bool
+	// Modes lists the states of an option that is more than on/off, e.g.
+	// SponsorBlock's "mark" and "remove". Mode is the active one.
+	Modes []string
+	Mode  string
+}
+
+// NextMode cycles a multi-mode option from off through each of its modes and
+// back to off.
+func (o *DownloadOption) NextMode() {
+	next := ""
+	if o.Mode == "" {
+		next = o.Modes[0]
+	} else {
+		for i, mode := range o.Modes {
+			if mode == o.Mode && i+1 < len(o.Modes) {
+				next = o.Modes[i+1]
+			}
+		}
+	}
+
+	o.Mode = next
+	o.Enabled = next != ""
 }
 
 func DownloadOptions() []DownloadOption {
@@ -30,7 +57,81 @@ func DownloadOptions() []DownloadOption {
 			ConfigField:    "EmbedChapters",
 			RequiresFFmpeg: true,
 		},
+		{
+			Name:           "Embed Thumbnail",
+			KeyBinding:     tea.KeyCtrlG,
+			ConfigField:    "EmbedThumbnail",
+			RequiresFFmpeg: true,
+		},
+		{
+			Name:           "SponsorBlock",
+			KeyBinding:     tea.KeyCtrlT,
+			ConfigField:    "SponsorBlock",
+			RequiresFFmpeg: true,
+			Modes:          []string{"mark", "remove"},
+		},
+		{
+			Name:        "Live From Start",
+			KeyBinding:  tea.KeyCtrlE,
+			ConfigField: "LiveFromStart",
+		},
+	}
+}
+
+func IsOptionEnabled(options []DownloadOption, configField string) bool {
+	for _, opt := range options {
+		if opt.ConfigField == configField {
+			return opt.Enabled
+		}
+	}
+
+	return false
+}
+
+// Post-processing stages yt-dlp runs once the streams are downloaded, named
+// after the tag it prints in front of their output.
+const (
+	StageMerger        = "Merger"
+	StageVideoRemuxer  = "VideoRemuxer"
+	StageExtractAudio  = "ExtractAudio"
+	StageEmbedSubtitle = "EmbedSubtitle"
+	StageMetadata      = "Metadata"
+	StageFixupM3u8     = "FixupM3u8"
+)
+
+func StageDisplayName(stage string) string {
+	switch stage {
+	case StageMerger:
+		return "Merging formats"
+	case StageVideoRemuxer:
+		return "Remuxing video"
+	case StageExtractAudio:
+		return "Extracting audio"
+	case StageEmbedSubtitle:
+		return "Embedding subtitles"
+	case StageMetadata:
+		return "Writing metadata"
+	case StageFixupM3u8:
+		return "Fixing HLS stream"
 	}
+
+	return "Post-processing (" + stage + ")"
+}
+
+// StageTickMsg refreshes the elapsed time of the post-processing stage that
+// started at Started.
+type StageTickMsg struct {
+	Started time.Time
+}
+
+func OptionMode(options []DownloadOption, configField string) string {
+	for _, opt := range options {
+		if opt.ConfigField == configField && opt.Enabled {
+			return opt.Mode
+		}
+	}
+
+	return ""
 }
 
 type DownloadRequest struct {
@@ -40,17 +141,81 @@ type DownloadRequest struct {
 	IsAudioTab bool
 	ABR        float64
 
-	Title           string
-	QueueIndex      int
-	QueueTotal      int
-	URLs            []string
-	Videos          []VideoItem
-	UnfinishedKey   string
-	UnfinishedTitle string
-	UnfinishedDesc  string
+	Title      string
+	QueueIndex int
+	QueueTotal int
+	Videos     []VideoItem
+	OutputDir  string
+	RateLimit  string
+	Clip       ClipRange
+	Subtitles  SubtitleRequest
+	Thumbnail  ThumbnailItem
 
 	Options []DownloadOption
+	// ForceRedownload downloads the video again even if it's in the archive.
+	ForceRedownload bool
 
 	CookiesFromBrowser string
 	Cookies            string
 }
+
+type HookResult struct {
+	Command  string
+	ExitCode int
+	Output   string
+	Err      string
+}
+
+type HookResultMsg struct {
+	QueueIndex int
+	Title      string
+	Results    []HookResult
+}
+
+// ClipRange is a section of a video, in seconds from its start. The zero
+// value means the whole video.
+type ClipRange struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+}
+
+func (c ClipRange) IsZero() bool {
+	return c.Start == 0 && c.End == 0
+}
+
+func (c ClipRange) Duration() float64 {
+	return c.End - c.Start
+}
+
+const (
+	SubtitleFormatSRT = "srt"
+	SubtitleFormatVTT = "vtt"
+)
+
+// SubtitleRequest lists the subtitle languages picked on the format screen.
+// They are embedded into the video, or with Only set written next to it as
+// Format files without downloading the video.
+type SubtitleRequest struct {
+	Languages []string `json:"languages,omitempty"`
+	// AutoLanguages are the languages picked from the automatic captions.
+	AutoLanguages []string `json:"auto_languages,omitempty"`
+	Format        string   `json:"format,omitempty"`
+	Only          bool     `json:"only,omitempty"`
+}
+
+func (r SubtitleRequest) IsZero() bool {
+	return len(r.Languages) == 0 && len(r.AutoLanguages) == 0
+}
+
+// AllLanguages returns the uploaded and automatic languages without
+// duplicates.
+func (r SubtitleRequest) AllLanguages() []string {
+	langs := slices.Clone(r.Languages)
+	for _, lang := range r.AutoLanguages {
+		if !slices.Contains(langs, lang) {
+			langs = append(langs, lang)
+		}
+	}
+
+	return langs
+}
@@ -0,0 +1,18 @@
+package types
+
+import "github.com/charmbracelet/bubbles/list"
+
+type StartFeedMsg struct{}
+
+// FeedResultMsg lists the latest uploads of the subscribed channels, newest
+// first.
+type FeedResultMsg struct {
+	Videos []list.Item
+	// Channels is how many subscriptions were checked and New how many of
+	// the videos were uploaded since the last check.
+	Channels int
+	New      int
+	// Failed describes the channels whose uploads couldn't be listed.
+	Failed []string
+	Err    string
+}
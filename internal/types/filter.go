@@ -0,0 +1,288 @@
+package types
+
+import (
+	"encoding/base64"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+type UploadDate string
+
+const (
+	UploadDateAny   UploadDate = ""
+	UploadDateHour  UploadDate = "hour"
+	UploadDateToday UploadDate = "today"
+	UploadDateWeek  UploadDate = "week"
+	UploadDateMonth UploadDate = "month"
+	UploadDateYear  UploadDate = "year"
+)
+
+var UploadDates = []UploadDate{UploadDateAny, UploadDateHour, UploadDateToday, UploadDateWeek, UploadDateMonth, UploadDateYear}
+
+func (d UploadDate) GetDisplayName() string {
+	switch d {
+	case UploadDateHour:
+		return "Last hour"
+	case UploadDateToday:
+		return "Today"
+	case UploadDateWeek:
+		return "This week"
+	case UploadDateMonth:
+		return "This month"
+	case UploadDateYear:
+		return "This year"
+	default:
+		return "Any time"
+	}
+}
+
+type DurationFilter string
+
+const (
+	DurationAny    DurationFilter = ""
+	DurationShort  DurationFilter = "short"
+	DurationMedium DurationFilter = "medium"
+	DurationLong   DurationFilter = "long"
+)
+
+var Durations = []DurationFilter{DurationAny, DurationShort, DurationMedium, DurationLong}
+
+func (d DurationFilter) GetDisplayName() string {
+	switch d {
+	case DurationShort:
+		return "Under 4 minutes"
+	case DurationMedium:
+		return "4-20 minutes"
+	case DurationLong:
+		return "Over 20 minutes"
+	default:
+		return "Any duration"
+	}
+}
+
+type ResultType string
+
+const (
+	ResultTypeAny      ResultType = ""
+	ResultTypeVideo    ResultType = "video"
+	ResultTypeChannel  ResultType = "channel"
+	ResultTypePlaylist ResultType = "playlist"
+	ResultTypeMovie    ResultType = "movie"
+)
+
+var ResultTypes = []ResultType{ResultTypeAny, ResultTypeVideo, ResultTypeChannel, ResultTypePlaylist, ResultTypeMovie}
+
+func (t ResultType) GetDisplayName() string {
+	switch t {
+	case ResultTypeVideo:
+		return "Video"
+	case ResultTypeChannel:
+		return "Channel"
+	case ResultTypePlaylist:
+		return "Playlist"
+	case ResultTypeMovie:
+		return "Movie"
+	default:
+		return "Any type"
+	}
+}
+
+type SearchFeature string
+
+const (
+	Feature4K              SearchFeature = "4k"
+	FeatureHD              SearchFeature = "hd"
+	FeatureSubtitles       SearchFeature = "subtitles"
+	FeatureCreativeCommons SearchFeature = "creative_commons"
+	FeatureLive            SearchFeature = "live"
+)
+
+var SearchFeatures = []SearchFeature{Feature4K, FeatureHD, FeatureSubtitles, FeatureCreativeCommons, FeatureLive}
+
+func (f SearchFeature) GetDisplayName() string {
+	switch f {
+	case Feature4K:
+		return "4K"
+	case FeatureHD:
+		return "HD"
+	case FeatureSubtitles:
+		return "Subtitles/CC"
+	case FeatureCreativeCommons:
+		return "Creative Commons"
+	case FeatureLive:
+		return "Live"
+	default:
+		return string(f)
+	}
+}
+
+// SearchFilters narrows down YouTube search results. The zero value
+// filters nothing.
+type SearchFilters struct {
+	UploadDate UploadDate
+	Duration   DurationFilter
+	Type       ResultType
+	Features   []SearchFeature
+}
+
+// ParseSearchFilters reads filters from their config or flag names and
+// drops the ones it doesn't know.
+func ParseSearchFilters(uploadDate, duration, resultType string, features []string) SearchFilters {
+	f := SearchFilters{
+		UploadDate: parseChoice(UploadDates, UploadDate(strings.ToLower(uploadDate))),
+		Duration:   parseChoice(Durations, DurationFilter(strings.ToLower(duration))),
+		Type:       parseChoice(ResultTypes, ResultType(strings.ToLower(resultType))),
+	}
+
+	for _, name := range features {
+		feature := SearchFeature(strings.ToLower(strings.TrimSpace(name)))
+		if slices.Contains(SearchFeatures, feature) && !f.HasFeature(feature) {
+			f.Features = append(f.Features, feature)
+		}
+	}
+
+	return f
+}
+
+func parseChoice[T comparable](choices []T, value T) T {
+	if slices.Contains(choices, value) {
+		return value
+	}
+
+	var zero T
+	return zero
+}
+
+// CycleChoice returns the choice delta steps from current, wrapping around.
+func CycleChoice[T comparable](choices []T, current T, delta int) T {
+	i := max(slices.Index(choices, current), 0)
+	n := len(choices)
+	return choices[((i+delta)%n+n)%n]
+}
+
+func (f SearchFilters) IsZero() bool {
+	return f.UploadDate == UploadDateAny && f.Duration == DurationAny && f.Type == ResultTypeAny && len(f.Features) == 0
+}
+
+func (f SearchFilters) HasFeature(feature SearchFeature) bool {
+	return slices.Contains(f.Features, feature)
+}
+
+func (f *SearchFilters) ToggleFeature(feature SearchFeature) {
+	if i := slices.Index(f.Features, feature); i >= 0 {
+		f.Features = slices.Delete(slices.Clone(f.Features), i, i+1)
+		return
+	}
+
+	f.Features = append(slices.Clone(f.Features), feature)
+}
+
+// FeatureNames lists the features in the order they're shown, for saving.
+func (f SearchFilters) FeatureNames() []string {
+	var names []string
+	for _, feature := range SearchFeatures {
+		if f.HasFeature(feature) {
+			names = append(names, string(feature))
+		}
+	}
+
+	return names
+}
+
+// Summary describes the active filters on one line.
+func (f SearchFilters) Summary() string {
+	var parts []string
+	if f.UploadDate != UploadDateAny {
+		parts = append(parts, f.UploadDate.GetDisplayName())
+	}
+	if f.Duration != DurationAny {
+		parts = append(parts, f.Duration.GetDisplayName())
+	}
+	if f.Type != ResultTypeAny {
+		parts = append(parts, f.Type.GetDisplayName())
+	}
+	for _, feature := range SearchFeatures {
+		if f.HasFeature(feature) {
+			parts = append(parts, feature.GetDisplayName())
+		}
+	}
+
+	if len(parts) == 0 {
+		return "None"
+	}
+
+	return strings.Join(parts, " • ")
+}
+
+// SearchParam builds the sp parameter of a YouTube search URL for a sort
+// order and filters. It's a base64 protobuf message with the sort order in
+// field 1 and the filters in the message of field 2, escaped the way
+// YouTube itself links to it.
+func SearchParam(sort SortBy, f SearchFilters) string {
+	var filters []byte
+	addField := func(field, value int) {
+		if value > 0 {
+			filters = append(filters, byte(field<<3), byte(value))
+		}
+	}
+
+	addField(1, slices.Index(UploadDates, f.UploadDate))
+	addField(2, slices.Index(ResultTypes, f.Type))
+	addField(3, durationCode(f.Duration))
+	for _, feature := range []struct {
+		field   int
+		feature SearchFeature
+	}{
+		{4, FeatureHD},
+		{5, FeatureSubtitles},
+		{6, FeatureCreativeCommons},
+		{8, FeatureLive},
+		{14, Feature4K},
+	} {
+		if f.HasFeature(feature.feature) {
+			addField(feature.field, 1)
+		}
+	}
+
+	var params []byte
+	if code := sortCode(sort); code > 0 {
+		params = append(params, 1<<3, byte(code))
+	}
+	if len(filters) > 0 {
+		params = append(params, 2<<3|2, byte(len(filters)))
+		params = append(params, filters...)
+	}
+
+	if len(params) == 0 {
+		return ""
+	}
+
+	return url.QueryEscape(url.QueryEscape(base64.StdEncoding.EncodeToString(params)))
+}
+
+func sortCode(s SortBy) int {
+	switch s {
+	case SortByRating:
+		return 1
+	case SortByDate:
+		return 2
+	case SortByViews:
+		return 3
+	default:
+		return 0
+	}
+}
+
+func durationCode(d DurationFilter) int {
+	switch d {
+	case DurationShort:
+		return 1
+	case DurationLong:
+		return 2
+	case DurationMedium:
+		return 3
+	default:
+		return 0
+	}
+}
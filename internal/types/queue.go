@@ -1,5 +1,7 @@
 package types
 
+import "time"
+
 type QueueStatus string
 
 const (
@@ -11,15 +13,24 @@ const (
 )
 
 type QueueItem struct {
-	Index       int
-	Video       VideoItem
-	URL         string
-	Status      QueueStatus
-	Progress    float64
-	Speed       string
-	ETA         string
-	Error       string
-	Destination string
+	Index          int
+	Video          VideoItem
+	URL            string
+	Status         QueueStatus
+	Progress       float64
+	Speed          string
+	ETA            string
+	Elapsed        float64
+	Downloaded     int64
+	Stage          string
+	StageStartedAt time.Time
+	Error          string
+	Destination    string
+	OutputDir      string
+	Paused         bool
+	// ForceRedownload is set when a skipped item is retried, so it's
+	// downloaded again even though it's in the archive.
+	ForceRedownload bool
 }
 
 type QueueState struct {
@@ -41,6 +52,9 @@ type SelectAllVideosMsg struct{}
 
 type StartQueueConfirmMsg struct {
 	Videos []VideoItem
+	// Label names the queue, e.g. after the file it was imported from. It
+	// defaults to the current search.
+	Label string
 }
 
 type StartQueueDownloadMsg struct {
@@ -49,6 +63,10 @@ type StartQueueDownloadMsg struct {
 	ABR             float64
 	DownloadOptions []DownloadOption
 	Videos          []VideoItem
+	// EstimatedSize is the expected size of one video in bytes, 0 if unknown.
+	EstimatedSize  int64
+	SkipSpaceCheck bool
+	Label          string
 }
 
 type StartQueueConfirmWithFormatMsg struct {
@@ -58,6 +76,21 @@ type StartQueueConfirmWithFormatMsg struct {
 	ABR        float64
 }
 
+// StartImportMsg queues the videos listed in a batch file.
+type StartImportMsg struct {
+	Path string
+}
+
+type ImportResultMsg struct {
+	// Label is the file name, which the queue is saved under.
+	Label  string
+	Videos []VideoItem
+	// Skipped counts the lines that weren't video, playlist or channel
+	// links, or that listed no videos.
+	Skipped int
+	Err     string
+}
+
 type QueueProgressMsg struct {
 	Index    int
 	Progress float64
@@ -79,9 +112,17 @@ type QueueCompleteMsg struct {
 
 type QueueCancelledMsg struct{}
 
-type SkipCurrentQueueItemMsg struct{}
+type SkipCurrentQueueItemMsg struct {
+	Index int
+}
+
+type RetryCurrentQueueItemMsg struct {
+	Index int
+}
 
-type RetryCurrentQueueItemMsg struct{}
+type CancelQueueItemMsg struct {
+	Index int
+}
 
 type PauseQueueMsg struct{}
 
@@ -10,18 +10,7 @@ const (
 )
 
 func (s SortBy) GetSPParam() string {
-	switch s {
-	case SortByRelevance:
-		return ""
-	case SortByDate:
-		return "CAI%253D"
-	case SortByViews:
-		return "CAM%253D"
-	case SortByRating:
-		return "CAE%253D"
-	default:
-		return ""
-	}
+	return SearchParam(s, SearchFilters{})
 }
 
 func (s SortBy) GetDisplayName() string {
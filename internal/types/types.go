@@ -1,6 +1,11 @@
 package types
 
 import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/xdagiz/xytz/internal/styles"
 )
@@ -44,17 +49,31 @@ type MPVStartedMsg struct {
 }
 
 type ProgressMsg struct {
-	Percent       float64
-	Speed         string
-	Eta           string
-	Status        string
-	Destination   string
-	FileExtension string
-	QueueIndex    int
-	QueueTotal    int
-	Title         string
+	Percent         float64
+	Speed           string
+	Eta             string
+	Status          string
+	Stage           string
+	Destination     string
+	FileExtension   string
+	DownloadedBytes int64
+	TotalBytes      int64
+	FragmentIndex   int
+	FragmentCount   int
+	// Elapsed is how long the download has been running, in seconds. It
+	// stands in for the percentage when recording a live stream.
+	Elapsed    float64
+	QueueIndex int
+	QueueTotal int
+	Title      string
 }
 
+// Values of yt-dlp's live_status field for streams that haven't ended.
+const (
+	LiveStatusLive     = "is_live"
+	LiveStatusUpcoming = "is_upcoming"
+)
+
 type VideoItem struct {
 	ID         string
 	VideoTitle string
@@ -62,6 +81,32 @@ type VideoItem struct {
 	Views      float64
 	Duration   float64
 	Channel    string
+	UploadDate string
+	LiveStatus string `json:",omitempty"`
+	// ScheduledStart is when an upcoming stream or premiere begins, if known.
+	ScheduledStart time.Time `json:",omitzero"`
+	// Published is when the video was uploaded, if yt-dlp listed it.
+	Published time.Time `json:",omitzero"`
+	// IsNew marks feed items uploaded since the channel was last checked.
+	IsNew bool `json:"-"`
+}
+
+func (i VideoItem) IsLive() bool     { return i.LiveStatus == LiveStatusLive }
+func (i VideoItem) IsUpcoming() bool { return i.LiveStatus == LiveStatusUpcoming }
+
+// Badge is the LIVE or UPCOMING label shown next to a stream's title, or
+// NEW for a feed item that wasn't there at the last check.
+func (i VideoItem) Badge() string {
+	switch {
+	case i.IsLive():
+		return styles.LiveBadgeStyle.Render("LIVE")
+	case i.IsUpcoming():
+		return styles.UpcomingBadgeStyle.Render("UPCOMING")
+	case i.IsNew:
+		return styles.NewBadgeStyle.Render("NEW")
+	}
+
+	return ""
 }
 
 func (i VideoItem) Title() string       { return i.VideoTitle }
@@ -74,11 +119,16 @@ type SelectableVideoItem struct {
 }
 
 func (i SelectableVideoItem) Title() string {
+	title := i.VideoTitle
 	if i.IsSelected {
-		return styles.QueueSelectedItemStyle.Render("✓ " + i.VideoTitle)
+		title = styles.QueueSelectedItemStyle.Render("✓ " + title)
 	}
 
-	return i.VideoTitle
+	if badge := i.Badge(); badge != "" {
+		title += " " + badge
+	}
+
+	return title
 }
 
 func (i SelectableVideoItem) Description() string {
@@ -94,6 +144,23 @@ func (i SelectableVideoItem) FilterValue() string { return i.VideoTitle }
 type SearchResultMsg struct {
 	Videos []list.Item
 	Err    string
+	// URL is the page the results were listed from, and Next the playlist
+	// index of the first entry after them, or 0 when there are no more.
+	URL  string
+	Next int
+}
+
+// LoadMoreMsg asks for the next page of the results shown, or for all the
+// remaining ones.
+type LoadMoreMsg struct {
+	All bool
+}
+
+type MoreResultsMsg struct {
+	URL   string
+	Items []list.Item
+	Next  int
+	Err   string
 }
 
 type FormatItem struct {
@@ -114,10 +181,102 @@ func (i FormatItem) FilterValue() string {
 	return i.FormatTitle + " " + i.FormatValue + " " + i.Size + " " + i.Language + " " + i.Resolution + " " + i.FormatType
 }
 
+// Bytes is the expected download size, or 0 when yt-dlp didn't report one
+// for every stream of the format.
+func (i FormatItem) Bytes() int64 {
+	if strings.Contains(i.FormatValue, "+") && (i.VideoSize == 0 || i.AudioSize == 0) {
+		return 0
+	}
+
+	return int64(i.VideoSize + i.AudioSize)
+}
+
+// SubtitleItem is a subtitle track of a video. Auto marks YouTube's
+// automatic captions, which are listed separately from uploaded subtitles.
+type SubtitleItem struct {
+	Language   string
+	Name       string
+	Auto       bool
+	Formats    []string
+	IsSelected bool
+}
+
+func (i SubtitleItem) Title() string {
+	title := i.Language
+	if i.Name != "" && i.Name != i.Language {
+		title = i.Name + " (" + i.Language + ")"
+	}
+
+	if i.IsSelected {
+		return styles.QueueSelectedItemStyle.Render("✓ " + title)
+	}
+
+	return title
+}
+
+func (i SubtitleItem) Description() string {
+	kind := "subtitles"
+	if i.Auto {
+		kind = "auto-generated"
+	}
+
+	desc := kind
+	if len(i.Formats) > 0 {
+		desc += " • " + strings.Join(i.Formats, ", ")
+	}
+
+	if i.IsSelected {
+		return styles.QueueSelectedItemStyle.Bold(false).Render(desc)
+	}
+
+	return desc
+}
+
+func (i SubtitleItem) FilterValue() string { return i.Language + " " + i.Name }
+
+// Key tells an uploaded track apart from the automatic captions in the same
+// language.
+func (i SubtitleItem) Key() string {
+	if i.Auto {
+		return i.Language + ":auto"
+	}
+
+	return i.Language
+}
+
+// ThumbnailItem is an entry of the thumbnails yt-dlp lists for a video.
+// Width and Height are 0 when it didn't report them.
+type ThumbnailItem struct {
+	ID     string
+	URL    string
+	Width  int
+	Height int
+}
+
+func (i ThumbnailItem) Title() string {
+	if i.Width > 0 && i.Height > 0 {
+		return fmt.Sprintf("%dx%d", i.Width, i.Height)
+	}
+
+	return "unknown size"
+}
+
+func (i ThumbnailItem) Description() string {
+	ext := strings.TrimPrefix(path.Ext(strings.SplitN(i.URL, "?", 2)[0]), ".")
+	if ext == "" {
+		return "id " + i.ID
+	}
+
+	return ext + " • id " + i.ID
+}
+
+func (i ThumbnailItem) FilterValue() string { return i.Title() + " " + i.ID }
+
 type FormatResultMsg struct {
 	VideoFormats     []list.Item
 	AudioFormats     []list.Item
 	ThumbnailFormats []list.Item
+	SubtitleFormats  []list.Item
 	AllFormats       []list.Item
 	VideoInfo        VideoItem
 	Err              string
@@ -130,6 +289,18 @@ type StartDownloadMsg struct {
 	ABR             float64
 	DownloadOptions []DownloadOption
 	SelectedVideo   VideoItem
+	ForceRedownload bool
+	Clip            ClipRange
+	Subtitles       SubtitleRequest
+	Thumbnail       ThumbnailItem
+	// EstimatedSize is the format's expected size in bytes, 0 if unknown.
+	EstimatedSize  int64
+	SkipSpaceCheck bool
+}
+
+type PlayFileMsg struct {
+	Path          string
+	SelectedVideo VideoItem
 }
 
 type DownloadResultMsg struct {
@@ -138,26 +309,51 @@ type DownloadResultMsg struct {
 	Destination string
 	QueueIndex  int
 	QueueTotal  int
+	// NoSpace is set when the download failed because the disk filled up.
+	NoSpace bool
 }
 
 type DownloadCompleteMsg struct{}
 
-type PauseDownloadMsg struct{}
+type SetRateLimitMsg struct {
+	Limit string
+}
 
-type ResumeDownloadMsg struct{}
+type PauseDownloadMsg struct {
+	QueueIndex int
+}
+
+type ResumeDownloadMsg struct {
+	QueueIndex int
+}
 
 type CancelDownloadMsg struct{}
 
+// StopRecordingMsg reports that a live recording was asked to finish.
+type StopRecordingMsg struct {
+	QueueIndex int
+}
+
 type CancelSearchMsg struct{}
 
 type CancelFormatsMsg struct{}
 
+// StartResumeDownloadMsg restarts an unfinished download. Queue is set for
+// queues, whose Items keep the status they had when xytz stopped.
 type StartResumeDownloadMsg struct {
-	URL      string
-	URLs     []string
-	Videos   []VideoItem
-	FormatID string
-	Title    string
+	URL                string
+	Title              string
+	FormatID           string
+	IsAudioTab         bool
+	ABR                float64
+	Options            []DownloadOption
+	CookiesFromBrowser string
+	Cookies            string
+	Clip               ClipRange
+	Subtitles          SubtitleRequest
+	ForceRedownload    bool
+	Queue              bool
+	Items              []QueueItem
 }
 
 type StartChannelURLMsg struct {
@@ -167,6 +363,8 @@ type StartChannelURLMsg struct {
 
 type StartPlaylistURLMsg struct {
 	Query string
+	// Title names the playlist when it's known, e.g. from a channel tab.
+	Title string
 }
 
 type BackFromVideoListMsg struct{}
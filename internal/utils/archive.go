@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/xdagiz/xytz/internal/paths"
+	"github.com/xdagiz/xytz/internal/store"
+)
+
+// ArchiveFileName uses the same "<extractor> <id>" line format as yt-dlp's
+// --download-archive, so the file is shared with the yt-dlp process.
+const ArchiveFileName = "archive.txt"
+
+const archiveExtractor = "youtube"
+
+var GetArchiveFilePath = func() string {
+	dataDir := paths.GetDataDir()
+	if err := paths.EnsureDirExists(dataDir); err != nil {
+		log.Printf("Warning: Could not create data directory: %v", err)
+		return ArchiveFileName
+	}
+
+	return filepath.Join(dataDir, ArchiveFileName)
+}
+
+func ArchiveVideoID(id string) string {
+	id = strings.TrimSpace(id)
+	if extracted := ExtractVideoID(id); extracted != "" {
+		return extracted
+	}
+
+	return id
+}
+
+func parseArchive(data []byte) map[string]bool {
+	archive := make(map[string]bool)
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+
+		archive[fields[1]] = true
+	}
+
+	return archive
+}
+
+func LoadArchive() (map[string]bool, error) {
+	data, err := store.Read(GetArchiveFilePath())
+	if err != nil {
+		return nil, err
+	}
+
+	return parseArchive(data), nil
+}
+
+func IsArchived(id string) bool {
+	id = ArchiveVideoID(id)
+	if id == "" {
+		return false
+	}
+
+	archive, err := LoadArchive()
+	if err != nil {
+		log.Printf("Failed to load download archive: %v", err)
+		return false
+	}
+
+	return archive[id]
+}
+
+func AddToArchive(id string) error {
+	id = ArchiveVideoID(id)
+	if id == "" || strings.Contains(id, "/") {
+		return nil
+	}
+
+	path := GetArchiveFilePath()
+	unlock, err := store.Lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if parseArchive(data)[id] {
+		return nil
+	}
+
+	// Appended rather than rewritten, since yt-dlp appends to the same
+	// file without taking the lock.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = fmt.Fprintf(f, "%s %s\n", archiveExtractor, id)
+	return err
+}
@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"os"
+	"testing"
+)
+
+func TestAddToArchive(t *testing.T) {
+	path := setupArchiveFilePath(t)
+
+	if IsArchived("abc123") {
+		t.Fatalf("IsArchived() = true before adding")
+	}
+
+	if err := AddToArchive("abc123"); err != nil {
+		t.Fatalf("AddToArchive() error = %v", err)
+	}
+	if err := AddToArchive("https://www.youtube.com/watch?v=abc123"); err != nil {
+		t.Fatalf("AddToArchive() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read archive: %v", err)
+	}
+	if string(data) != "youtube abc123\n" {
+		t.Fatalf("archive content = %q, want a single yt-dlp style entry", string(data))
+	}
+
+	if !IsArchived("abc123") {
+		t.Fatalf("IsArchived(id) = false, want true")
+	}
+	if !IsArchived("https://youtu.be/abc123") {
+		t.Fatalf("IsArchived(url) = false, want true")
+	}
+}
+
+func TestLoadArchiveReadsYTDLPEntries(t *testing.T) {
+	path := setupArchiveFilePath(t)
+
+	content := "youtube one\n\nmalformed\nyoutube two\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write archive: %v", err)
+	}
+
+	archive, err := LoadArchive()
+	if err != nil {
+		t.Fatalf("LoadArchive() error = %v", err)
+	}
+	if len(archive) != 2 || !archive["one"] || !archive["two"] {
+		t.Fatalf("LoadArchive() = %v, want one and two", archive)
+	}
+}
+
+func TestAddToArchiveIgnoresNonVideoIDs(t *testing.T) {
+	path := setupArchiveFilePath(t)
+
+	if err := AddToArchive(""); err != nil {
+		t.Fatalf("AddToArchive(\"\") error = %v", err)
+	}
+	if err := AddToArchive("https://www.youtube.com/playlist?list=PL1"); err != nil {
+		t.Fatalf("AddToArchive(playlist) error = %v", err)
+	}
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected no archive file, stat err = %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/xdagiz/xytz/internal/config"
+	"github.com/xdagiz/xytz/internal/types"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ReadURLList reads one URL per line, skipping blank lines and # comments.
+func ReadURLList(r io.Reader) ([]string, error) {
+	var urls []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			urls = append(urls, line)
+		}
+	}
+
+	return urls, scanner.Err()
+}
+
+// ImportBatchFile lists the videos of the URLs in a batch file, expanding
+// playlists and channels to their videos. Lines that aren't video, playlist
+// or channel links are skipped.
+func ImportBatchFile(sm *SearchManager, path, cookiesBrowser, cookiesFile string) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		cfg, err := config.Load()
+		if err != nil {
+			log.Printf("Warning: Failed to load config, using defaults: %v", err)
+			cfg = config.GetDefault()
+		}
+
+		path = cfg.ExpandPath(strings.TrimSpace(path))
+		label := filepath.Base(path)
+
+		f, err := os.Open(path)
+		if err != nil {
+			return types.ImportResultMsg{Label: label, Err: fmt.Sprintf("Failed to open %s: %v", path, err)}
+		}
+		defer f.Close()
+
+		urls, err := ReadURLList(f)
+		if err != nil {
+			return types.ImportResultMsg{Label: label, Err: fmt.Sprintf("Failed to read %s: %v", path, err)}
+		}
+
+		var videos []types.VideoItem
+		var skipped int
+		var lastErr string
+		seen := make(map[string]bool)
+
+		add := func(video types.VideoItem) {
+			if video.ID != "" && !seen[video.ID] {
+				seen[video.ID] = true
+				videos = append(videos, video)
+			}
+		}
+
+		for _, line := range urls {
+			urlType, url := ParseSearchQuery(line)
+			switch urlType {
+			case "video":
+				add(types.VideoItem{ID: ExtractVideoID(line), VideoTitle: url})
+
+			case "playlist", "channel":
+				result := executeYTDLP(sm, url, 1, 0, cookiesBrowser, cookiesFile, parseVideoEntry)
+				if result == nil {
+					return nil
+				}
+
+				msg, ok := result.(types.SearchResultMsg)
+				if !ok || len(msg.Videos) == 0 {
+					log.Printf("Failed to list %s: %v", url, msg.Err)
+					lastErr = msg.Err
+					skipped++
+					continue
+				}
+
+				for _, item := range msg.Videos {
+					if video, ok := item.(types.VideoItem); ok {
+						add(video)
+					}
+				}
+
+			default:
+				log.Printf("Skipping %q from %s: not a video, playlist or channel link", line, path)
+				skipped++
+			}
+		}
+
+		if len(videos) == 0 {
+			errMsg := "No videos found in " + label
+			if lastErr != "" {
+				errMsg += ": " + lastErr
+			}
+
+			return types.ImportResultMsg{Label: label, Skipped: skipped, Err: errMsg}
+		}
+
+		return types.ImportResultMsg{Label: label, Videos: videos, Skipped: skipped}
+	})
+}
@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/xdagiz/xytz/internal/types"
+)
+
+func TestReadURLList(t *testing.T) {
+	input := "# videos to watch\nhttps://youtu.be/aaaaaaaaaaa\n\n   \n  https://youtu.be/bbbbbbbbbbb  \n#https://youtu.be/ccccccccccc\n"
+
+	got, err := ReadURLList(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadURLList() error = %v", err)
+	}
+
+	want := []string{"https://youtu.be/aaaaaaaaaaa", "https://youtu.be/bbbbbbbbbbb"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("ReadURLList() = %v, want %v", got, want)
+	}
+}
+
+func TestImportBatchFileExpandsPlaylistsAndChannels(t *testing.T) {
+	setupFakeSearch(t, 3)
+
+	path := filepath.Join(t.TempDir(), "urls.txt")
+	content := strings.Join([]string{
+		"# mixed list",
+		"https://www.youtube.com/watch?v=aaaaaaaaaaa",
+		"https://www.youtube.com/playlist?list=PLabc",
+		"https://www.youtube.com/@gopher",
+		"just some words",
+		"https://youtu.be/aaaaaaaaaaa",
+	}, "\n")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write batch file: %v", err)
+	}
+
+	msg, ok := ImportBatchFile(NewSearchManager(), path, "", "")().(types.ImportResultMsg)
+	if !ok {
+		t.Fatalf("expected types.ImportResultMsg")
+	}
+	if msg.Err != "" {
+		t.Fatalf("unexpected error: %s", msg.Err)
+	}
+	if msg.Label != "urls.txt" {
+		t.Fatalf("Label = %q, want urls.txt", msg.Label)
+	}
+	if msg.Skipped != 1 {
+		t.Fatalf("Skipped = %d, want 1", msg.Skipped)
+	}
+
+	var ids []string
+	for _, video := range msg.Videos {
+		ids = append(ids, video.ID)
+	}
+	want := []string{"aaaaaaaaaaa", "v1", "v2", "v3"}
+	if !slices.Equal(ids, want) {
+		t.Fatalf("video IDs = %v, want %v", ids, want)
+	}
+}
+
+func TestImportBatchFileErrors(t *testing.T) {
+	setupFakeSearch(t, 3)
+
+	missing := filepath.Join(t.TempDir(), "missing.txt")
+	msg := ImportBatchFile(NewSearchManager(), missing, "", "")().(types.ImportResultMsg)
+	if !strings.HasPrefix(msg.Err, "Failed to open") {
+		t.Fatalf("Err = %q, want open error", msg.Err)
+	}
+
+	empty := filepath.Join(t.TempDir(), "empty.txt")
+	if err := os.WriteFile(empty, []byte("# nothing here\nsome search\n"), 0o644); err != nil {
+		t.Fatalf("write batch file: %v", err)
+	}
+
+	msg = ImportBatchFile(NewSearchManager(), empty, "", "")().(types.ImportResultMsg)
+	if msg.Err != "No videos found in empty.txt" || msg.Skipped != 1 {
+		t.Fatalf("unexpected result: %+v", msg)
+	}
+}
@@ -0,0 +1,111 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/xdagiz/xytz/internal/types"
+)
+
+var (
+	ErrInvalidTimestamp   = errors.New("timestamps must look like 90, 1:30 or 1:02:03")
+	ErrClipEndBeforeStart = errors.New("clip end must be after its start")
+	ErrClipPastDuration   = errors.New("clip ends after the video does")
+)
+
+// ParseTimestamp parses "SS", "MM:SS" or "HH:MM:SS" into seconds. The last
+// component may have a fractional part.
+func ParseTimestamp(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, ErrInvalidTimestamp
+	}
+
+	parts := strings.Split(s, ":")
+	if len(parts) > 3 {
+		return 0, ErrInvalidTimestamp
+	}
+
+	var seconds float64
+	for i, part := range parts {
+		// ParseFloat also takes signs, exponents, hex, NaN and Inf.
+		if part == "" || strings.Trim(part, "0123456789.") != "" {
+			return 0, ErrInvalidTimestamp
+		}
+
+		last := i == len(parts)-1
+
+		var value float64
+		if last {
+			v, err := strconv.ParseFloat(part, 64)
+			if err != nil {
+				return 0, ErrInvalidTimestamp
+			}
+			value = v
+		} else {
+			v, err := strconv.Atoi(part)
+			if err != nil {
+				return 0, ErrInvalidTimestamp
+			}
+			value = float64(v)
+		}
+
+		if i > 0 && value >= 60 {
+			return 0, ErrInvalidTimestamp
+		}
+
+		seconds = seconds*60 + value
+	}
+
+	return seconds, nil
+}
+
+// ParseClipRange validates a start/end pair against the video duration. A
+// duration of 0 means it is unknown and only the order is checked.
+func ParseClipRange(start, end string, duration float64) (types.ClipRange, error) {
+	startSec, err := ParseTimestamp(start)
+	if err != nil {
+		return types.ClipRange{}, fmt.Errorf("start: %w", err)
+	}
+
+	endSec, err := ParseTimestamp(end)
+	if err != nil {
+		return types.ClipRange{}, fmt.Errorf("end: %w", err)
+	}
+
+	if endSec <= startSec {
+		return types.ClipRange{}, ErrClipEndBeforeStart
+	}
+
+	if duration > 0 && endSec > duration {
+		return types.ClipRange{}, fmt.Errorf("%w (%s)", ErrClipPastDuration, FormatDuration(duration))
+	}
+
+	return types.ClipRange{Start: startSec, End: endSec}, nil
+}
+
+func FormatClipRange(clip types.ClipRange) string {
+	return FormatDuration(clip.Start) + "-" + FormatDuration(clip.End)
+}
+
+// clipSection is the --download-sections value for a clip.
+func clipSection(clip types.ClipRange) string {
+	return "*" + formatSeconds(clip.Start) + "-" + formatSeconds(clip.End)
+}
+
+// clipOutputTemplate tags the filename with the clip range so it doesn't
+// collide with a full download of the same video.
+func clipOutputTemplate(tmpl string, clip types.ClipRange) string {
+	suffix := fmt.Sprintf(" [clip %s-%s]", formatSeconds(clip.Start), formatSeconds(clip.End))
+	if i := strings.LastIndex(tmpl, ".%(ext)s"); i >= 0 {
+		return tmpl[:i] + suffix + tmpl[i:]
+	}
+
+	return tmpl + suffix
+}
+
+func formatSeconds(seconds float64) string {
+	return strconv.FormatFloat(seconds, 'f', -1, 64)
+}
@@ -0,0 +1,108 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/xdagiz/xytz/internal/types"
+)
+
+func TestParseTimestamp(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    float64
+		wantErr bool
+	}{
+		{input: "90", want: 90},
+		{input: "1:30", want: 90},
+		{input: "01:02:03", want: 3723},
+		{input: "0:05.5", want: 5.5},
+		{input: " 2:00 ", want: 120},
+		{input: "", wantErr: true},
+		{input: "1:75", wantErr: true},
+		{input: "1:2:3:4", wantErr: true},
+		{input: "-5", wantErr: true},
+		{input: "1e3", wantErr: true},
+		{input: "abc", wantErr: true},
+		{input: "nan", wantErr: true},
+		{input: "NaN", wantErr: true},
+		{input: "inf", wantErr: true},
+		{input: "infinity", wantErr: true},
+		{input: "1:inf", wantErr: true},
+		{input: "0x10", wantErr: true},
+		{input: "+1:00", wantErr: true},
+		{input: "1::00", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseTimestamp(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseTimestamp(%q) = %v, want error", tt.input, got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseTimestamp(%q) error: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Fatalf("ParseTimestamp(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseClipRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		start    string
+		end      string
+		duration float64
+		want     types.ClipRange
+		wantErr  error
+	}{
+		{name: "valid", start: "1:00", end: "2:30", duration: 600, want: types.ClipRange{Start: 60, End: 150}},
+		{name: "unknown duration", start: "0", end: "10:00", want: types.ClipRange{End: 600}},
+		{name: "ends at duration", start: "0", end: "10:00", duration: 600, want: types.ClipRange{End: 600}},
+		{name: "end before start", start: "2:00", end: "1:00", duration: 600, wantErr: ErrClipEndBeforeStart},
+		{name: "empty range", start: "1:00", end: "1:00", duration: 600, wantErr: ErrClipEndBeforeStart},
+		{name: "past duration", start: "0", end: "10:01", duration: 600, wantErr: ErrClipPastDuration},
+		{name: "bad start", start: "x", end: "1:00", duration: 600, wantErr: ErrInvalidTimestamp},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseClipRange(tt.start, tt.end, tt.duration)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("ParseClipRange = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClipOutputTemplate(t *testing.T) {
+	clip := types.ClipRange{Start: 60, End: 150.5}
+
+	if got := clipOutputTemplate("%(title)s.%(ext)s", clip); got != "%(title)s [clip 60-150.5].%(ext)s" {
+		t.Fatalf("clipOutputTemplate = %q", got)
+	}
+	if got := clipSection(clip); got != "*60-150.5" {
+		t.Fatalf("clipSection = %q", got)
+	}
+	if got := FormatClipRange(clip); !strings.HasPrefix(got, "1:00-2:30") {
+		t.Fatalf("FormatClipRange = %q", got)
+	}
+}
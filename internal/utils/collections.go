@@ -0,0 +1,228 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/xdagiz/xytz/internal/config"
+	"github.com/xdagiz/xytz/internal/paths"
+	"github.com/xdagiz/xytz/internal/store"
+	"github.com/xdagiz/xytz/internal/types"
+)
+
+var ErrCollectionNotFound = errors.New("collection not found")
+
+const (
+	CollectionsFileName = "collections.json"
+	// DefaultCollection is offered when videos are saved before any
+	// collection exists.
+	DefaultCollection = "Watch Later"
+)
+
+type Collection struct {
+	Name      string            `json:"name"`
+	Videos    []types.VideoItem `json:"videos"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+var GetCollectionsFilePath = func() string {
+	dataDir := paths.GetDataDir()
+	if err := paths.EnsureDirExists(dataDir); err != nil {
+		log.Printf("Warning: Could not create data directory: %v", err)
+		return CollectionsFileName
+	}
+
+	return filepath.Join(dataDir, CollectionsFileName)
+}
+
+func LoadCollections() ([]Collection, error) {
+	var collections []Collection
+	if err := store.LoadJSON(GetCollectionsFilePath(), &collections); err != nil {
+		return nil, err
+	}
+
+	if collections == nil {
+		return []Collection{}, nil
+	}
+
+	return collections, nil
+}
+
+// LoadCollection returns the collection with the given name.
+func LoadCollection(name string) (Collection, error) {
+	collections, err := LoadCollections()
+	if err != nil {
+		return Collection{}, err
+	}
+
+	i := indexCollection(collections, name)
+	if i < 0 {
+		return Collection{}, ErrCollectionNotFound
+	}
+
+	return collections[i], nil
+}
+
+func indexCollection(collections []Collection, name string) int {
+	return slices.IndexFunc(collections, func(c Collection) bool {
+		return strings.EqualFold(c.Name, strings.TrimSpace(name))
+	})
+}
+
+// updateCollection applies fn to the named collection under the file lock.
+func updateCollection(name string, fn func(*Collection) error) error {
+	var collections []Collection
+	return store.UpdateJSON(GetCollectionsFilePath(), &collections, func() error {
+		i := indexCollection(collections, name)
+		if i < 0 {
+			return ErrCollectionNotFound
+		}
+
+		if err := fn(&collections[i]); err != nil {
+			return err
+		}
+
+		collections[i].UpdatedAt = time.Now()
+		return nil
+	})
+}
+
+// AddToCollection appends videos to the named collection, creating it if
+// needed. Videos already in the collection are left where they are. It
+// returns how many videos were added.
+func AddToCollection(name string, videos []types.VideoItem) (int, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return 0, errors.New("empty collection name")
+	}
+
+	var added int
+	var collections []Collection
+	err := store.UpdateJSON(GetCollectionsFilePath(), &collections, func() error {
+		i := indexCollection(collections, name)
+		if i < 0 {
+			collections = append(collections, Collection{Name: name, Videos: []types.VideoItem{}})
+			i = len(collections) - 1
+		}
+
+		c := &collections[i]
+		for _, video := range videos {
+			if video.ID == "" || slices.ContainsFunc(c.Videos, func(v types.VideoItem) bool { return v.ID == video.ID }) {
+				continue
+			}
+
+			c.Videos = append(c.Videos, video)
+			added++
+		}
+
+		c.UpdatedAt = time.Now()
+		return nil
+	})
+
+	return added, err
+}
+
+// RemoveFromCollection removes a video from the named collection.
+func RemoveFromCollection(name, videoID string) error {
+	return updateCollection(name, func(c *Collection) error {
+		c.Videos = slices.DeleteFunc(c.Videos, func(v types.VideoItem) bool { return v.ID == videoID })
+		return nil
+	})
+}
+
+// MoveInCollection swaps the video at index with its neighbour delta places
+// away and returns the video's new index.
+func MoveInCollection(name string, index, delta int) (int, error) {
+	to := index
+	err := updateCollection(name, func(c *Collection) error {
+		if index < 0 || index >= len(c.Videos) {
+			return fmt.Errorf("no video at position %d", index+1)
+		}
+
+		to = min(max(index+delta, 0), len(c.Videos)-1)
+		c.Videos[index], c.Videos[to] = c.Videos[to], c.Videos[index]
+		return nil
+	})
+
+	return to, err
+}
+
+func DeleteCollection(name string) error {
+	var collections []Collection
+	return store.UpdateJSON(GetCollectionsFilePath(), &collections, func() error {
+		i := indexCollection(collections, name)
+		if i < 0 {
+			return ErrCollectionNotFound
+		}
+
+		collections = slices.Delete(collections, i, i+1)
+		return nil
+	})
+}
+
+// WriteM3U writes videos as an extended M3U playlist of their URLs.
+func WriteM3U(w io.Writer, videos []types.VideoItem) error {
+	var s strings.Builder
+	s.WriteString("#EXTM3U\n")
+	for _, video := range videos {
+		duration := -1
+		if video.Duration > 0 {
+			duration = int(video.Duration)
+		}
+
+		title := video.Title()
+		if video.Channel != "" {
+			title = video.Channel + " - " + title
+		}
+
+		fmt.Fprintf(&s, "#EXTINF:%d,%s\n%s\n", duration, title, BuildVideoURL(video.ID))
+	}
+
+	_, err := io.WriteString(w, s.String())
+	return err
+}
+
+// ExportCollection writes the named collection as an M3U playlist to the
+// download directory and returns the file's path.
+func ExportCollection(name string) (string, error) {
+	c, err := LoadCollection(name)
+	if err != nil {
+		return "", err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Printf("Warning: Failed to load config, using defaults: %v", err)
+		cfg = config.GetDefault()
+	}
+
+	dir := cfg.GetDownloadPath()
+	if err := paths.EnsureDirExists(dir); err != nil {
+		return "", err
+	}
+
+	fileName := sanitizeDirName(c.Name)
+	if fileName == "" {
+		fileName = "collection"
+	}
+
+	path := filepath.Join(dir, fileName+".m3u")
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+
+	if err := WriteM3U(f, c.Videos); err != nil {
+		f.Close()
+		return "", err
+	}
+
+	return path, f.Close()
+}
@@ -0,0 +1,124 @@
+package utils
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xdagiz/xytz/internal/config"
+	"github.com/xdagiz/xytz/internal/types"
+)
+
+func setupCollectionsFilePath(t *testing.T) {
+	t.Helper()
+
+	orig := GetCollectionsFilePath
+	path := filepath.Join(t.TempDir(), "collections.json")
+	GetCollectionsFilePath = func() string { return path }
+	t.Cleanup(func() {
+		GetCollectionsFilePath = orig
+	})
+}
+
+func collectionIDs(t *testing.T, name string) string {
+	t.Helper()
+
+	c, err := LoadCollection(name)
+	if err != nil {
+		t.Fatalf("LoadCollection(%q) error = %v", name, err)
+	}
+
+	ids := make([]string, len(c.Videos))
+	for i, v := range c.Videos {
+		ids[i] = v.ID
+	}
+
+	return strings.Join(ids, ",")
+}
+
+func TestCollectionsAddMoveRemove(t *testing.T) {
+	setupCollectionsFilePath(t)
+
+	videos := []types.VideoItem{{ID: "a"}, {ID: "b"}, {ID: ""}, {ID: "c"}}
+	if added, err := AddToCollection(" Later ", videos); err != nil || added != 3 {
+		t.Fatalf("AddToCollection() = %d, %v, want 3 added", added, err)
+	}
+
+	if added, err := AddToCollection("later", []types.VideoItem{{ID: "b"}, {ID: "d"}}); err != nil || added != 1 {
+		t.Fatalf("AddToCollection() = %d, %v, want only the new video added", added, err)
+	}
+
+	if got := collectionIDs(t, "Later"); got != "a,b,c,d" {
+		t.Fatalf("videos = %s, want a,b,c,d", got)
+	}
+
+	if to, err := MoveInCollection("Later", 3, -1); err != nil || to != 2 {
+		t.Fatalf("MoveInCollection() = %d, %v, want 2", to, err)
+	}
+	if to, _ := MoveInCollection("Later", 0, -1); to != 0 {
+		t.Fatalf("moving the first video up = %d, want 0", to)
+	}
+
+	if err := RemoveFromCollection("Later", "a"); err != nil {
+		t.Fatalf("RemoveFromCollection() error = %v", err)
+	}
+	if got := collectionIDs(t, "Later"); got != "b,d,c" {
+		t.Fatalf("videos = %s, want b,d,c", got)
+	}
+
+	if err := DeleteCollection("LATER"); err != nil {
+		t.Fatalf("DeleteCollection() error = %v", err)
+	}
+	if _, err := LoadCollection("Later"); !errors.Is(err, ErrCollectionNotFound) {
+		t.Fatalf("LoadCollection() after delete error = %v", err)
+	}
+	if err := RemoveFromCollection("Later", "b"); !errors.Is(err, ErrCollectionNotFound) {
+		t.Fatalf("RemoveFromCollection() on missing collection error = %v", err)
+	}
+}
+
+func TestExportCollectionWritesM3U(t *testing.T) {
+	setupCollectionsFilePath(t)
+
+	origConfigDir := config.GetConfigDir
+	configDir := filepath.Join(t.TempDir(), "config")
+	config.GetConfigDir = func() string { return configDir }
+	t.Cleanup(func() { config.GetConfigDir = origConfigDir })
+
+	downloadDir := t.TempDir()
+	cfg := config.GetDefault()
+	cfg.DefaultDownloadPath = downloadDir
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	videos := []types.VideoItem{
+		{ID: "aaaaaaaaaaa", VideoTitle: "First", Channel: "Gopher", Duration: 61.5},
+		{ID: "bbbbbbbbbbb", VideoTitle: "Second"},
+	}
+	if _, err := AddToCollection("Music/Mix", videos); err != nil {
+		t.Fatalf("AddToCollection() error = %v", err)
+	}
+
+	path, err := ExportCollection("music/mix")
+	if err != nil {
+		t.Fatalf("ExportCollection() error = %v", err)
+	}
+	if want := filepath.Join(downloadDir, "Music_Mix.m3u"); path != want {
+		t.Fatalf("path = %q, want %q", path, want)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read export: %v", err)
+	}
+
+	want := "#EXTM3U\n" +
+		"#EXTINF:61,Gopher - First\nhttps://www.youtube.com/watch?v=aaaaaaaaaaa\n" +
+		"#EXTINF:-1,Second\nhttps://www.youtube.com/watch?v=bbbbbbbbbbb\n"
+	if string(data) != want {
+		t.Fatalf("export =\n%s\nwant\n%s", data, want)
+	}
+}
@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// lowDiskSpaceHeadroom is how much more room than the estimate a download
+// should have before xytz stops warning about it.
+const lowDiskSpaceHeadroom = 1.2
+
+// FreeDiskSpace reports the bytes available to the user on the filesystem
+// holding path. Directories that don't exist yet are measured at their
+// nearest existing parent.
+var FreeDiskSpace = func(path string) (int64, error) {
+	return freeDiskSpace(existingParent(path))
+}
+
+func existingParent(path string) string {
+	path = filepath.Clean(path)
+	for {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+
+		parent := filepath.Dir(path)
+		if parent == path {
+			return path
+		}
+		path = parent
+	}
+}
+
+// EstimateRequiredBytes estimates the peak disk usage of a download of size
+// bytes. Merging separate streams or converting audio writes the output
+// while the downloaded parts still exist, which needs about twice the room.
+func EstimateRequiredBytes(size int64, formatID string, isAudio bool) int64 {
+	if strings.Contains(formatID, "+") || isAudio {
+		return size * 2
+	}
+
+	return size
+}
+
+// EstimateQueueBytes estimates the disk usage of count downloads of about
+// size bytes each, of which up to concurrent are post-processed at once.
+func EstimateQueueBytes(size int64, count, concurrent int, formatID string, isAudio bool) int64 {
+	overhead := EstimateRequiredBytes(size, formatID, isAudio) - size
+	return size*int64(count) + overhead*int64(min(max(concurrent, 1), count))
+}
+
+type DiskSpaceCheck struct {
+	Dir      string
+	Required int64
+	Free     int64
+}
+
+func CheckDiskSpace(dir string, required int64) (DiskSpaceCheck, error) {
+	free, err := FreeDiskSpace(dir)
+	if err != nil {
+		return DiskSpaceCheck{}, err
+	}
+
+	return DiskSpaceCheck{Dir: dir, Required: required, Free: free}, nil
+}
+
+func (c DiskSpaceCheck) Enough() bool {
+	return c.Free >= c.Required
+}
+
+// Low reports whether the download fits but leaves little room to spare.
+func (c DiskSpaceCheck) Low() bool {
+	return c.Enough() && float64(c.Free) < float64(c.Required)*lowDiskSpaceHeadroom
+}
+
+// IsNoSpaceError reports whether a line of yt-dlp or ffmpeg output says the
+// disk is full.
+func IsNoSpaceError(line string) bool {
+	return strings.Contains(line, "No space left on device") || strings.Contains(line, "[Errno 28]")
+}
@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestEstimateRequiredBytes(t *testing.T) {
+	tests := []struct {
+		name     string
+		formatID string
+		isAudio  bool
+		want     int64
+	}{
+		{"single file", "18", false, 100},
+		{"merged streams", "137+140", false, 200},
+		{"audio conversion", "140", true, 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EstimateRequiredBytes(100, tt.formatID, tt.isAudio); got != tt.want {
+				t.Fatalf("EstimateRequiredBytes() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEstimateQueueBytes(t *testing.T) {
+	if got := EstimateQueueBytes(100, 5, 2, "137+140", false); got != 700 {
+		t.Fatalf("merged queue = %d, want 700", got)
+	}
+
+	if got := EstimateQueueBytes(100, 1, 3, "137+140", false); got != 200 {
+		t.Fatalf("single merged item = %d, want 200", got)
+	}
+
+	if got := EstimateQueueBytes(100, 5, 2, "18", false); got != 500 {
+		t.Fatalf("unmerged queue = %d, want 500", got)
+	}
+}
+
+func TestDiskSpaceCheck(t *testing.T) {
+	if c := (DiskSpaceCheck{Required: 100, Free: 50}); c.Enough() || c.Low() {
+		t.Fatalf("%+v: want not enough", c)
+	}
+
+	if c := (DiskSpaceCheck{Required: 100, Free: 110}); !c.Enough() || !c.Low() {
+		t.Fatalf("%+v: want enough but low", c)
+	}
+
+	if c := (DiskSpaceCheck{Required: 100, Free: 1000}); !c.Enough() || c.Low() {
+		t.Fatalf("%+v: want plenty", c)
+	}
+}
+
+func TestFreeDiskSpaceUsesExistingParent(t *testing.T) {
+	free, err := FreeDiskSpace(filepath.Join(t.TempDir(), "not", "created", "yet"))
+	if err != nil {
+		t.Fatalf("FreeDiskSpace() error = %v", err)
+	}
+
+	if free <= 0 {
+		t.Fatalf("FreeDiskSpace() = %d, want > 0", free)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"log"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -17,33 +18,21 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 )
 
-func StartDownload(dm *DownloadManager, program *tea.Program, req types.DownloadRequest) tea.Cmd {
-	return tea.Cmd(func() tea.Msg {
-		videos := req.Videos
-		if len(videos) == 0 && req.Title != "" {
-			videos = []types.VideoItem{{ID: req.URL, VideoTitle: req.Title}}
-		}
-
-		key := req.UnfinishedKey
-		if key == "" {
-			key = req.URL
-		}
-		title := req.UnfinishedTitle
-		if title == "" {
-			title = req.Title
-		}
-		unfinished := UnfinishedDownload{
-			URL:       key,
-			FormatID:  req.FormatID,
-			Title:     title,
-			Desc:      req.UnfinishedDesc,
-			URLs:      req.URLs,
-			Videos:    videos,
-			Timestamp: time.Now(),
-		}
+// Sender receives the progress and result messages of a download. A
+// *tea.Program is one.
+type Sender interface {
+	Send(msg tea.Msg)
+}
 
-		if err := AddUnfinished(unfinished); err != nil {
-			log.Printf("Failed to add to unfinished list: %v", err)
+func StartDownload(dm *DownloadManager, program Sender, req types.DownloadRequest) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		// Queue entries are kept up to date by the app. Thumbnails are
+		// quick to fetch again, and /resume would restart them as a video
+		// download.
+		if req.QueueTotal == 0 && req.Thumbnail.URL == "" {
+			if err := AddUnfinished(unfinishedFromRequest(req)); err != nil {
+				log.Printf("Failed to add to unfinished list: %v", err)
+			}
 		}
 
 		cfg, err := config.Load()
@@ -52,52 +41,99 @@ func StartDownload(dm *DownloadManager, program *tea.Program, req types.Download
 			cfg = config.GetDefault()
 		}
 
+		if req.Thumbnail.URL != "" {
+			go doThumbnailDownload(dm, program, req, cfg)
+			return nil
+		}
+
 		go doDownload(dm, program, req, cfg)
 		return nil
 	})
 }
 
-func doDownload(dm *DownloadManager, program *tea.Program, req types.DownloadRequest, cfg *config.Config) {
+// Download runs req to completion, reporting its progress and result to
+// sender, without recording it as unfinished.
+func Download(dm *DownloadManager, sender Sender, req types.DownloadRequest, cfg *config.Config) {
+	if req.Thumbnail.URL != "" {
+		doThumbnailDownload(dm, sender, req, cfg)
+		return
+	}
+
+	doDownload(dm, sender, req, cfg)
+}
+
+func doDownload(dm *DownloadManager, program Sender, req types.DownloadRequest, cfg *config.Config) {
+	for {
+		rateLimit, restart := runDownload(dm, program, req, cfg)
+		if !restart {
+			return
+		}
+
+		req.RateLimit = rateLimit
+	}
+}
+
+func runDownload(dm *DownloadManager, program Sender, req types.DownloadRequest, cfg *config.Config) (string, bool) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	dm.SetContext(ctx, cancel)
+	dm.SetItemContext(req.QueueIndex, ctx, cancel)
 
 	ytdlpPath := "yt-dlp"
 	if cfg.YTDLPPath != "" {
 		ytdlpPath = cfg.YTDLPPath
 	}
 
-	downloadPath := cfg.GetDownloadPath()
+	downloadPath := req.OutputDir
+	if downloadPath == "" {
+		downloadPath = cfg.GetDownloadPath()
+	}
 	url := req.URL
 	formatID := req.FormatID
 	abr := req.ABR
 
 	if url == "" {
 		log.Printf("download error: empty URL provided")
+		dm.ClearItem(req.QueueIndex, ctx)
 		program.Send(types.DownloadResultMsg{Err: "Download error: empty URL provided", QueueIndex: req.QueueIndex, QueueTotal: req.QueueTotal})
-		return
+		return "", false
 	}
 
 	isPlaylist := strings.Contains(url, "/playlist?list=") || strings.Contains(url, "&list=")
 
 	args := []string{
-		"-f",
-		formatID,
 		"--newline",
 		"-R",
 		"infinite",
 		url,
 	}
 
+	if !req.Subtitles.Only {
+		args = append([]string{"-f", formatID}, args...)
+	}
+
+	videoTemplate, audioTemplate := cfg.VideoOutputTemplate, cfg.AudioOutputTemplate
+	if !req.Clip.IsZero() && !req.Subtitles.Only {
+		videoTemplate = clipOutputTemplate(videoTemplate, req.Clip)
+		audioTemplate = clipOutputTemplate(audioTemplate, req.Clip)
+		args = append(args, "--download-sections", clipSection(req.Clip))
+	}
+
+	if len(req.Videos) > 0 {
+		args = append(args, liveArgs(req.Videos[0], req.Options)...)
+	}
+
 	var fileExtension string
 	if req.IsAudioTab {
-		audioQuality := fmt.Sprintf("%dK", int(abr))
+		// Without a known bitrate, ask for the best variable bitrate.
+		audioQuality := "0"
+		if abr > 0 {
+			audioQuality = fmt.Sprintf("%dK", int(abr))
+		}
 		ext := cfg.AudioFormat
 		fileExtension = ext
 		args = append([]string{
 			"-o",
-			filepath.Join(downloadPath, "%(artist)s - %(title)s.%(ext)s"),
-			"--restrict-filenames",
+			filepath.Join(downloadPath, audioTemplate),
 			"-x",
 			"--audio-format",
 			ext,
@@ -112,7 +148,7 @@ func doDownload(dm *DownloadManager, program *tea.Program, req types.DownloadReq
 		fileExtension = ext
 		args = append([]string{
 			"-o",
-			filepath.Join(downloadPath, "%(title)s.%(ext)s"),
+			filepath.Join(downloadPath, videoTemplate),
 			"--merge-output-format",
 			ext,
 			"--remux-video",
@@ -139,35 +175,62 @@ func doDownload(dm *DownloadManager, program *tea.Program, req types.DownloadReq
 		args = append([]string{"--cookies", c}, args...)
 	}
 
+	// A clip or subtitles aren't the whole video, so they neither skip nor
+	// fill the download archive.
+	if req.ForceRedownload {
+		args = append([]string{"--force-overwrites"}, args...)
+	} else if req.Clip.IsZero() && !req.Subtitles.Only {
+		args = append([]string{"--download-archive", GetArchiveFilePath()}, args...)
+	}
+
+	if req.RateLimit != "" {
+		args = append([]string{"--limit-rate", req.RateLimit}, args...)
+	}
+
+	useTemplate := !legacyProgress.Load()
+	if useTemplate {
+		args = append(ProgressTemplateArgs(), args...)
+	}
+
 	if cfg.FFmpegPath != "" {
 		ffmpegPath := cfg.FFmpegPath
 		args = append([]string{"--ffmpeg-path", ffmpegPath}, args...)
 	}
 
+	if mode := types.OptionMode(req.Options, "SponsorBlock"); mode != "" {
+		args = append(args, sponsorBlockArgs(mode, cfg)...)
+	}
+
+	if !req.Subtitles.IsZero() && !req.IsAudioTab {
+		args = append(args, subtitleArgs(req.Subtitles)...)
+	}
+
 	for _, opt := range req.Options {
 		if opt.Enabled {
 			switch opt.ConfigField {
 			case "EmbedSubtitles":
-				args = append(args, "--embed-subs")
+				if req.Subtitles.IsZero() {
+					args = append(args, "--embed-subs")
+				}
 			case "EmbedMetadata":
 				args = append(args, "--embed-metadata")
 			case "EmbedChapters":
 				args = append(args, "--embed-chapters")
+			case "EmbedThumbnail":
+				args = append(args, "--embed-thumbnail")
 			}
 		}
 	}
 
 	cmd := exec.CommandContext(ctx, ytdlpPath, args...)
 
-	dm.SetCmd(cmd)
-	dm.SetPaused(false)
-
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
 		log.Printf("pipe error: %v", err)
 		errMsg := fmt.Sprintf("pipe error: %v", err)
+		dm.ClearItem(req.QueueIndex, ctx)
 		program.Send(types.DownloadResultMsg{Err: errMsg, QueueIndex: req.QueueIndex, QueueTotal: req.QueueTotal})
-		return
+		return "", false
 	}
 
 	stderr, err2 := cmd.StderrPipe()
@@ -175,8 +238,9 @@ func doDownload(dm *DownloadManager, program *tea.Program, req types.DownloadReq
 		stdout.Close()
 		log.Printf("stderr pipe error: %v", err2)
 		errMsg := fmt.Sprintf("stderr pipe error: %v", err2)
+		dm.ClearItem(req.QueueIndex, ctx)
 		program.Send(types.DownloadResultMsg{Err: errMsg, QueueIndex: req.QueueIndex, QueueTotal: req.QueueTotal})
-		return
+		return "", false
 	}
 
 	if err := cmd.Start(); err != nil {
@@ -184,72 +248,106 @@ func doDownload(dm *DownloadManager, program *tea.Program, req types.DownloadReq
 		stderr.Close()
 		log.Printf("start error: %v", err)
 		errMsg := fmt.Sprintf("start error: %v", err)
+		dm.ClearItem(req.QueueIndex, ctx)
 		program.Send(types.DownloadResultMsg{Err: errMsg, QueueIndex: req.QueueIndex, QueueTotal: req.QueueTotal})
-		return
+		return "", false
 	}
 
+	// Only publish the command once Start has set its Process, which cancel,
+	// restart and pause read from other goroutines. A cancel that lands before
+	// this still stops the process through ctx.
+	dm.SetItemCmd(req.QueueIndex, cmd)
+	dm.SetItemPaused(req.QueueIndex, false)
+
 	parser := NewProgressParser()
 	var (
 		wg              sync.WaitGroup
+		destinationMu   sync.Mutex
 		lastDestination string
 	)
 
 	readPipe := func(pipe io.Reader) {
 		defer wg.Done()
-		parser.ReadPipe(pipe, func(percent float64, speed, eta, status, destination string) {
-			if destination != "" {
-				lastDestination = destination
+		parser.ReadPipe(pipe, func(progress Progress) {
+			if progress.Destination != "" {
+				destinationMu.Lock()
+				lastDestination = progress.Destination
+				destinationMu.Unlock()
 			}
 
 			program.Send(types.ProgressMsg{
-				Percent:       percent,
-				Speed:         speed,
-				Eta:           eta,
-				Status:        status,
-				Destination:   destination,
-				FileExtension: fileExtension,
-				QueueIndex:    req.QueueIndex,
-				QueueTotal:    req.QueueTotal,
-				Title:         req.Title,
+				Percent:         progress.Percent,
+				Speed:           progress.Speed,
+				Eta:             progress.Eta,
+				Status:          progress.Status,
+				Stage:           progress.Stage,
+				Destination:     progress.Destination,
+				FileExtension:   fileExtension,
+				DownloadedBytes: progress.DownloadedBytes,
+				TotalBytes:      progress.TotalBytes,
+				FragmentIndex:   progress.FragmentIndex,
+				FragmentCount:   progress.FragmentCount,
+				Elapsed:         progress.Elapsed,
+				QueueIndex:      req.QueueIndex,
+				QueueTotal:      req.QueueTotal,
+				Title:           req.Title,
 			})
 		})
 	}
 
+	go func() {
+		<-ctx.Done()
+		_ = stdout.Close()
+		_ = stderr.Close()
+	}()
+
+	// Drain the pipes before Wait, which closes them as soon as yt-dlp exits
+	// and would drop its last lines (the merge and post-processing output).
 	wg.Add(2)
 	go readPipe(stdout)
 	go readPipe(stderr)
-	err = cmd.Wait()
-	_ = stdout.Close()
-	_ = stderr.Close()
 	wg.Wait()
+	err = cmd.Wait()
 
 	if cmd.Process != nil && cmd.ProcessState != nil && !cmd.ProcessState.Exited() {
 		_ = cmd.Process.Kill()
 	}
 
-	dm.Clear()
+	rateLimit, restart := dm.takeRestart(req.QueueIndex, ctx)
+	dm.ClearItem(req.QueueIndex, ctx)
+	if restart {
+		return rateLimit, true
+	}
 
-	key := req.UnfinishedKey
-	if key == "" {
-		key = url
+	if err != nil && useTemplate && parser.TemplateUnsupported() {
+		log.Printf("yt-dlp doesn't support --progress-template, retrying with plain progress output")
+		legacyProgress.Store(true)
+		return req.RateLimit, true
 	}
 
 	if ctx.Err() == context.Canceled {
 		program.Send(types.DownloadResultMsg{Err: "Download cancelled", QueueIndex: req.QueueIndex, QueueTotal: req.QueueTotal})
-		return
+		return "", false
 	}
 
 	if err != nil {
 		errMsg := fmt.Sprintf("Download error: %v", err)
+		if parser.NoSpace() {
+			errMsg = "Download error: no space left on device"
+		}
 		log.Print(errMsg)
-		program.Send(types.DownloadResultMsg{Err: errMsg, QueueIndex: req.QueueIndex, QueueTotal: req.QueueTotal})
+		program.Send(types.DownloadResultMsg{Err: errMsg, NoSpace: parser.NoSpace(), QueueIndex: req.QueueIndex, QueueTotal: req.QueueTotal})
 	} else {
-		if req.QueueTotal == 0 || req.QueueIndex >= req.QueueTotal {
-			if err := RemoveUnfinished(key); err != nil {
+		if req.QueueTotal == 0 {
+			if err := RemoveUnfinished(url); err != nil {
 				log.Printf("Failed to remove from unfinished list: %v", err)
 			}
 		}
 
+		if req.Subtitles.Only && lastDestination != "" {
+			lastDestination = subtitleDestination(lastDestination, req.Subtitles.Format)
+		}
+
 		program.Send(types.DownloadResultMsg{
 			Output:      "Download complete",
 			Destination: lastDestination,
@@ -257,4 +355,143 @@ func doDownload(dm *DownloadManager, program *tea.Program, req types.DownloadReq
 			QueueTotal:  req.QueueTotal,
 		})
 	}
+
+	return "", false
+}
+
+// ConfigDownloadOptions returns the download options turned on or off as
+// the config sets them.
+func ConfigDownloadOptions(cfg *config.Config) []types.DownloadOption {
+	options := types.DownloadOptions()
+	for i := range options {
+		switch options[i].ConfigField {
+		case "EmbedSubtitles":
+			options[i].Enabled = cfg.EmbedSubtitles
+		case "EmbedMetadata":
+			options[i].Enabled = cfg.EmbedMetadata
+		case "EmbedChapters":
+			options[i].Enabled = cfg.EmbedChapters
+		case "EmbedThumbnail":
+			options[i].Enabled = cfg.EmbedThumbnail
+		case "LiveFromStart":
+			options[i].Enabled = cfg.LiveFromStart
+		case "SponsorBlock":
+			options[i].Mode = cfg.SponsorBlockMode
+			options[i].Enabled = cfg.SponsorBlockMode != config.SponsorBlockOff
+		}
+	}
+
+	return options
+}
+
+// liveWaitInterval is how often, in seconds, yt-dlp checks whether an
+// upcoming stream without a known start time has begun.
+const liveWaitInterval = 60
+
+// liveArgs records a live stream, optionally from its beginning, and has
+// yt-dlp wait for an upcoming stream or premiere to start.
+func liveArgs(video types.VideoItem, options []types.DownloadOption) []string {
+	var args []string
+	if video.IsUpcoming() {
+		args = append(args, "--wait-for-video", strconv.Itoa(liveWaitInterval))
+	}
+
+	if (video.IsLive() || video.IsUpcoming()) && types.IsOptionEnabled(options, "LiveFromStart") {
+		args = append(args, "--live-from-start")
+	}
+
+	return args
+}
+
+func unfinishedFromRequest(req types.DownloadRequest) UnfinishedDownload {
+	video := types.VideoItem{ID: req.URL, VideoTitle: req.Title}
+	if len(req.Videos) > 0 {
+		video = req.Videos[0]
+	}
+
+	return UnfinishedDownload{
+		URL:                req.URL,
+		FormatID:           req.FormatID,
+		Title:              req.Title,
+		IsAudio:            req.IsAudioTab,
+		ABR:                req.ABR,
+		Options:            SaveOptions(req.Options),
+		CookiesFromBrowser: req.CookiesFromBrowser,
+		Cookies:            req.Cookies,
+		Clip:               req.Clip,
+		Subtitles:          req.Subtitles,
+		ForceRedownload:    req.ForceRedownload,
+		Items: []UnfinishedItem{{
+			URL:       req.URL,
+			Video:     video,
+			OutputDir: req.OutputDir,
+			Status:    types.QueueStatusPending,
+		}},
+		Timestamp: time.Now(),
+	}
+}
+
+func subtitleArgs(sub types.SubtitleRequest) []string {
+	// --embed-subs only writes uploaded subtitles on its own, so ask for
+	// each kind that was picked.
+	args := []string{"--sub-langs", strings.Join(sub.AllLanguages(), ",")}
+	if len(sub.Languages) > 0 {
+		args = append(args, "--write-subs")
+	}
+	if len(sub.AutoLanguages) > 0 {
+		args = append(args, "--write-auto-subs")
+	}
+
+	if !sub.Only {
+		return append(args, "--embed-subs")
+	}
+
+	format := sub.Format
+	if format == "" {
+		format = types.SubtitleFormatSRT
+	}
+
+	return append(args, "--skip-download", "--sub-format", format+"/best", "--convert-subs", format)
+}
+
+// subtitleDestination points at the converted subtitle file, as yt-dlp only
+// reports the name it wrote before converting.
+func subtitleDestination(path, format string) string {
+	if format == "" {
+		format = types.SubtitleFormatSRT
+	}
+
+	return strings.TrimSuffix(path, filepath.Ext(path)) + "." + format
+}
+
+func sponsorBlockArgs(mode string, cfg *config.Config) []string {
+	var args []string
+	switch mode {
+	case config.SponsorBlockMark:
+		args = append(args, "--sponsorblock-mark", strings.Join(cfg.SponsorBlockCategories, ","))
+	case config.SponsorBlockRemove:
+		// yt-dlp refuses the whole download when asked to remove a category
+		// that can only be marked.
+		var categories []string
+		for _, category := range cfg.SponsorBlockCategories {
+			if config.SponsorBlockRemovable(category) {
+				categories = append(categories, category)
+			}
+		}
+
+		if len(categories) == 0 {
+			log.Printf("Warning: None of the SponsorBlock categories can be removed, only marked")
+			return nil
+		}
+
+		args = append(args, "--sponsorblock-remove", strings.Join(categories, ","))
+	default:
+		return nil
+	}
+
+	if cfg.SponsorBlockAPI != "" {
+		args = append(args, "--sponsorblock-api", cfg.SponsorBlockAPI)
+	}
+
+	return args
 }
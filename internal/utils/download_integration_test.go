@@ -4,6 +4,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"testing"
@@ -96,6 +97,19 @@ func setupUnfinishedFilePath(t *testing.T) {
 	})
 }
 
+func setupArchiveFilePath(t *testing.T) string {
+	t.Helper()
+
+	orig := GetArchiveFilePath
+	path := filepath.Join(t.TempDir(), "archive.txt")
+	GetArchiveFilePath = func() string { return path }
+	t.Cleanup(func() {
+		GetArchiveFilePath = orig
+	})
+
+	return path
+}
+
 func setupDownloadConfigDir(t *testing.T) {
 	t.Helper()
 
@@ -122,6 +136,18 @@ func waitForManagerReady(t *testing.T, dm *DownloadManager) {
 	t.Fatalf("download manager context was not initialized in time")
 }
 
+// waitForDownloadResult lets the download StartDownload runs in the
+// background finish before the test's path overrides are restored.
+func waitForDownloadResult(t *testing.T, m *downloadCollectorModel) {
+	t.Helper()
+
+	select {
+	case <-m.done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for download result")
+	}
+}
+
 func makeExecutable(t *testing.T, name, content string) string {
 	t.Helper()
 
@@ -248,6 +274,107 @@ func TestDoDownload_SuccessSendsProgressAndResult(t *testing.T) {
 	}
 }
 
+func TestDoDownload_ProgressTemplateSendsStructuredProgress(t *testing.T) {
+	setupUnfinishedFilePath(t)
+
+	m, p := runCollectorProgram(t)
+	dm := NewDownloadManager()
+
+	tmpDir := t.TempDir()
+	script := "#!/usr/bin/env bash\n" +
+		"echo 'xytz-progress:137 avc1 none {\"status\": \"downloading\", \"downloaded_bytes\": 50, \"total_bytes\": 200, \"fragment_index\": 1, \"fragment_count\": 4}'\n" +
+		"echo 'xytz-postprocess:FFmpegMerger started'\n" +
+		"echo '[Merger] Merging formats into \"/tmp/merged.mp4\"'\n" +
+		"exit 0\n"
+	ytdlp := makeExecutable(t, "fake-yt-dlp.sh", script)
+
+	cfg := config.GetDefault()
+	cfg.YTDLPPath = ytdlp
+	cfg.DefaultDownloadPath = tmpDir
+
+	doDownload(dm, p, types.DownloadRequest{
+		URL:      "https://www.youtube.com/watch?v=abc",
+		FormatID: "137+140",
+		Title:    "Video",
+	}, cfg)
+
+	select {
+	case <-m.done:
+	case <-time.After(3 * time.Second):
+		t.Fatalf("timed out waiting for download result")
+	}
+
+	progress, results := m.snapshot()
+	if len(progress) < 3 {
+		t.Fatalf("expected 3 ProgressMsg, got %d", len(progress))
+	}
+
+	first := progress[0]
+	if first.Percent != 25 || first.DownloadedBytes != 50 || first.TotalBytes != 200 || first.FragmentCount != 4 {
+		t.Fatalf("unexpected structured progress: %+v", first)
+	}
+	if first.Status != "[download] video (137)" {
+		t.Fatalf("Status = %q, want video stream", first.Status)
+	}
+	if progress[1].Stage != types.StageMerger {
+		t.Fatalf("Stage = %q, want %q", progress[1].Stage, types.StageMerger)
+	}
+	if got := results[len(results)-1].Destination; got != "/tmp/merged.mp4" {
+		t.Fatalf("Destination = %q, want merged file", got)
+	}
+}
+
+func TestDoDownload_FallsBackWhenProgressTemplateUnsupported(t *testing.T) {
+	setupUnfinishedFilePath(t)
+	t.Cleanup(func() { legacyProgress.Store(false) })
+
+	m, p := runCollectorProgram(t)
+	dm := NewDownloadManager()
+
+	tmpDir := t.TempDir()
+	script := "#!/usr/bin/env bash\n" +
+		"for arg in \"$@\"; do\n" +
+		"  if [ \"$arg\" = \"--progress-template\" ]; then\n" +
+		"    echo 'yt-dlp: error: no such option: --progress-template' >&2\n" +
+		"    exit 2\n" +
+		"  fi\n" +
+		"done\n" +
+		"echo '[download] Destination: /tmp/legacy.mp4'\n" +
+		"echo '[download] 50% of 10.00MiB at 2.00MiB/s ETA 00:03'\n" +
+		"exit 0\n"
+	ytdlp := makeExecutable(t, "fake-yt-dlp-legacy.sh", script)
+
+	cfg := config.GetDefault()
+	cfg.YTDLPPath = ytdlp
+	cfg.DefaultDownloadPath = tmpDir
+
+	doDownload(dm, p, types.DownloadRequest{
+		URL:      "https://www.youtube.com/watch?v=abc",
+		FormatID: "best",
+		Title:    "Video",
+	}, cfg)
+
+	select {
+	case <-m.done:
+	case <-time.After(3 * time.Second):
+		t.Fatalf("timed out waiting for download result")
+	}
+
+	_, results := m.snapshot()
+	if len(results) != 1 {
+		t.Fatalf("expected one DownloadResultMsg, got %d", len(results))
+	}
+	if results[0].Err != "" {
+		t.Fatalf("expected success after fallback, got error: %q", results[0].Err)
+	}
+	if results[0].Destination != "/tmp/legacy.mp4" {
+		t.Fatalf("Destination = %q, want /tmp/legacy.mp4", results[0].Destination)
+	}
+	if !legacyProgress.Load() {
+		t.Fatalf("expected legacy progress mode to be remembered")
+	}
+}
+
 func TestDoDownload_CancelSendsCancelled(t *testing.T) {
 	setupUnfinishedFilePath(t)
 
@@ -301,7 +428,6 @@ func TestStartDownload_PersistsSingleVideoInfoForResume(t *testing.T) {
 	setupDownloadConfigDir(t)
 
 	m, p := runCollectorProgram(t)
-	_ = m
 	dm := NewDownloadManager()
 
 	cmd := StartDownload(dm, p, types.DownloadRequest{
@@ -317,19 +443,20 @@ func TestStartDownload_PersistsSingleVideoInfoForResume(t *testing.T) {
 	if msg != nil {
 		t.Fatalf("start command msg = %T, want nil", msg)
 	}
+	waitForDownloadResult(t, m)
 
 	entry := GetUnfinishedByURL("https://www.youtube.com/watch?v=abc123")
 	if entry == nil {
 		t.Fatalf("expected unfinished entry to exist")
 	}
-	if len(entry.Videos) != 1 {
-		t.Fatalf("entry.Videos len = %d, want 1", len(entry.Videos))
+	if len(entry.Items) != 1 {
+		t.Fatalf("entry.Items len = %d, want 1", len(entry.Items))
 	}
-	if entry.Videos[0].ID != "https://www.youtube.com/watch?v=abc123" {
-		t.Fatalf("entry.Videos[0].ID = %q, want URL", entry.Videos[0].ID)
+	if entry.Items[0].Video.ID != "https://www.youtube.com/watch?v=abc123" {
+		t.Fatalf("entry.Items[0].Video.ID = %q, want URL", entry.Items[0].Video.ID)
 	}
-	if entry.Videos[0].VideoTitle != "Saved Title" {
-		t.Fatalf("entry.Videos[0].VideoTitle = %q, want %q", entry.Videos[0].VideoTitle, "Saved Title")
+	if entry.Items[0].Video.VideoTitle != "Saved Title" {
+		t.Fatalf("entry.Items[0].Video.VideoTitle = %q, want %q", entry.Items[0].Video.VideoTitle, "Saved Title")
 	}
 }
 
@@ -337,7 +464,7 @@ func TestStartDownload_PersistsFullSingleVideoMetadata(t *testing.T) {
 	setupUnfinishedFilePath(t)
 	setupDownloadConfigDir(t)
 
-	_, p := runCollectorProgram(t)
+	m, p := runCollectorProgram(t)
 	dm := NewDownloadManager()
 
 	video := types.VideoItem{
@@ -350,25 +477,573 @@ func TestStartDownload_PersistsFullSingleVideoMetadata(t *testing.T) {
 	}
 
 	cmd := StartDownload(dm, p, types.DownloadRequest{
-		URL:      video.ID,
-		FormatID: "best",
-		Title:    video.Title(),
-		Videos:   []types.VideoItem{video},
+		URL:             video.ID,
+		FormatID:        "best",
+		Title:           video.Title(),
+		Videos:          []types.VideoItem{video},
+		Subtitles:       types.SubtitleRequest{Languages: []string{"en"}},
+		ForceRedownload: true,
 	})
 	if cmd == nil {
 		t.Fatalf("expected non-nil start command")
 	}
 	_ = cmd()
+	waitForDownloadResult(t, m)
 
 	entry := GetUnfinishedByURL(video.ID)
 	if entry == nil {
 		t.Fatalf("expected unfinished entry to exist")
 	}
-	if len(entry.Videos) != 1 {
-		t.Fatalf("entry.Videos len = %d, want 1", len(entry.Videos))
+	if len(entry.Items) != 1 {
+		t.Fatalf("entry.Items len = %d, want 1", len(entry.Items))
 	}
-	got := entry.Videos[0]
+	got := entry.Items[0].Video
 	if got.Desc != "Meta Description" || got.Views != 12345 || got.Duration != 321 || got.Channel != "Meta Channel" {
 		t.Fatalf("video metadata not preserved: %+v", got)
 	}
+	if !slices.Equal(entry.Subtitles.Languages, []string{"en"}) || !entry.ForceRedownload {
+		t.Fatalf("subtitles/force not preserved: %+v/%v", entry.Subtitles, entry.ForceRedownload)
+	}
+}
+
+func TestDoDownload_DownloadArchiveArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		force    bool
+		want     string
+		dontWant string
+	}{
+		{name: "uses archive by default", want: "--download-archive", dontWant: "--force-overwrites"},
+		{name: "force re-download skips archive", force: true, want: "--force-overwrites", dontWant: "--download-archive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupUnfinishedFilePath(t)
+			archivePath := setupArchiveFilePath(t)
+
+			m, p := runCollectorProgram(t)
+			dm := NewDownloadManager()
+
+			tmpDir := t.TempDir()
+			argsPath := filepath.Join(tmpDir, "args.txt")
+			ytdlp := makeExecutable(t, "fake-yt-dlp.sh", "#!/usr/bin/env bash\nprintf '%s\\n' \"$@\" > \""+argsPath+"\"\nexit 0\n")
+
+			cfg := config.GetDefault()
+			cfg.YTDLPPath = ytdlp
+			cfg.DefaultDownloadPath = tmpDir
+
+			doDownload(dm, p, types.DownloadRequest{
+				URL:             "https://www.youtube.com/watch?v=abc",
+				FormatID:        "best",
+				ForceRedownload: tt.force,
+			}, cfg)
+
+			select {
+			case <-m.done:
+			case <-time.After(3 * time.Second):
+				t.Fatalf("timed out waiting for download result")
+			}
+
+			argsBytes, err := os.ReadFile(argsPath)
+			if err != nil {
+				t.Fatalf("read args file: %v", err)
+			}
+			args := string(argsBytes)
+			if !strings.Contains(args, tt.want) {
+				t.Fatalf("expected %s in args, got:\n%s", tt.want, args)
+			}
+			if strings.Contains(args, tt.dontWant) {
+				t.Fatalf("unexpected %s in args, got:\n%s", tt.dontWant, args)
+			}
+			if !tt.force && !strings.Contains(args, archivePath) {
+				t.Fatalf("expected archive path %s in args, got:\n%s", archivePath, args)
+			}
+		})
+	}
+}
+
+func TestDoDownload_UsesRequestOutputDir(t *testing.T) {
+	setupUnfinishedFilePath(t)
+	setupArchiveFilePath(t)
+
+	m, p := runCollectorProgram(t)
+	dm := NewDownloadManager()
+
+	tmpDir := t.TempDir()
+	argsPath := filepath.Join(tmpDir, "args.txt")
+	ytdlp := makeExecutable(t, "fake-yt-dlp.sh", "#!/usr/bin/env bash\nprintf '%s\\n' \"$@\" > \""+argsPath+"\"\nexit 0\n")
+
+	cfg := config.GetDefault()
+	cfg.YTDLPPath = ytdlp
+	cfg.DefaultDownloadPath = filepath.Join(tmpDir, "flat")
+
+	outputDir := filepath.Join(tmpDir, "by-channel", "Band")
+	doDownload(dm, p, types.DownloadRequest{
+		URL:       "https://www.youtube.com/watch?v=abc",
+		FormatID:  "best",
+		OutputDir: outputDir,
+	}, cfg)
+
+	select {
+	case <-m.done:
+	case <-time.After(3 * time.Second):
+		t.Fatalf("timed out waiting for download result")
+	}
+
+	argsBytes, err := os.ReadFile(argsPath)
+	if err != nil {
+		t.Fatalf("read args file: %v", err)
+	}
+	args := string(argsBytes)
+	if !strings.Contains(args, filepath.Join(outputDir, cfg.VideoOutputTemplate)) {
+		t.Fatalf("expected output under %s, got:\n%s", outputDir, args)
+	}
+	if strings.Contains(args, cfg.DefaultDownloadPath) {
+		t.Fatalf("unexpected default download path in args:\n%s", args)
+	}
+}
+
+func TestStartDownload_PersistsOutputDirForResume(t *testing.T) {
+	setupUnfinishedFilePath(t)
+	setupDownloadConfigDir(t)
+
+	m, p := runCollectorProgram(t)
+	dm := NewDownloadManager()
+
+	cmd := StartDownload(dm, p, types.DownloadRequest{
+		URL:       "https://www.youtube.com/watch?v=abc123",
+		FormatID:  "best",
+		Title:     "Saved Title",
+		OutputDir: "/downloads/Band",
+	})
+	_ = cmd()
+	waitForDownloadResult(t, m)
+
+	entry := GetUnfinishedByURL("https://www.youtube.com/watch?v=abc123")
+	if entry == nil {
+		t.Fatalf("expected unfinished entry to exist")
+	}
+	if entry.Items[0].OutputDir != "/downloads/Band" {
+		t.Fatalf("entry.Items[0].OutputDir = %q, want /downloads/Band", entry.Items[0].OutputDir)
+	}
+}
+
+func TestDoDownload_RestartAppliesNewRateLimit(t *testing.T) {
+	setupUnfinishedFilePath(t)
+	setupArchiveFilePath(t)
+
+	m, p := runCollectorProgram(t)
+	dm := NewDownloadManager()
+
+	tmpDir := t.TempDir()
+	argsPath := filepath.Join(tmpDir, "args.txt")
+	marker := filepath.Join(tmpDir, "started")
+	ytdlp := makeExecutable(t, "fake-yt-dlp-restart.sh", "#!/usr/bin/env bash\necho \"$*\" >> \""+argsPath+"\"\nif [ -e \""+marker+"\" ]; then\n  echo \"[download] Destination: /tmp/fake.mp4\"\n  exit 0\nfi\ntouch \""+marker+"\"\nexec sleep 5\n")
+
+	cfg := config.GetDefault()
+	cfg.YTDLPPath = ytdlp
+	cfg.DefaultDownloadPath = tmpDir
+
+	done := make(chan struct{})
+	go func() {
+		doDownload(dm, p, types.DownloadRequest{
+			URL:      "https://www.youtube.com/watch?v=abc",
+			FormatID: "best",
+		}, cfg)
+		close(done)
+	}()
+
+	waitForManagerReady(t, dm)
+	deadline := time.Now().Add(2 * time.Second)
+	for dm.GetCmd() == nil && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := dm.RestartItem(0, "1M"); err != nil {
+		t.Fatalf("RestartItem() error = %v", err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("download routine did not return after restart")
+	}
+
+	select {
+	case <-m.done:
+	case <-time.After(3 * time.Second):
+		t.Fatalf("timed out waiting for download result")
+	}
+
+	_, results := m.snapshot()
+	if len(results) != 1 {
+		t.Fatalf("got %d DownloadResultMsg, want 1: %+v", len(results), results)
+	}
+	if results[0].Err != "" {
+		t.Fatalf("expected restarted download to succeed, got error: %q", results[0].Err)
+	}
+
+	argsBytes, err := os.ReadFile(argsPath)
+	if err != nil {
+		t.Fatalf("read args file: %v", err)
+	}
+	runs := strings.Split(strings.TrimSpace(string(argsBytes)), "\n")
+	if len(runs) != 2 {
+		t.Fatalf("yt-dlp ran %d times, want 2:\n%s", len(runs), argsBytes)
+	}
+	if strings.Contains(runs[0], "--limit-rate") {
+		t.Fatalf("first run unexpectedly limited: %s", runs[0])
+	}
+	if !strings.Contains(runs[1], "--limit-rate 1M") {
+		t.Fatalf("second run missing --limit-rate 1M: %s", runs[1])
+	}
+}
+
+func TestDoDownload_SponsorBlockArgs(t *testing.T) {
+	tests := []struct {
+		name       string
+		mode       string
+		categories []string
+		want       []string
+		dontWant   []string
+	}{
+		{name: "off", mode: "", dontWant: []string{"--sponsorblock-mark", "--sponsorblock-remove", "--sponsorblock-api"}},
+		{name: "mark highlight", mode: config.SponsorBlockMark, categories: []string{"sponsor", "poi_highlight"}, want: []string{"--sponsorblock-mark\nsponsor,poi_highlight\n"}},
+		{name: "remove drops mark-only categories", mode: config.SponsorBlockRemove, categories: []string{"sponsor", "poi_highlight", "chapter"}, want: []string{"--sponsorblock-remove\nsponsor\n"}, dontWant: []string{"poi_highlight", ",chapter"}},
+		{name: "remove only mark-only categories", mode: config.SponsorBlockRemove, categories: []string{"poi_highlight"}, dontWant: []string{"--sponsorblock-remove", "--sponsorblock-api"}},
+		{name: "mark as chapters", mode: config.SponsorBlockMark, want: []string{"--sponsorblock-mark\nsponsor,intro\n", "--sponsorblock-api\nhttp://127.0.0.1:8089\n"}, dontWant: []string{"--sponsorblock-remove"}},
+		{name: "remove segments", mode: config.SponsorBlockRemove, want: []string{"--sponsorblock-remove\nsponsor,intro\n"}, dontWant: []string{"--sponsorblock-mark"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupUnfinishedFilePath(t)
+			setupArchiveFilePath(t)
+
+			m, p := runCollectorProgram(t)
+			dm := NewDownloadManager()
+
+			tmpDir := t.TempDir()
+			argsPath := filepath.Join(tmpDir, "args.txt")
+			ytdlp := makeExecutable(t, "fake-yt-dlp.sh", "#!/usr/bin/env bash\nprintf '%s\\n' \"$@\" > \""+argsPath+"\"\nexit 0\n")
+
+			cfg := config.GetDefault()
+			cfg.YTDLPPath = ytdlp
+			cfg.DefaultDownloadPath = tmpDir
+			cfg.SponsorBlockCategories = []string{"sponsor", "intro"}
+			if tt.categories != nil {
+				cfg.SponsorBlockCategories = tt.categories
+			}
+			cfg.SponsorBlockAPI = "http://127.0.0.1:8089"
+
+			options := types.DownloadOptions()
+			for i := range options {
+				if options[i].ConfigField == "SponsorBlock" {
+					options[i].Mode = tt.mode
+					options[i].Enabled = tt.mode != ""
+				}
+			}
+
+			doDownload(dm, p, types.DownloadRequest{
+				URL:      "https://www.youtube.com/watch?v=abc",
+				FormatID: "best",
+				Options:  options,
+			}, cfg)
+
+			select {
+			case <-m.done:
+			case <-time.After(3 * time.Second):
+				t.Fatalf("timed out waiting for download result")
+			}
+
+			argsBytes, err := os.ReadFile(argsPath)
+			if err != nil {
+				t.Fatalf("read args file: %v", err)
+			}
+			args := string(argsBytes)
+			for _, want := range tt.want {
+				if !strings.Contains(args, want) {
+					t.Fatalf("expected %q in args, got:\n%s", want, args)
+				}
+			}
+			for _, dontWant := range tt.dontWant {
+				if strings.Contains(args, dontWant) {
+					t.Fatalf("unexpected %q in args, got:\n%s", dontWant, args)
+				}
+			}
+		})
+	}
+}
+
+func TestDoDownload_ClipArgs(t *testing.T) {
+	setupUnfinishedFilePath(t)
+	setupArchiveFilePath(t)
+
+	m, p := runCollectorProgram(t)
+	dm := NewDownloadManager()
+
+	tmpDir := t.TempDir()
+	argsPath := filepath.Join(tmpDir, "args.txt")
+	ytdlp := makeExecutable(t, "fake-yt-dlp.sh", "#!/usr/bin/env bash\nprintf '%s\\n' \"$@\" > \""+argsPath+"\"\nexit 0\n")
+
+	cfg := config.GetDefault()
+	cfg.YTDLPPath = ytdlp
+	cfg.DefaultDownloadPath = tmpDir
+	cfg.VideoOutputTemplate = "%(title)s.%(ext)s"
+
+	doDownload(dm, p, types.DownloadRequest{
+		URL:      "https://www.youtube.com/watch?v=abc",
+		FormatID: "best",
+		Clip:     types.ClipRange{Start: 3600, End: 3690},
+		Options:  types.DownloadOptions(),
+	}, cfg)
+
+	select {
+	case <-m.done:
+	case <-time.After(3 * time.Second):
+		t.Fatalf("timed out waiting for download result")
+	}
+
+	argsBytes, err := os.ReadFile(argsPath)
+	if err != nil {
+		t.Fatalf("read args file: %v", err)
+	}
+	args := string(argsBytes)
+	for _, want := range []string{"--download-sections\n*3600-3690\n", "%(title)s [clip 3600-3690].%(ext)s"} {
+		if !strings.Contains(args, want) {
+			t.Fatalf("expected %q in args, got:\n%s", want, args)
+		}
+	}
+	if strings.Contains(args, "--download-archive") {
+		t.Fatalf("clip downloads should not use the download archive, got:\n%s", args)
+	}
+}
+
+func TestDoDownload_AudioKeepsTemplateFilenames(t *testing.T) {
+	setupUnfinishedFilePath(t)
+	setupArchiveFilePath(t)
+
+	m, p := runCollectorProgram(t)
+	dm := NewDownloadManager()
+
+	tmpDir := t.TempDir()
+	argsPath := filepath.Join(tmpDir, "args.txt")
+	ytdlp := makeExecutable(t, "fake-yt-dlp.sh", "#!/usr/bin/env bash\nprintf '%s\\n' \"$@\" > \""+argsPath+"\"\nexit 0\n")
+
+	cfg := config.GetDefault()
+	cfg.YTDLPPath = ytdlp
+	cfg.DefaultDownloadPath = tmpDir
+
+	doDownload(dm, p, types.DownloadRequest{
+		URL:        "https://www.youtube.com/watch?v=abc",
+		FormatID:   "140",
+		IsAudioTab: true,
+	}, cfg)
+
+	select {
+	case <-m.done:
+	case <-time.After(3 * time.Second):
+		t.Fatalf("timed out waiting for download result")
+	}
+
+	argsBytes, err := os.ReadFile(argsPath)
+	if err != nil {
+		t.Fatalf("read args file: %v", err)
+	}
+	args := string(argsBytes)
+	if !strings.Contains(args, cfg.AudioOutputTemplate) {
+		t.Fatalf("expected %q in args, got:\n%s", cfg.AudioOutputTemplate, args)
+	}
+	// The format screen previews the unrestricted name.
+	if strings.Contains(args, "--restrict-filenames") {
+		t.Fatalf("audio downloads should keep the names the template gives, got:\n%s", args)
+	}
+}
+
+func TestLiveArgs(t *testing.T) {
+	fromStart := []types.DownloadOption{{ConfigField: "LiveFromStart", Enabled: true}}
+
+	tests := []struct {
+		name    string
+		video   types.VideoItem
+		options []types.DownloadOption
+		want    []string
+	}{
+		{
+			name:    "regular video",
+			video:   types.VideoItem{ID: "abc"},
+			options: fromStart,
+			want:    nil,
+		},
+		{
+			name:    "live stream from now",
+			video:   types.VideoItem{ID: "abc", LiveStatus: types.LiveStatusLive},
+			options: types.DownloadOptions(),
+			want:    nil,
+		},
+		{
+			name:    "live stream from start",
+			video:   types.VideoItem{ID: "abc", LiveStatus: types.LiveStatusLive},
+			options: fromStart,
+			want:    []string{"--live-from-start"},
+		},
+		{
+			name:    "upcoming stream",
+			video:   types.VideoItem{ID: "abc", LiveStatus: types.LiveStatusUpcoming},
+			options: types.DownloadOptions(),
+			want:    []string{"--wait-for-video", "60"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := liveArgs(tt.video, tt.options)
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("liveArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDoDownload_SubtitleArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		subs     types.SubtitleRequest
+		want     []string
+		dontWant []string
+	}{
+		{
+			name:     "embed selected languages",
+			subs:     types.SubtitleRequest{Languages: []string{"en", "de"}, Format: types.SubtitleFormatSRT},
+			want:     []string{"-f\nbest\n", "--sub-langs\nen,de\n", "--write-subs\n", "--embed-subs\n", "--download-archive"},
+			dontWant: []string{"--skip-download", "--write-auto-subs"},
+		},
+		{
+			name:     "embed uploaded and automatic languages",
+			subs:     types.SubtitleRequest{Languages: []string{"en"}, AutoLanguages: []string{"de", "en"}, Format: types.SubtitleFormatSRT},
+			want:     []string{"--sub-langs\nen,de\n", "--write-subs\n", "--write-auto-subs\n", "--embed-subs\n"},
+			dontWant: []string{"--skip-download"},
+		},
+		{
+			name:     "subtitles only",
+			subs:     types.SubtitleRequest{AutoLanguages: []string{"en"}, Format: types.SubtitleFormatVTT, Only: true},
+			want:     []string{"--sub-langs\nen\n", "--write-auto-subs\n", "--skip-download\n", "--sub-format\nvtt/best\n", "--convert-subs\nvtt\n"},
+			dontWant: []string{"-f\n", "--write-subs", "--embed-subs", "--download-archive"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupUnfinishedFilePath(t)
+			setupArchiveFilePath(t)
+
+			m, p := runCollectorProgram(t)
+			dm := NewDownloadManager()
+
+			tmpDir := t.TempDir()
+			argsPath := filepath.Join(tmpDir, "args.txt")
+			ytdlp := makeExecutable(t, "fake-yt-dlp.sh", "#!/usr/bin/env bash\nprintf '%s\\n' \"$@\" > \""+argsPath+"\"\nexit 0\n")
+
+			cfg := config.GetDefault()
+			cfg.YTDLPPath = ytdlp
+			cfg.DefaultDownloadPath = tmpDir
+
+			doDownload(dm, p, types.DownloadRequest{
+				URL:       "https://www.youtube.com/watch?v=abc",
+				FormatID:  "best",
+				Subtitles: tt.subs,
+				Options:   types.DownloadOptions(),
+			}, cfg)
+
+			select {
+			case <-m.done:
+			case <-time.After(3 * time.Second):
+				t.Fatalf("timed out waiting for download result")
+			}
+
+			argsBytes, err := os.ReadFile(argsPath)
+			if err != nil {
+				t.Fatalf("read args file: %v", err)
+			}
+			args := string(argsBytes)
+			for _, want := range tt.want {
+				if !strings.Contains(args, want) {
+					t.Fatalf("expected %q in args, got:\n%s", want, args)
+				}
+			}
+			for _, dontWant := range tt.dontWant {
+				if strings.Contains(args, dontWant) {
+					t.Fatalf("unexpected %q in args, got:\n%s", dontWant, args)
+				}
+			}
+		})
+	}
+}
+
+func TestDoDownload_SubtitlesOnlyReportsConvertedFile(t *testing.T) {
+	setupUnfinishedFilePath(t)
+	setupArchiveFilePath(t)
+
+	m, p := runCollectorProgram(t)
+	dm := NewDownloadManager()
+
+	tmpDir := t.TempDir()
+	written := filepath.Join(tmpDir, "Video.en.vtt")
+	ytdlp := makeExecutable(t, "fake-yt-dlp.sh", "#!/usr/bin/env bash\necho '[info] Writing video subtitles to: "+written+"'\necho '[SubtitlesConvertor] Converting subtitles'\nexit 0\n")
+
+	cfg := config.GetDefault()
+	cfg.YTDLPPath = ytdlp
+	cfg.DefaultDownloadPath = tmpDir
+
+	doDownload(dm, p, types.DownloadRequest{
+		URL:       "https://www.youtube.com/watch?v=abc",
+		Subtitles: types.SubtitleRequest{Languages: []string{"en"}, Format: types.SubtitleFormatSRT, Only: true},
+	}, cfg)
+
+	select {
+	case <-m.done:
+	case <-time.After(3 * time.Second):
+		t.Fatalf("timed out waiting for download result")
+	}
+
+	_, results := m.snapshot()
+	if len(results) != 1 || results[0].Err != "" {
+		t.Fatalf("results = %+v, want one successful result", results)
+	}
+
+	want := filepath.Join(tmpDir, "Video.en.srt")
+	if results[0].Destination != want {
+		t.Fatalf("Destination = %q, want %q", results[0].Destination, want)
+	}
+}
+
+func TestDoDownload_NoSpaceLeftOnDevice(t *testing.T) {
+	setupUnfinishedFilePath(t)
+	setupArchiveFilePath(t)
+
+	m, p := runCollectorProgram(t)
+	dm := NewDownloadManager()
+
+	ytdlp := makeExecutable(t, "fake-yt-dlp.sh", "#!/usr/bin/env bash\necho 'ERROR: unable to write data: [Errno 28] No space left on device' >&2\nexit 1\n")
+
+	cfg := config.GetDefault()
+	cfg.YTDLPPath = ytdlp
+	cfg.DefaultDownloadPath = t.TempDir()
+
+	doDownload(dm, p, types.DownloadRequest{URL: "https://www.youtube.com/watch?v=abc", QueueIndex: 2, QueueTotal: 3}, cfg)
+
+	select {
+	case <-m.done:
+	case <-time.After(3 * time.Second):
+		t.Fatalf("timed out waiting for download result")
+	}
+
+	_, results := m.snapshot()
+	if len(results) != 1 || !results[0].NoSpace {
+		t.Fatalf("results = %+v, want one NoSpace result", results)
+	}
+
+	if !strings.Contains(results[0].Err, "no space left on device") {
+		t.Fatalf("Err = %q, want it to mention the full disk", results[0].Err)
+	}
 }
@@ -4,84 +4,248 @@ import (
 	"context"
 	"log"
 	"os/exec"
+	"sort"
 	"sync"
 )
 
+type downloadSlot struct {
+	cmd         *exec.Cmd
+	ctx         context.Context
+	cancel      context.CancelFunc
+	isPaused    bool
+	restart     bool
+	restartRate string
+}
+
+// DownloadManager tracks the running yt-dlp processes keyed by queue index.
+// Single (non-queue) downloads use index 0.
 type DownloadManager struct {
-	cmd      *exec.Cmd
-	ctx      context.Context
-	cancel   context.CancelFunc
-	mutex    sync.Mutex
-	isPaused bool
+	slots map[int]*downloadSlot
+	mutex sync.Mutex
 }
 
 func NewDownloadManager() *DownloadManager {
-	return &DownloadManager{}
+	return &DownloadManager{
+		slots: make(map[int]*downloadSlot),
+	}
 }
 
-func (dm *DownloadManager) SetCmd(cmd *exec.Cmd) {
+func (dm *DownloadManager) slot(index int) *downloadSlot {
+	if dm.slots == nil {
+		dm.slots = make(map[int]*downloadSlot)
+	}
+
+	s, ok := dm.slots[index]
+	if !ok {
+		s = &downloadSlot{}
+		dm.slots[index] = s
+	}
+
+	return s
+}
+
+func (dm *DownloadManager) SetItemCmd(index int, cmd *exec.Cmd) {
 	dm.mutex.Lock()
 	defer dm.mutex.Unlock()
 
-	dm.cmd = cmd
+	dm.slot(index).cmd = cmd
 }
 
-func (dm *DownloadManager) GetCmd() *exec.Cmd {
+func (dm *DownloadManager) GetItemCmd(index int) *exec.Cmd {
 	dm.mutex.Lock()
 	defer dm.mutex.Unlock()
 
-	return dm.cmd
+	if s, ok := dm.slots[index]; ok {
+		return s.cmd
+	}
+
+	return nil
 }
 
-func (dm *DownloadManager) SetContext(ctx context.Context, cancel context.CancelFunc) {
+func (dm *DownloadManager) SetItemContext(index int, ctx context.Context, cancel context.CancelFunc) {
 	dm.mutex.Lock()
 	defer dm.mutex.Unlock()
 
-	dm.ctx = ctx
-	dm.cancel = cancel
+	s := dm.slot(index)
+	s.ctx = ctx
+	s.cancel = cancel
 }
 
-func (dm *DownloadManager) GetContext() (context.Context, context.CancelFunc) {
+func (dm *DownloadManager) GetItemContext(index int) (context.Context, context.CancelFunc) {
+	dm.mutex.Lock()
+	defer dm.mutex.Unlock()
+
+	if s, ok := dm.slots[index]; ok {
+		return s.ctx, s.cancel
+	}
+
+	return nil, nil
+}
+
+func (dm *DownloadManager) SetItemPaused(index int, paused bool) {
+	dm.mutex.Lock()
+	defer dm.mutex.Unlock()
+
+	if s, ok := dm.slots[index]; ok {
+		s.isPaused = paused
+	}
+}
+
+func (dm *DownloadManager) IsItemPaused(index int) bool {
+	dm.mutex.Lock()
+	defer dm.mutex.Unlock()
+
+	if s, ok := dm.slots[index]; ok {
+		return s.isPaused
+	}
+
+	return false
+}
+
+// ClearItem forgets the slot at index, but only while it still belongs to
+// ctx so a finished process can't wipe out a retry of the same item.
+func (dm *DownloadManager) ClearItem(index int, ctx context.Context) {
+	dm.mutex.Lock()
+	defer dm.mutex.Unlock()
+
+	s, ok := dm.slots[index]
+	if !ok {
+		return
+	}
+
+	if ctx != nil && s.ctx != nil && s.ctx != ctx {
+		return
+	}
+
+	delete(dm.slots, index)
+}
+
+func (dm *DownloadManager) CancelItem(index int) error {
+	dm.mutex.Lock()
+	defer dm.mutex.Unlock()
+
+	s, ok := dm.slots[index]
+	if !ok {
+		return nil
+	}
+
+	return s.kill()
+}
+
+// RestartItem stops the process at index so doDownload starts it again with
+// rateLimit. yt-dlp picks up the existing .part file on the next run.
+func (dm *DownloadManager) RestartItem(index int, rateLimit string) error {
 	dm.mutex.Lock()
 	defer dm.mutex.Unlock()
 
-	return dm.ctx, dm.cancel
+	s, ok := dm.slots[index]
+	if !ok || s.cmd == nil {
+		return nil
+	}
+
+	s.restart = true
+	s.restartRate = rateLimit
+	return s.kill()
+}
+
+func (dm *DownloadManager) takeRestart(index int, ctx context.Context) (string, bool) {
+	dm.mutex.Lock()
+	defer dm.mutex.Unlock()
+
+	s, ok := dm.slots[index]
+	if !ok || s.ctx != ctx || !s.restart {
+		return "", false
+	}
+
+	s.restart = false
+	return s.restartRate, true
+}
+
+func (dm *DownloadManager) ActiveIndexes() []int {
+	dm.mutex.Lock()
+	defer dm.mutex.Unlock()
+
+	indexes := make([]int, 0, len(dm.slots))
+	for index, s := range dm.slots {
+		if s.cmd != nil {
+			indexes = append(indexes, index)
+		}
+	}
+
+	sort.Ints(indexes)
+	return indexes
+}
+
+func (dm *DownloadManager) ActiveCount() int {
+	return len(dm.ActiveIndexes())
+}
+
+func (dm *DownloadManager) SetCmd(cmd *exec.Cmd) {
+	dm.SetItemCmd(0, cmd)
+}
+
+func (dm *DownloadManager) GetCmd() *exec.Cmd {
+	return dm.GetItemCmd(0)
+}
+
+func (dm *DownloadManager) SetContext(ctx context.Context, cancel context.CancelFunc) {
+	dm.SetItemContext(0, ctx, cancel)
+}
+
+func (dm *DownloadManager) GetContext() (context.Context, context.CancelFunc) {
+	return dm.GetItemContext(0)
 }
 
 func (dm *DownloadManager) SetPaused(paused bool) {
 	dm.mutex.Lock()
 	defer dm.mutex.Unlock()
 
-	dm.isPaused = paused
+	for _, s := range dm.slots {
+		s.isPaused = paused
+	}
 }
 
 func (dm *DownloadManager) IsPaused() bool {
 	dm.mutex.Lock()
 	defer dm.mutex.Unlock()
 
-	return dm.isPaused
+	for _, s := range dm.slots {
+		if s.isPaused {
+			return true
+		}
+	}
+
+	return false
 }
 
 func (dm *DownloadManager) Clear() {
 	dm.mutex.Lock()
 	defer dm.mutex.Unlock()
 
-	dm.cmd = nil
-	dm.ctx = nil
-	dm.cancel = nil
-	dm.isPaused = false
+	dm.slots = make(map[int]*downloadSlot)
 }
 
 func (dm *DownloadManager) Cancel() error {
 	dm.mutex.Lock()
 	defer dm.mutex.Unlock()
 
-	if dm.cancel != nil {
-		dm.cancel()
+	var firstErr error
+	for _, s := range dm.slots {
+		if err := s.kill(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+func (s *downloadSlot) kill() error {
+	if s.cancel != nil {
+		s.cancel()
 	}
 
-	if dm.cmd != nil && dm.cmd.Process != nil {
-		if err := dm.cmd.Process.Kill(); err != nil {
+	if s.cmd != nil && s.cmd.Process != nil {
+		if err := s.cmd.Process.Kill(); err != nil {
 			log.Printf("Failed to kill download process: %v", err)
 			return err
 		}
@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDownloadManagerTracksItemsIndependently(t *testing.T) {
+	dm := NewDownloadManager()
+
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	defer cancel1()
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	defer cancel2()
+
+	dm.SetItemContext(1, ctx1, cancel1)
+	dm.SetItemContext(2, ctx2, cancel2)
+
+	if err := dm.CancelItem(1); err != nil {
+		t.Fatalf("CancelItem(1) error = %v", err)
+	}
+	if ctx1.Err() == nil {
+		t.Fatalf("item 1 context not cancelled")
+	}
+	if ctx2.Err() != nil {
+		t.Fatalf("item 2 context cancelled, want running")
+	}
+
+	if err := dm.Cancel(); err != nil {
+		t.Fatalf("Cancel() error = %v", err)
+	}
+	if ctx2.Err() == nil {
+		t.Fatalf("Cancel() did not cancel item 2")
+	}
+}
+
+func TestDownloadManagerClearItemIgnoresStaleContext(t *testing.T) {
+	dm := NewDownloadManager()
+
+	stale, cancelStale := context.WithCancel(context.Background())
+	defer cancelStale()
+	current, cancelCurrent := context.WithCancel(context.Background())
+	defer cancelCurrent()
+
+	dm.SetItemContext(3, current, cancelCurrent)
+	dm.ClearItem(3, stale)
+
+	if ctx, _ := dm.GetItemContext(3); ctx != current {
+		t.Fatalf("ClearItem with stale context removed the current slot")
+	}
+
+	dm.ClearItem(3, current)
+	if ctx, _ := dm.GetItemContext(3); ctx != nil {
+		t.Fatalf("ClearItem with current context left the slot behind")
+	}
+}
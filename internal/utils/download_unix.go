@@ -13,34 +13,91 @@ import (
 
 func PauseDownload(dm *DownloadManager) tea.Cmd {
 	return tea.Cmd(func() tea.Msg {
-		cmd := dm.GetCmd()
-		if cmd == nil || cmd.Process == nil || dm.IsPaused() {
-			return nil
+		paused := false
+		for _, index := range dm.ActiveIndexes() {
+			if signalDownloadItem(dm, index, syscall.SIGSTOP, true) {
+				paused = true
+			}
 		}
 
-		if err := cmd.Process.Signal(syscall.SIGSTOP); err != nil {
-			log.Printf("Failed to pause download: %v", err)
+		if !paused {
 			return nil
 		}
 
-		dm.SetPaused(true)
 		return types.PauseDownloadMsg{}
 	})
 }
 
 func ResumeDownload(dm *DownloadManager) tea.Cmd {
 	return tea.Cmd(func() tea.Msg {
-		cmd := dm.GetCmd()
-		if cmd == nil || cmd.Process == nil || !dm.IsPaused() {
-			return nil
+		resumed := false
+		for _, index := range dm.ActiveIndexes() {
+			if signalDownloadItem(dm, index, syscall.SIGCONT, false) {
+				resumed = true
+			}
 		}
 
-		if err := cmd.Process.Signal(syscall.SIGCONT); err != nil {
-			log.Printf("Failed to resume download: %v", err)
+		if !resumed {
 			return nil
 		}
 
-		dm.SetPaused(false)
 		return types.ResumeDownloadMsg{}
 	})
 }
+
+func PauseDownloadItem(dm *DownloadManager, index int) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		if !signalDownloadItem(dm, index, syscall.SIGSTOP, true) {
+			return nil
+		}
+
+		return types.PauseDownloadMsg{QueueIndex: index}
+	})
+}
+
+func ResumeDownloadItem(dm *DownloadManager, index int) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		if !signalDownloadItem(dm, index, syscall.SIGCONT, false) {
+			return nil
+		}
+
+		return types.ResumeDownloadMsg{QueueIndex: index}
+	})
+}
+
+func signalDownloadItem(dm *DownloadManager, index int, sig syscall.Signal, pause bool) bool {
+	cmd := dm.GetItemCmd(index)
+	if cmd == nil || cmd.Process == nil || dm.IsItemPaused(index) == pause {
+		return false
+	}
+
+	if err := cmd.Process.Signal(sig); err != nil {
+		log.Printf("Failed to signal download %d: %v", index, err)
+		return false
+	}
+
+	dm.SetItemPaused(index, pause)
+	return true
+}
+
+// StopRecording interrupts yt-dlp so it finishes a live recording with what
+// it has so far, where cancelling would leave a partial file.
+func StopRecording(dm *DownloadManager, index int) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		cmd := dm.GetItemCmd(index)
+		if cmd == nil || cmd.Process == nil {
+			return nil
+		}
+
+		if dm.IsItemPaused(index) {
+			signalDownloadItem(dm, index, syscall.SIGCONT, false)
+		}
+
+		if err := cmd.Process.Signal(syscall.SIGINT); err != nil {
+			log.Printf("Failed to stop recording %d: %v", index, err)
+			return nil
+		}
+
+		return types.StopRecordingMsg{QueueIndex: index}
+	})
+}
@@ -10,8 +10,7 @@ import (
 
 func PauseDownload(dm *DownloadManager) tea.Cmd {
 	return tea.Cmd(func() tea.Msg {
-		cmd := dm.GetCmd()
-		if cmd != nil && cmd.Process != nil && !dm.IsPaused() {
+		if dm.ActiveCount() > 0 && !dm.IsPaused() {
 			log.Print("pause not supported on windows")
 		}
 
@@ -21,11 +20,40 @@ func PauseDownload(dm *DownloadManager) tea.Cmd {
 
 func ResumeDownload(dm *DownloadManager) tea.Cmd {
 	return tea.Cmd(func() tea.Msg {
-		cmd := dm.GetCmd()
-		if cmd != nil && cmd.Process != nil && dm.IsPaused() {
+		if dm.ActiveCount() > 0 && dm.IsPaused() {
 			log.Print("resume not supported on windows")
 		}
 
 		return nil
 	})
 }
+
+func PauseDownloadItem(dm *DownloadManager, index int) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		if dm.GetItemCmd(index) != nil {
+			log.Print("pause not supported on windows")
+		}
+
+		return nil
+	})
+}
+
+func ResumeDownloadItem(dm *DownloadManager, index int) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		if dm.GetItemCmd(index) != nil {
+			log.Print("resume not supported on windows")
+		}
+
+		return nil
+	})
+}
+
+func StopRecording(dm *DownloadManager, index int) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		if dm.GetItemCmd(index) != nil {
+			log.Print("stopping a recording is not supported on windows")
+		}
+
+		return nil
+	})
+}
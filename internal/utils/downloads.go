@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/xdagiz/xytz/internal/paths"
+	"github.com/xdagiz/xytz/internal/store"
+)
+
+var ErrInvalidCompletedDownload = errors.New("completed download must have a video ID or destination")
+
+const DownloadsFileName = "downloads.json"
+
+type CompletedDownload struct {
+	VideoID     string    `json:"video_id"`
+	Title       string    `json:"title"`
+	Channel     string    `json:"channel,omitempty"`
+	FormatID    string    `json:"format_id"`
+	IsAudio     bool      `json:"is_audio,omitempty"`
+	Destination string    `json:"destination"`
+	Size        int64     `json:"size"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// key identifies the file a download produced, falling back to the video ID
+// when yt-dlp didn't report a destination.
+func (d CompletedDownload) key() string {
+	if d.Destination != "" {
+		return d.Destination
+	}
+
+	return "id:" + d.VideoID
+}
+
+var GetDownloadsFilePath = func() string {
+	dataDir := paths.GetDataDir()
+	if err := paths.EnsureDirExists(dataDir); err != nil {
+		log.Printf("Warning: Could not create data directory: %v", err)
+		return DownloadsFileName
+	}
+
+	return filepath.Join(dataDir, DownloadsFileName)
+}
+
+func LoadDownloads() ([]CompletedDownload, error) {
+	var downloads []CompletedDownload
+	if err := store.LoadJSON(GetDownloadsFilePath(), &downloads); err != nil {
+		return nil, err
+	}
+
+	if downloads == nil {
+		return []CompletedDownload{}, nil
+	}
+
+	return downloads, nil
+}
+
+// updateDownloads applies fn to the completed downloads under the file lock.
+func updateDownloads(fn func([]CompletedDownload) []CompletedDownload) error {
+	var downloads []CompletedDownload
+	return store.UpdateJSON(GetDownloadsFilePath(), &downloads, func() error {
+		downloads = fn(downloads)
+		if downloads == nil {
+			downloads = []CompletedDownload{}
+		}
+
+		return nil
+	})
+}
+
+// AddDownload records a completed download. Downloading to the same file
+// again replaces the earlier entry.
+func AddDownload(download CompletedDownload) error {
+	if download.VideoID == "" && download.Destination == "" {
+		return ErrInvalidCompletedDownload
+	}
+
+	if download.Size == 0 && download.Destination != "" {
+		if info, err := os.Stat(download.Destination); err == nil {
+			download.Size = info.Size()
+		}
+	}
+
+	if download.Timestamp.IsZero() {
+		download.Timestamp = time.Now()
+	}
+
+	return updateDownloads(func(downloads []CompletedDownload) []CompletedDownload {
+		newDownloads := make([]CompletedDownload, 0, len(downloads)+1)
+		for _, d := range downloads {
+			if d.key() != download.key() {
+				newDownloads = append(newDownloads, d)
+			}
+		}
+
+		return append(newDownloads, download)
+	})
+}
+
+func RemoveDownload(download CompletedDownload) error {
+	return updateDownloads(func(downloads []CompletedDownload) []CompletedDownload {
+		var newDownloads []CompletedDownload
+		for _, d := range downloads {
+			if d.key() != download.key() {
+				newDownloads = append(newDownloads, d)
+			}
+		}
+
+		return newDownloads
+	})
+}
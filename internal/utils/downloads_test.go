@@ -0,0 +1,110 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func setupDownloadsFilePath(t *testing.T) string {
+	t.Helper()
+
+	original := GetDownloadsFilePath
+	path := filepath.Join(t.TempDir(), "downloads.json")
+	GetDownloadsFilePath = func() string {
+		return path
+	}
+	t.Cleanup(func() { GetDownloadsFilePath = original })
+
+	return path
+}
+
+func TestAddDownloadRejectsEmptyEntry(t *testing.T) {
+	setupDownloadsFilePath(t)
+
+	if err := AddDownload(CompletedDownload{Title: "No ID"}); err != ErrInvalidCompletedDownload {
+		t.Fatalf("AddDownload() error = %v, want ErrInvalidCompletedDownload", err)
+	}
+}
+
+func TestAddDownloadRecordsSizeAndTimestamp(t *testing.T) {
+	setupDownloadsFilePath(t)
+
+	file := filepath.Join(t.TempDir(), "video.mp4")
+	if err := os.WriteFile(file, []byte("12345"), 0o644); err != nil {
+		t.Fatalf("WriteFile error: %v", err)
+	}
+
+	if err := AddDownload(CompletedDownload{VideoID: "abc", Title: "Video", FormatID: "18", Destination: file}); err != nil {
+		t.Fatalf("AddDownload() error: %v", err)
+	}
+
+	downloads, err := LoadDownloads()
+	if err != nil {
+		t.Fatalf("LoadDownloads() error: %v", err)
+	}
+	if len(downloads) != 1 {
+		t.Fatalf("len(downloads) = %d, want 1", len(downloads))
+	}
+
+	got := downloads[0]
+	if got.Size != 5 {
+		t.Fatalf("Size = %d, want 5", got.Size)
+	}
+	if got.Timestamp.IsZero() {
+		t.Fatalf("expected timestamp to be set")
+	}
+	if got.VideoID != "abc" || got.FormatID != "18" || got.Destination != file {
+		t.Fatalf("unexpected entry: %+v", got)
+	}
+}
+
+func TestAddDownloadReplacesSameDestination(t *testing.T) {
+	setupDownloadsFilePath(t)
+
+	first := CompletedDownload{VideoID: "abc", Title: "Old", Destination: "/tmp/a.mp4", Timestamp: time.Now().Add(-time.Hour)}
+	other := CompletedDownload{VideoID: "def", Title: "Other", Destination: "/tmp/b.mp4"}
+	again := CompletedDownload{VideoID: "abc", Title: "New", Destination: "/tmp/a.mp4"}
+
+	for _, d := range []CompletedDownload{first, other, again} {
+		if err := AddDownload(d); err != nil {
+			t.Fatalf("AddDownload() error: %v", err)
+		}
+	}
+
+	downloads, err := LoadDownloads()
+	if err != nil {
+		t.Fatalf("LoadDownloads() error: %v", err)
+	}
+	if len(downloads) != 2 {
+		t.Fatalf("len(downloads) = %d, want 2", len(downloads))
+	}
+	if downloads[1].Title != "New" {
+		t.Fatalf("latest entry title = %q, want New", downloads[1].Title)
+	}
+}
+
+func TestRemoveDownload(t *testing.T) {
+	setupDownloadsFilePath(t)
+
+	keep := CompletedDownload{VideoID: "keep", Destination: "/tmp/keep.mp4"}
+	drop := CompletedDownload{VideoID: "drop", Destination: "/tmp/drop.mp4"}
+	for _, d := range []CompletedDownload{keep, drop} {
+		if err := AddDownload(d); err != nil {
+			t.Fatalf("AddDownload() error: %v", err)
+		}
+	}
+
+	if err := RemoveDownload(drop); err != nil {
+		t.Fatalf("RemoveDownload() error: %v", err)
+	}
+
+	downloads, err := LoadDownloads()
+	if err != nil {
+		t.Fatalf("LoadDownloads() error: %v", err)
+	}
+	if len(downloads) != 1 || downloads[0].VideoID != "keep" {
+		t.Fatalf("downloads = %+v, want only keep", downloads)
+	}
+}
@@ -0,0 +1,224 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/xdagiz/xytz/internal/types"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// feedWorkers is how many channels the feed lists at once.
+const feedWorkers = 4
+
+// approximateDateArgs makes yt-dlp list when channel uploads were
+// published, which flat listings otherwise leave out.
+var approximateDateArgs = []string{"--extractor-args", "youtubetab:approximate_date"}
+
+// FeedManager cancels the channel listings of a running feed fetch, each of
+// which runs under its own SearchManager.
+type FeedManager struct {
+	mutex    sync.Mutex
+	managers []*SearchManager
+	canceled bool
+}
+
+func NewFeedManager() *FeedManager {
+	return &FeedManager{}
+}
+
+func (fm *FeedManager) start(workers int) []*SearchManager {
+	fm.mutex.Lock()
+	defer fm.mutex.Unlock()
+
+	fm.canceled = false
+	fm.managers = make([]*SearchManager, workers)
+	for i := range fm.managers {
+		fm.managers[i] = NewSearchManager()
+	}
+
+	return slices.Clone(fm.managers)
+}
+
+func (fm *FeedManager) WasCanceled() bool {
+	fm.mutex.Lock()
+	defer fm.mutex.Unlock()
+
+	return fm.canceled
+}
+
+func (fm *FeedManager) Cancel() error {
+	fm.mutex.Lock()
+	defer fm.mutex.Unlock()
+
+	fm.canceled = true
+
+	var errs []error
+	for _, sm := range fm.managers {
+		errs = append(errs, sm.Cancel())
+	}
+
+	return errors.Join(errs...)
+}
+
+type channelUploads struct {
+	videos   []types.VideoItem
+	err      string
+	canceled bool
+}
+
+// FetchFeed lists the latest perChannel uploads of every subscription,
+// feedWorkers channels at a time, and merges them newest first. Uploads
+// newer than a channel's last check are marked new, and the check time of
+// every channel listed is updated.
+func FetchFeed(fm *FeedManager, subs []Subscription, perChannel int, cookiesBrowser, cookiesFile string) tea.Cmd {
+	// The managers are reset before the command runs, so a cancel that comes
+	// in before the fetch gets going isn't lost.
+	managers := fm.start(min(feedWorkers, len(subs)))
+
+	return tea.Cmd(func() tea.Msg {
+		if len(subs) == 0 {
+			return types.FeedResultMsg{Err: "No subscriptions yet. Subscribe to a channel with /subscribe @username"}
+		}
+
+		checked := time.Now()
+		results := make([]channelUploads, len(subs))
+		jobs := make(chan int)
+
+		var wg sync.WaitGroup
+		for _, sm := range managers {
+			wg.Go(func() {
+				for i := range jobs {
+					if fm.WasCanceled() {
+						results[i] = channelUploads{canceled: true}
+						continue
+					}
+
+					results[i] = fetchChannelUploads(sm, subs[i], perChannel, cookiesBrowser, cookiesFile)
+				}
+			})
+		}
+
+		for i := range subs {
+			if fm.WasCanceled() {
+				break
+			}
+			jobs <- i
+		}
+		close(jobs)
+		wg.Wait()
+
+		if fm.WasCanceled() {
+			return nil
+		}
+
+		var videos []types.VideoItem
+		var listed, failed []string
+		var newCount int
+		for i, result := range results {
+			sub := subs[i]
+			if result.canceled {
+				return nil
+			}
+
+			if result.err != "" {
+				failed = append(failed, fmt.Sprintf("@%s: %s", sub.Channel, result.err))
+				continue
+			}
+
+			listed = append(listed, sub.Channel)
+			for _, video := range result.videos {
+				if video.Channel == "" {
+					video.Channel = sub.Channel
+				}
+
+				if !video.Published.IsZero() {
+					video.IsNew = video.Published.After(sub.Since())
+					video.Desc = formatAge(video.Published, checked) + " • " + video.Desc
+				}
+
+				if video.IsNew {
+					newCount++
+				}
+
+				videos = append(videos, video)
+			}
+		}
+
+		if err := MarkSubscriptionsChecked(listed, checked); err != nil {
+			log.Printf("Failed to update subscriptions: %v", err)
+		}
+
+		if len(listed) == 0 {
+			return types.FeedResultMsg{Channels: len(subs), Failed: failed, Err: "Failed to load the feed: " + failed[0]}
+		}
+
+		slices.SortStableFunc(videos, func(a, b types.VideoItem) int {
+			return b.Published.Compare(a.Published)
+		})
+
+		items := make([]list.Item, len(videos))
+		for i, video := range videos {
+			items[i] = video
+		}
+
+		return types.FeedResultMsg{Videos: items, Channels: len(subs), New: newCount, Failed: failed}
+	})
+}
+
+func fetchChannelUploads(sm *SearchManager, sub Subscription, limit int, cookiesBrowser, cookiesFile string) channelUploads {
+	result := executeYTDLP(sm, BuildChannelURL(sub.Channel), 1, limit, cookiesBrowser, cookiesFile, parseVideoEntry, approximateDateArgs...)
+	if result == nil {
+		return channelUploads{canceled: true}
+	}
+
+	msg, ok := result.(types.SearchResultMsg)
+	if !ok {
+		return channelUploads{err: "unexpected result"}
+	}
+
+	if msg.Err != "" {
+		return channelUploads{err: msg.Err}
+	}
+
+	var videos []types.VideoItem
+	for _, item := range msg.Videos {
+		if video, ok := item.(types.VideoItem); ok {
+			videos = append(videos, video)
+		}
+	}
+
+	return channelUploads{videos: videos}
+}
+
+// CancelFeed stops a running feed fetch.
+func CancelFeed(fm *FeedManager) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		if err := fm.Cancel(); err != nil {
+			log.Printf("Failed to cancel feed: %v", err)
+		}
+
+		return types.CancelSearchMsg{}
+	})
+}
+
+// formatAge describes how long ago t was, e.g. "3h ago".
+func formatAge(t, now time.Time) string {
+	d := now.Sub(t)
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", max(int(d.Minutes()), 1))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	case d < 30*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+
+	return t.Format("Jan 2, 2006")
+}
@@ -0,0 +1,218 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xdagiz/xytz/internal/config"
+	"github.com/xdagiz/xytz/internal/types"
+)
+
+// setupFakeFeed points the config at a fake yt-dlp that lists a couple of
+// timestamped uploads for @fresh and @old, fails for @missing and lists one
+// undated upload for any other channel. Every listing takes a moment and
+// records how many listings were running at once.
+func setupFakeFeed(t *testing.T, now time.Time) (argsPath, countsPath string) {
+	t.Helper()
+
+	setupSubscriptionsFilePath(t)
+
+	origConfigDir := config.GetConfigDir
+	configDir := filepath.Join(t.TempDir(), "config")
+	config.GetConfigDir = func() string { return configDir }
+	t.Cleanup(func() { config.GetConfigDir = origConfigDir })
+
+	dir := t.TempDir()
+	argsPath = filepath.Join(dir, "args.txt")
+	countsPath = filepath.Join(dir, "counts.txt")
+	runDir := filepath.Join(dir, "running")
+	if err := os.Mkdir(runDir, 0o755); err != nil {
+		t.Fatalf("create running dir: %v", err)
+	}
+
+	ts := func(ago time.Duration) string { return strconv.FormatInt(now.Add(-ago).Unix(), 10) }
+	script := `#!/usr/bin/env bash
+[ "$1" = "--version" ] && exit 0
+url="${@: -1}"
+echo "$*" >> "ARGS"
+touch "RUN/$$"
+ls "RUN" | wc -l >> "COUNTS"
+sleep 0.2
+rm -f "RUN/$$"
+case "$url" in
+  */@fresh/*)
+    echo '{"id":"fresh-new","title":"Fresh new","uploader":"Fresh","duration":60,"timestamp":NEW}'
+    echo '{"id":"fresh-old","title":"Fresh old","uploader":"Fresh","duration":60,"timestamp":OLDER}'
+    ;;
+  */@old/*)
+    echo '{"id":"old","title":"Old","duration":60,"timestamp":OLD}'
+    ;;
+  */@missing/*)
+    echo "ERROR: [youtube:tab] missing: HTTP Error 404: Not Found" >&2
+    exit 1
+    ;;
+  *)
+    echo "{\"id\":\"filler-$$\",\"title\":\"Filler\",\"duration\":60}"
+    ;;
+esac
+`
+	script = strings.NewReplacer(
+		"ARGS", argsPath,
+		"COUNTS", countsPath,
+		"RUN", runDir,
+		"NEW", ts(10*time.Minute),
+		"OLDER", ts(3*time.Hour),
+		"OLD", ts(2*time.Hour),
+	).Replace(script)
+
+	cfg := config.GetDefault()
+	cfg.YTDLPPath = makeExecutable(t, "fake-yt-dlp.sh", script)
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	return argsPath, countsPath
+}
+
+func TestFetchFeedMergesChannelsNewestFirst(t *testing.T) {
+	now := time.Now()
+	argsPath, countsPath := setupFakeFeed(t, now)
+
+	channels := []string{"old", "fresh", "missing", "f1", "f2", "f3", "f4", "f5"}
+	for _, channel := range channels {
+		if _, err := AddSubscription("@" + channel); err != nil {
+			t.Fatalf("AddSubscription() error = %v", err)
+		}
+	}
+	lastCheck := now.Add(-time.Hour)
+	if err := MarkSubscriptionsChecked(channels, lastCheck); err != nil {
+		t.Fatalf("MarkSubscriptionsChecked() error = %v", err)
+	}
+
+	subs, err := LoadSubscriptions()
+	if err != nil {
+		t.Fatalf("LoadSubscriptions() error = %v", err)
+	}
+
+	msg, ok := FetchFeed(NewFeedManager(), subs, 5, "", "")().(types.FeedResultMsg)
+	if !ok {
+		t.Fatalf("expected types.FeedResultMsg")
+	}
+	if msg.Err != "" {
+		t.Fatalf("unexpected error: %s", msg.Err)
+	}
+	if msg.Channels != len(channels) || msg.New != 1 {
+		t.Fatalf("Channels/New = %d/%d, want %d/1", msg.Channels, msg.New, len(channels))
+	}
+	if len(msg.Failed) != 1 || msg.Failed[0] != "@missing: Channel not found" {
+		t.Fatalf("Failed = %v", msg.Failed)
+	}
+
+	var ids []string
+	for _, item := range msg.Videos {
+		video := item.(types.VideoItem)
+		if video.IsNew != (video.ID == "fresh-new") {
+			t.Fatalf("%s IsNew = %v", video.ID, video.IsNew)
+		}
+		ids = append(ids, video.ID)
+	}
+	if len(ids) != 8 || !slices.Equal(ids[:3], []string{"fresh-new", "old", "fresh-old"}) {
+		t.Fatalf("video order = %v, want dated uploads newest first, then the undated ones", ids)
+	}
+
+	first := msg.Videos[0].(types.VideoItem)
+	if !strings.HasPrefix(first.Desc, "10m ago • ") {
+		t.Fatalf("Desc = %q, want the upload age first", first.Desc)
+	}
+
+	args, _ := os.ReadFile(argsPath)
+	if !strings.Contains(string(args), "--extractor-args youtubetab:approximate_date") || !strings.Contains(string(args), "--playlist-items 1:5") {
+		t.Fatalf("unexpected yt-dlp args:\n%s", args)
+	}
+
+	counts, _ := os.ReadFile(countsPath)
+	for line := range strings.Lines(string(counts)) {
+		if n, _ := strconv.Atoi(strings.TrimSpace(line)); n > feedWorkers {
+			t.Fatalf("%d listings ran at once, want at most %d", n, feedWorkers)
+		}
+	}
+
+	subs, _ = LoadSubscriptions()
+	for _, sub := range subs {
+		checked := !sub.LastChecked.Equal(lastCheck)
+		if checked != (sub.Channel != "missing") {
+			t.Fatalf("@%s LastChecked = %v, want updated only for listed channels", sub.Channel, sub.LastChecked)
+		}
+	}
+}
+
+func TestFetchFeedWithoutSubscriptions(t *testing.T) {
+	msg := FetchFeed(NewFeedManager(), nil, 5, "", "")().(types.FeedResultMsg)
+	if !strings.HasPrefix(msg.Err, "No subscriptions yet") {
+		t.Fatalf("Err = %q", msg.Err)
+	}
+}
+
+func TestFetchFeedAllChannelsFailing(t *testing.T) {
+	setupFakeFeed(t, time.Now())
+
+	AddSubscription("@missing")
+	subs, _ := LoadSubscriptions()
+
+	msg := FetchFeed(NewFeedManager(), subs, 5, "", "")().(types.FeedResultMsg)
+	if msg.Err != "Failed to load the feed: @missing: Channel not found" {
+		t.Fatalf("Err = %q", msg.Err)
+	}
+}
+
+func TestFetchFeedCancel(t *testing.T) {
+	setupFakeFeed(t, time.Now())
+
+	for _, channel := range []string{"f1", "f2", "f3", "f4", "f5", "f6"} {
+		AddSubscription("@" + channel)
+	}
+	subs, _ := LoadSubscriptions()
+
+	fm := NewFeedManager()
+	done := make(chan any)
+	go func() { done <- FetchFeed(fm, subs, 5, "", "")() }()
+
+	time.Sleep(100 * time.Millisecond)
+	if err := fm.Cancel(); err != nil {
+		t.Fatalf("Cancel() error = %v", err)
+	}
+
+	select {
+	case msg := <-done:
+		if msg != nil {
+			t.Fatalf("FetchFeed() = %T after cancel, want nil", msg)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("timed out waiting for the feed to stop")
+	}
+}
+
+func TestFormatAge(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		ago  time.Duration
+		want string
+	}{
+		{10 * time.Second, "1m ago"},
+		{45 * time.Minute, "45m ago"},
+		{5 * time.Hour, "5h ago"},
+		{3 * 24 * time.Hour, "3d ago"},
+		{60 * 24 * time.Hour, "Aug 18, 2026"},
+	}
+
+	for _, tt := range tests {
+		if got := formatAge(now.Add(-tt.ago), now); got != tt.want {
+			t.Fatalf("formatAge(-%v) = %q, want %q", tt.ago, got, tt.want)
+		}
+	}
+}
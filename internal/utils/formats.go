@@ -6,6 +6,8 @@ import (
 	"io"
 	"log"
 	"os/exec"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -233,7 +235,7 @@ func FetchFormats(fm *FormatsManager, url string) tea.Cmd {
 			formatType := ""
 			isVideoAudio := false
 			isAudioOnly := false
-			isThumbnail := ext == "mhtml"
+			isStoryboard := ext == "mhtml"
 
 			if vcodec != "none" && vcodec != "" {
 				if acodec != "none" && acodec != "" {
@@ -245,8 +247,8 @@ func FetchFormats(fm *FormatsManager, url string) tea.Cmd {
 			} else if acodec != "none" && acodec != "" {
 				formatType = "audio-only"
 				isAudioOnly = true
-			} else if isThumbnail {
-				formatType = "thumbnail"
+			} else if isStoryboard {
+				formatType = "storyboard"
 			} else {
 				formatType = "unknown"
 			}
@@ -274,7 +276,7 @@ func FetchFormats(fm *FormatsManager, url string) tea.Cmd {
 				if abr > 0 {
 					title = fmt.Sprintf("%dk", int(abr))
 				}
-			} else if isThumbnail {
+			} else if isStoryboard {
 				title = formatQuality(resolution)
 			} else {
 				quality := formatQuality(resolution)
@@ -302,6 +304,12 @@ func FetchFormats(fm *FormatsManager, url string) tea.Cmd {
 				ABR:         abr,
 			}
 
+			if isAudioOnly {
+				formatItem.AudioSize = size
+			} else {
+				formatItem.VideoSize = size
+			}
+
 			allFormats = append(allFormats, formatItem)
 
 			if isVideoAudio {
@@ -310,8 +318,6 @@ func FetchFormats(fm *FormatsManager, url string) tea.Cmd {
 				}
 			} else if isAudioOnly {
 				audioFormats = append(audioFormats, formatItem)
-			} else if isThumbnail {
-				thumbnailFormats = append(thumbnailFormats, formatItem)
 			}
 		}
 
@@ -406,10 +412,20 @@ func FetchFormats(fm *FormatsManager, url string) tea.Cmd {
 			}
 		}
 
+		for _, thumb := range extractThumbnails(data) {
+			thumbnailFormats = append(thumbnailFormats, thumb)
+		}
+
+		var subtitleFormats []list.Item
+		for _, sub := range extractSubtitles(data) {
+			subtitleFormats = append(subtitleFormats, sub)
+		}
+
 		return types.FormatResultMsg{
 			VideoFormats:     videoFormats,
 			AudioFormats:     audioFormats,
 			ThumbnailFormats: thumbnailFormats,
+			SubtitleFormats:  subtitleFormats,
 			AllFormats:       allFormats,
 			VideoInfo:        videoInfo,
 		}
@@ -420,6 +436,7 @@ func extractVideoInfo(data map[string]any) types.VideoItem {
 	videoID, _ := data["id"].(string)
 	title, _ := data["title"].(string)
 	channel, _ := data["uploader"].(string)
+	uploadDate, _ := data["upload_date"].(string)
 
 	var viewCount float64
 	if vc, ok := data["view_count"]; ok {
@@ -447,7 +464,92 @@ func extractVideoInfo(data map[string]any) types.VideoItem {
 		Views:      viewCount,
 		Duration:   duration,
 		Channel:    channel,
+		UploadDate: uploadDate,
+	}
+}
+
+// extractThumbnails lists the thumbnails of a video, largest first. Those
+// without a reported size come last.
+func extractThumbnails(data map[string]any) []types.ThumbnailItem {
+	thumbnailsAny, _ := data["thumbnails"].([]any)
+
+	var items []types.ThumbnailItem
+	seen := make(map[string]bool)
+	for _, tAny := range thumbnailsAny {
+		t, ok := tAny.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		url, _ := t["url"].(string)
+		if url == "" || seen[url] {
+			continue
+		}
+		seen[url] = true
+
+		id, _ := t["id"].(string)
+		items = append(items, types.ThumbnailItem{
+			ID:     id,
+			URL:    url,
+			Width:  int(parseFloat(t["width"])),
+			Height: int(parseFloat(t["height"])),
+		})
 	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Width*items[i].Height > items[j].Width*items[j].Height
+	})
+
+	return items
+}
+
+// extractSubtitles lists the uploaded subtitles of a video followed by its
+// automatic captions, each sorted by language.
+func extractSubtitles(data map[string]any) []types.SubtitleItem {
+	var items []types.SubtitleItem
+	for _, source := range []struct {
+		key  string
+		auto bool
+	}{{"subtitles", false}, {"automatic_captions", true}} {
+		tracks, ok := data[source.key].(map[string]any)
+		if !ok {
+			continue
+		}
+
+		var group []types.SubtitleItem
+		for lang, entriesAny := range tracks {
+			// Chat replays of live streams are listed as a subtitle track.
+			if lang == "live_chat" {
+				continue
+			}
+
+			entries, _ := entriesAny.([]any)
+			item := types.SubtitleItem{Language: lang, Auto: source.auto}
+			for _, entryAny := range entries {
+				entry, ok := entryAny.(map[string]any)
+				if !ok {
+					continue
+				}
+
+				if name, _ := entry["name"].(string); name != "" && item.Name == "" {
+					item.Name = name
+				}
+
+				if ext, _ := entry["ext"].(string); ext != "" && !slices.Contains(item.Formats, ext) {
+					item.Formats = append(item.Formats, ext)
+				}
+			}
+
+			group = append(group, item)
+		}
+
+		sort.Slice(group, func(i, j int) bool {
+			return group[i].Language < group[j].Language
+		})
+		items = append(items, group...)
+	}
+
+	return items
 }
 
 func CancelFormats(fm *FormatsManager) tea.Cmd {
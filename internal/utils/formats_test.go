@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/xdagiz/xytz/internal/types"
+)
+
+func TestExtractSubtitles(t *testing.T) {
+	raw := `{
+		"subtitles": {
+			"live_chat": [{"ext": "json"}],
+			"fr": [{"ext": "vtt", "name": "French"}],
+			"en": [{"ext": "vtt", "name": "English"}, {"ext": "srv3", "name": "English"}, {"ext": "vtt"}]
+		},
+		"automatic_captions": {
+			"de": [{"ext": "vtt", "name": "German"}],
+			"en": [{"ext": "json3", "name": "English"}]
+		}
+	}`
+
+	var data map[string]any
+	if err := json.Unmarshal([]byte(raw), &data); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	want := []types.SubtitleItem{
+		{Language: "en", Name: "English", Formats: []string{"vtt", "srv3"}},
+		{Language: "fr", Name: "French", Formats: []string{"vtt"}},
+		{Language: "de", Name: "German", Auto: true, Formats: []string{"vtt"}},
+		{Language: "en", Name: "English", Auto: true, Formats: []string{"json3"}},
+	}
+
+	if got := extractSubtitles(data); !reflect.DeepEqual(got, want) {
+		t.Fatalf("extractSubtitles =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestExtractSubtitlesWithoutTracks(t *testing.T) {
+	if got := extractSubtitles(map[string]any{"id": "abc"}); len(got) != 0 {
+		t.Fatalf("extractSubtitles = %+v, want none", got)
+	}
+}
+
+func TestExtractThumbnails(t *testing.T) {
+	raw := `{
+		"thumbnails": [
+			{"id": "0", "url": "https://i.ytimg.com/vi/abc/default.jpg", "width": 120, "height": 90},
+			{"id": "1", "url": "https://i.ytimg.com/vi/abc/sd1.jpg"},
+			{"id": "2", "url": "https://i.ytimg.com/vi_webp/abc/maxresdefault.webp", "width": 1280, "height": 720},
+			{"id": "3", "url": "https://i.ytimg.com/vi/abc/default.jpg", "width": 120, "height": 90},
+			{"id": "4"}
+		]
+	}`
+
+	var data map[string]any
+	if err := json.Unmarshal([]byte(raw), &data); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	want := []types.ThumbnailItem{
+		{ID: "2", URL: "https://i.ytimg.com/vi_webp/abc/maxresdefault.webp", Width: 1280, Height: 720},
+		{ID: "0", URL: "https://i.ytimg.com/vi/abc/default.jpg", Width: 120, Height: 90},
+		{ID: "1", URL: "https://i.ytimg.com/vi/abc/sd1.jpg"},
+	}
+
+	if got := extractThumbnails(data); !reflect.DeepEqual(got, want) {
+		t.Fatalf("extractThumbnails =\n%+v\nwant\n%+v", got, want)
+	}
+}
@@ -2,11 +2,11 @@ package utils
 
 import (
 	"log"
-	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/xdagiz/xytz/internal/paths"
+	"github.com/xdagiz/xytz/internal/store"
 )
 
 const HistoryFileName = "history"
@@ -22,27 +22,29 @@ var GetHistoryFilePath = func() string {
 }
 
 func LoadHistory() ([]string, error) {
-	path := GetHistoryFilePath()
-	data, err := os.ReadFile(path)
+	data, err := store.Read(GetHistoryFilePath())
 	if err != nil {
-		if os.IsNotExist(err) {
-			return []string{}, nil
-		}
 		return nil, err
 	}
 
-	content := string(data)
-	lines := strings.Split(content, "\n")
+	history := parseHistory(data)
+	if history == nil {
+		return []string{}, nil
+	}
 
+	return history, nil
+}
+
+func parseHistory(data []byte) []string {
 	var history []string
-	for _, line := range lines {
+	for _, line := range strings.Split(string(data), "\n") {
 		trimmed := strings.TrimSpace(line)
 		if trimmed != "" {
 			history = append(history, trimmed)
 		}
 	}
 
-	return history, nil
+	return history
 }
 
 func SaveHistory(query string) error {
@@ -51,28 +53,21 @@ func SaveHistory(query string) error {
 	}
 
 	query = strings.TrimSpace(query)
-	path := GetHistoryFilePath()
 
-	history, err := LoadHistory()
-	if err != nil {
-		return err
-	}
-
-	var newHistory []string
-	for _, entry := range history {
-		if entry != query {
-			newHistory = append(newHistory, entry)
+	return store.Update(GetHistoryFilePath(), func(data []byte) ([]byte, error) {
+		newHistory := []string{query}
+		for _, entry := range parseHistory(data) {
+			if entry != query {
+				newHistory = append(newHistory, entry)
+			}
 		}
-	}
 
-	newHistory = append([]string{query}, newHistory...)
-
-	if len(newHistory) > 1000 {
-		newHistory = newHistory[:1000]
-	}
+		if len(newHistory) > 1000 {
+			newHistory = newHistory[:1000]
+		}
 
-	content := strings.Join(newHistory, "\n")
-	return os.WriteFile(path, []byte(content), 0o644)
+		return []byte(strings.Join(newHistory, "\n")), nil
+	})
 }
 
 func AddToHistory(query string) error {
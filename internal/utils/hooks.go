@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/xdagiz/xytz/internal/types"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type HookEnv struct {
+	File       string
+	VideoID    string
+	Title      string
+	Channel    string
+	QueueLabel string
+	FormatID   string
+}
+
+func (e HookEnv) Environ() []string {
+	return append(os.Environ(),
+		"XYTZ_FILE="+e.File,
+		"XYTZ_VIDEO_ID="+e.VideoID,
+		"XYTZ_TITLE="+e.Title,
+		"XYTZ_CHANNEL="+e.Channel,
+		"XYTZ_QUEUE_LABEL="+e.QueueLabel,
+		"XYTZ_FORMAT_ID="+e.FormatID,
+	)
+}
+
+// hookWaitDelay is how long a killed hook's output is waited for, in case
+// something it started in the background still holds it open.
+const hookWaitDelay = 2 * time.Second
+
+func hookCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
+
+// RunPostDownloadHooks runs each hook in order through the shell and stops
+// at the first one that fails, since later hooks usually depend on it. A
+// hook running longer than timeout is killed and counts as failed.
+func RunPostDownloadHooks(hooks []string, env HookEnv, queueIndex int, timeout time.Duration) tea.Cmd {
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	return func() tea.Msg {
+		results := make([]types.HookResult, 0, len(hooks))
+		for _, hook := range hooks {
+			hook = strings.TrimSpace(hook)
+			if hook == "" {
+				continue
+			}
+
+			result := runHook(hook, env, timeout)
+			results = append(results, result)
+			if result.Err != "" {
+				break
+			}
+		}
+
+		return types.HookResultMsg{
+			QueueIndex: queueIndex,
+			Title:      env.Title,
+			Results:    results,
+		}
+	}
+}
+
+func runHook(hook string, env HookEnv, timeout time.Duration) types.HookResult {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := hookCommand(ctx, hook)
+	cmd.Env = env.Environ()
+	killHookGroup(cmd)
+	cmd.WaitDelay = hookWaitDelay
+
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+
+	err := cmd.Run()
+	result := types.HookResult{
+		Command: hook,
+		Output:  strings.TrimSpace(out.String()),
+	}
+
+	if err != nil {
+		result.ExitCode = -1
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			result.ExitCode = exitErr.ExitCode()
+		}
+		result.Err = err.Error()
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			result.Err = fmt.Sprintf("timed out after %s", timeout)
+		}
+		log.Printf("Post-download hook %q failed (exit %d) for %s: %v\n%s", hook, result.ExitCode, env.File, err, result.Output)
+	} else {
+		log.Printf("Post-download hook %q finished for %s\n%s", hook, env.File, result.Output)
+	}
+
+	return result
+}
@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xdagiz/xytz/internal/types"
+)
+
+func TestRunPostDownloadHooksPassesEnvironment(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook scripts use sh")
+	}
+
+	env := HookEnv{
+		File:       "/tmp/video.mp4",
+		VideoID:    "abc",
+		Title:      "A Title",
+		Channel:    "Chan",
+		QueueLabel: "lofi",
+		FormatID:   "137+140",
+	}
+
+	cmd := RunPostDownloadHooks([]string{`echo "$XYTZ_FILE|$XYTZ_VIDEO_ID|$XYTZ_TITLE|$XYTZ_CHANNEL|$XYTZ_QUEUE_LABEL|$XYTZ_FORMAT_ID"`}, env, 2, time.Minute)
+	if cmd == nil {
+		t.Fatalf("expected non-nil hook command")
+	}
+
+	msg, ok := cmd().(types.HookResultMsg)
+	if !ok {
+		t.Fatalf("cmd() returned %T, want types.HookResultMsg", cmd())
+	}
+	if msg.QueueIndex != 2 || msg.Title != "A Title" {
+		t.Fatalf("msg = %+v, want queue index 2 and title", msg)
+	}
+	if len(msg.Results) != 1 {
+		t.Fatalf("len(msg.Results) = %d, want 1", len(msg.Results))
+	}
+
+	want := "/tmp/video.mp4|abc|A Title|Chan|lofi|137+140"
+	if msg.Results[0].Output != want {
+		t.Fatalf("hook output = %q, want %q", msg.Results[0].Output, want)
+	}
+	if msg.Results[0].Err != "" || msg.Results[0].ExitCode != 0 {
+		t.Fatalf("hook result = %+v, want success", msg.Results[0])
+	}
+}
+
+func TestRunPostDownloadHooksStopsAtFirstFailure(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook scripts use sh")
+	}
+
+	cmd := RunPostDownloadHooks([]string{"echo first", "echo broken >&2; exit 3", "echo never"}, HookEnv{}, 0, time.Minute)
+	msg := cmd().(types.HookResultMsg)
+
+	if len(msg.Results) != 2 {
+		t.Fatalf("len(msg.Results) = %d, want 2", len(msg.Results))
+	}
+	failed := msg.Results[1]
+	if failed.ExitCode != 3 {
+		t.Fatalf("failed.ExitCode = %d, want 3", failed.ExitCode)
+	}
+	if failed.Err == "" || !strings.Contains(failed.Output, "broken") {
+		t.Fatalf("failed = %+v, want error with stderr output", failed)
+	}
+}
+
+func TestRunPostDownloadHooksKillsHookAfterTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook scripts use sh")
+	}
+
+	start := time.Now()
+	cmd := RunPostDownloadHooks([]string{"sleep 10", "echo never"}, HookEnv{}, 0, 100*time.Millisecond)
+	msg := cmd().(types.HookResultMsg)
+
+	if elapsed := time.Since(start); elapsed >= hookWaitDelay {
+		t.Fatalf("hooks took %s, want the slow hook and its children killed", elapsed)
+	}
+	if len(msg.Results) != 1 {
+		t.Fatalf("len(msg.Results) = %d, want 1", len(msg.Results))
+	}
+	if got := msg.Results[0]; !strings.Contains(got.Err, "timed out") {
+		t.Fatalf("result = %+v, want a timeout error", got)
+	}
+}
+
+func TestRunPostDownloadHooksWithoutHooks(t *testing.T) {
+	if cmd := RunPostDownloadHooks(nil, HookEnv{}, 0, time.Minute); cmd != nil {
+		t.Fatalf("expected nil command without hooks")
+	}
+}
@@ -0,0 +1,8 @@
+//go:build windows
+
+package utils
+
+import "os/exec"
+
+// killHookGroup leaves the default of killing just cmd /C on timeout.
+func killHookGroup(cmd *exec.Cmd) {}
@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/xdagiz/xytz/internal/config"
+	"github.com/xdagiz/xytz/internal/types"
+)
+
+var dirNameReplacer = strings.NewReplacer(
+	"/", "_", "\\", "_", ":", "_", "*", "_", "?", "_",
+	"\"", "_", "<", "_", ">", "_", "|", "_",
+)
+
+func sanitizeDirName(name string) string {
+	name = dirNameReplacer.Replace(strings.TrimSpace(name))
+	name = strings.TrimRight(name, ". ")
+	if name == "" || name == "_" {
+		return ""
+	}
+
+	return name
+}
+
+// ResolveOutputDir returns the directory a download should be written to for
+// the given organize mode. It falls back to base when the mode has nothing
+// to group by, e.g. a by-playlist download that didn't come from a playlist.
+func ResolveOutputDir(base, mode string, video types.VideoItem, channel, playlist, queueLabel string) string {
+	var sub string
+	switch mode {
+	case config.OrganizeChannel:
+		sub = video.Channel
+		if sub == "" {
+			sub = channel
+		}
+	case config.OrganizePlaylist:
+		sub = playlist
+	case config.OrganizeQueue:
+		sub = queueLabel
+	}
+
+	sub = sanitizeDirName(sub)
+	if sub == "" {
+		return base
+	}
+
+	return filepath.Join(base, sub)
+}
@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/xdagiz/xytz/internal/config"
+	"github.com/xdagiz/xytz/internal/types"
+)
+
+func TestResolveOutputDir(t *testing.T) {
+	base := filepath.Join("downloads")
+	video := types.VideoItem{ID: "abc", Channel: "AC/DC Official"}
+
+	tests := []struct {
+		name       string
+		mode       string
+		video      types.VideoItem
+		channel    string
+		playlist   string
+		queueLabel string
+		want       string
+	}{
+		{name: "flat", mode: config.OrganizeFlat, video: video, queueLabel: "q", want: base},
+		{name: "by channel", mode: config.OrganizeChannel, video: video, want: filepath.Join(base, "AC_DC Official")},
+		{name: "by channel falls back to searched channel", mode: config.OrganizeChannel, channel: "@band", want: filepath.Join(base, "@band")},
+		{name: "by playlist", mode: config.OrganizePlaylist, video: video, playlist: "PL123", want: filepath.Join(base, "PL123")},
+		{name: "by playlist without playlist", mode: config.OrganizePlaylist, video: video, want: base},
+		{name: "by queue label", mode: config.OrganizeQueue, video: video, queueLabel: "lofi: mix?", want: filepath.Join(base, "lofi_ mix_")},
+		{name: "trailing dots trimmed", mode: config.OrganizeChannel, video: types.VideoItem{Channel: "Long Channel..."}, want: filepath.Join(base, "Long Channel")},
+		{name: "unsafe only name", mode: config.OrganizeQueue, queueLabel: " / ", want: base},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ResolveOutputDir(base, tt.mode, tt.video, tt.channel, tt.playlist, tt.queueLabel)
+			if got != tt.want {
+				t.Errorf("ResolveOutputDir() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
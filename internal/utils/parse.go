@@ -7,11 +7,12 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/xdagiz/xytz/internal/types"
 )
 
-var ErrSkippedLiveShort = errors.New("skipping live/short content with zero duration")
+var ErrSkippedLiveShort = errors.New("skipping short content with zero duration")
 
 func ParseSearchQuery(query string) (string, string) {
 	query = strings.TrimSpace(query)
@@ -138,29 +139,45 @@ func BuildVideoURL(videoID string) string {
 }
 
 func BuildChannelURL(input string) string {
+	return BuildChannelTabURL(input, types.ChannelTabVideos)
+}
+
+// BuildChannelTabURL links to a tab of a channel, replacing any tab the
+// input already points at.
+func BuildChannelTabURL(input string, tab types.ChannelTab) string {
 	input = strings.TrimSpace(input)
 
 	if strings.Contains(input, "youtube.com") {
-		channelURL := input
-		if !strings.HasSuffix(channelURL, "/videos") {
-			channelURL = strings.TrimSuffix(channelURL, "/") + "/videos"
+		channelURL := strings.TrimSuffix(input, "/")
+		for _, t := range types.ChannelTabs {
+			channelURL = strings.TrimSuffix(channelURL, "/"+string(t))
 		}
 
-		return channelURL
+		return channelURL + "/" + string(tab)
 	}
 
 	if strings.HasPrefix(input, "@") {
-		return "https://www.youtube.com/" + input + "/videos"
+		return "https://www.youtube.com/" + input + "/" + string(tab)
 	}
 
 	if strings.HasPrefix(input, "UC") {
-		return "https://www.youtube.com/channel/" + input + "/videos"
+		return "https://www.youtube.com/channel/" + input + "/" + string(tab)
 	}
 
-	return "https://www.youtube.com/@" + url.PathEscape(input) + "/videos"
+	return "https://www.youtube.com/@" + url.PathEscape(input) + "/" + string(tab)
 }
 
 func ParseVideoItem(line string) (types.VideoItem, error) {
+	return parseVideoItem(line, false)
+}
+
+// ParseShortItem parses an entry of a channel's Shorts tab, which yt-dlp
+// lists without a duration.
+func ParseShortItem(line string) (types.VideoItem, error) {
+	return parseVideoItem(line, true)
+}
+
+func parseVideoItem(line string, short bool) (types.VideoItem, error) {
 	var data map[string]any
 	if err := json.Unmarshal([]byte(line), &data); err != nil {
 		return types.VideoItem{}, fmt.Errorf("failed to unmarshal JSON: %w", err)
@@ -196,32 +213,177 @@ func ParseVideoItem(line string) (types.VideoItem, error) {
 		durationFloat = parseFloat(d)
 	}
 
-	if durationFloat == 0 {
+	liveStatus, _ := data["live_status"].(string)
+	if isLive, _ := data["is_live"].(bool); isLive {
+		liveStatus = types.LiveStatusLive
+	}
+
+	if liveStatus != types.LiveStatusLive && liveStatus != types.LiveStatusUpcoming {
+		liveStatus = ""
+	}
+
+	if durationFloat == 0 && liveStatus == "" && !short {
 		return types.VideoItem{}, ErrSkippedLiveShort
 	}
 
+	uploadDate, _ := data["upload_date"].(string)
+
+	var scheduledStart time.Time
+	if ts := parseFloat(data["release_timestamp"]); ts > 0 && liveStatus == types.LiveStatusUpcoming {
+		scheduledStart = time.Unix(int64(ts), 0)
+	}
+
 	viewsStr := FormatNumber(viewCountFloat)
-	durationStr := FormatDuration(durationFloat)
 
 	channelLen := len(channel)
 	if channelLen > 30 {
 		channel = channel[:27] + "..."
 	}
 
-	desc := fmt.Sprintf("%s • %s views • %s", durationStr, viewsStr, channel)
+	var desc string
+	switch {
+	case liveStatus == types.LiveStatusLive:
+		desc = fmt.Sprintf("live now • %s watching • %s", viewsStr, channel)
+	case liveStatus == types.LiveStatusUpcoming:
+		desc = fmt.Sprintf("%s • %s", FormatScheduledStart(scheduledStart), channel)
+	case durationFloat == 0:
+		desc = fmt.Sprintf("short • %s views", viewsStr)
+		if channel != "" {
+			desc += " • " + channel
+		}
+	default:
+		desc = fmt.Sprintf("%s • %s views • %s", FormatDuration(durationFloat), viewsStr, channel)
+	}
 
 	videoItem := types.VideoItem{
-		ID:         videoID,
-		VideoTitle: title,
-		Desc:       desc,
-		Views:      viewCountFloat,
-		Duration:   durationFloat,
-		Channel:    channel,
+		ID:             videoID,
+		VideoTitle:     title,
+		Desc:           desc,
+		Views:          viewCountFloat,
+		Duration:       durationFloat,
+		Channel:        channel,
+		UploadDate:     uploadDate,
+		Published:      publishedTime(data["timestamp"], uploadDate),
+		LiveStatus:     liveStatus,
+		ScheduledStart: scheduledStart,
 	}
 
 	return videoItem, nil
 }
 
+// publishedTime is when a video was uploaded, from its timestamp or else
+// its YYYYMMDD upload date. It's zero when yt-dlp lists neither.
+func publishedTime(timestamp any, uploadDate string) time.Time {
+	if ts := parseFloat(timestamp); ts > 0 {
+		return time.Unix(int64(ts), 0)
+	}
+
+	if t, err := time.ParseInLocation("20060102", uploadDate, time.Local); err == nil {
+		return t
+	}
+
+	return time.Time{}
+}
+
+// FormatScheduledStart describes when an upcoming stream begins, e.g.
+// "starts Oct 18 15:04".
+func FormatScheduledStart(start time.Time) string {
+	if start.IsZero() {
+		return "starts soon"
+	}
+
+	start = start.Local()
+	if start.Year() != time.Now().Year() {
+		return "starts " + start.Format("Jan 2 2006 15:04")
+	}
+
+	return "starts " + start.Format("Jan 2 15:04")
+}
+
+// ParsePlaylistItem parses an entry of a channel's Playlists or Releases
+// tab.
+func ParsePlaylistItem(line string) (types.PlaylistItem, error) {
+	var data map[string]any
+	if err := json.Unmarshal([]byte(line), &data); err != nil {
+		return types.PlaylistItem{}, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+
+	title, _ := data["title"].(string)
+	id, _ := data["id"].(string)
+	if title == "" || id == "" {
+		return types.PlaylistItem{}, fmt.Errorf("missing title or ID in playlist data")
+	}
+
+	playlistURL, _ := data["url"].(string)
+	if !strings.Contains(playlistURL, "list=") {
+		playlistURL = BuildPlaylistURL(id)
+	}
+
+	count := int(parseFloat(data["playlist_count"]))
+
+	desc := "playlist"
+	switch {
+	case count == 1:
+		desc = "1 video"
+	case count > 1:
+		desc = fmt.Sprintf("%d videos", count)
+	}
+
+	return types.PlaylistItem{
+		ID:            id,
+		PlaylistTitle: title,
+		Desc:          desc,
+		URL:           playlistURL,
+		Count:         count,
+	}, nil
+}
+
+// ParseChannelItem parses a channel listed in search results.
+func ParseChannelItem(line string) (types.ChannelItem, error) {
+	var data map[string]any
+	if err := json.Unmarshal([]byte(line), &data); err != nil {
+		return types.ChannelItem{}, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+
+	name, _ := data["title"].(string)
+	id, _ := data["id"].(string)
+	if name == "" || id == "" {
+		return types.ChannelItem{}, fmt.Errorf("missing title or ID in channel data")
+	}
+
+	channelURL, _ := data["url"].(string)
+	channelName := ExtractChannelUsername(channelURL)
+	if handle, _ := data["uploader_id"].(string); strings.HasPrefix(handle, "@") {
+		channelName = ExtractChannelUsername(handle)
+	}
+	if channelName == "" {
+		channelName = id
+	}
+
+	desc := "channel"
+	if followers := parseFloat(data["channel_follower_count"]); followers > 0 {
+		desc = fmt.Sprintf("channel • %s subscribers", FormatNumber(followers))
+	}
+
+	return types.ChannelItem{
+		ID:          id,
+		Name:        name,
+		ChannelName: channelName,
+		Desc:        desc,
+		URL:         channelURL,
+	}, nil
+}
+
+// AddSearchParam adds the sp parameter built by types.SearchParam to a
+// search results URL.
+func AddSearchParam(searchURL, sp string) string {
+	if sp == "" || !strings.Contains(searchURL, "/results?") {
+		return searchURL
+	}
+
+	return searchURL + "&sp=" + sp
+}
+
 func parseFloat(v any) float64 {
 	switch val := v.(type) {
 	case json.Number:
@@ -1,6 +1,12 @@
 package utils
 
-import "testing"
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/xdagiz/xytz/internal/types"
+)
 
 func TestExtractVideoID(t *testing.T) {
 	tests := []struct {
@@ -394,6 +400,15 @@ func TestParseVideoItem(t *testing.T) {
 			wantViews:   100,
 			wantErr:     false,
 		},
+		{
+			name:        "live stream without duration",
+			input:       `{"id":"live","title":"Live Stream","uploader":"Channel","view_count":1500,"duration":null,"live_status":"is_live"}`,
+			wantID:      "live",
+			wantTitle:   "Live Stream",
+			wantChannel: "Channel",
+			wantViews:   1500,
+			wantErr:     false,
+		},
 		{
 			name:        "zero duration returns error",
 			input:       `{"id":"live","title":"Live Stream","view_count":100,"duration":0}`,
@@ -551,3 +566,96 @@ func TestParseSearchQuery(t *testing.T) {
 		})
 	}
 }
+
+func TestParseVideoItemLiveStatus(t *testing.T) {
+	live, err := ParseVideoItem(`{"id":"live","title":"Live","uploader":"Channel","view_count":1500,"live_status":"is_live"}`)
+	if err != nil {
+		t.Fatalf("ParseVideoItem() error = %v", err)
+	}
+	if !live.IsLive() || live.Desc != "live now • 1.5K watching • Channel" {
+		t.Errorf("live item = %+v", live)
+	}
+
+	upcoming, err := ParseVideoItem(`{"id":"soon","title":"Premiere","uploader":"Channel","live_status":"is_upcoming","release_timestamp":1893456000}`)
+	if err != nil {
+		t.Fatalf("ParseVideoItem() error = %v", err)
+	}
+	if !upcoming.IsUpcoming() || !upcoming.ScheduledStart.Equal(time.Unix(1893456000, 0)) {
+		t.Errorf("upcoming item = %+v", upcoming)
+	}
+	if want := FormatScheduledStart(upcoming.ScheduledStart) + " • Channel"; upcoming.Desc != want {
+		t.Errorf("upcoming Desc = %q, want %q", upcoming.Desc, want)
+	}
+
+	if _, err := ParseVideoItem(`{"id":"short","title":"Short","live_status":"not_live"}`); !errors.Is(err, ErrSkippedLiveShort) {
+		t.Errorf("zero duration video error = %v, want ErrSkippedLiveShort", err)
+	}
+}
+
+func TestBuildChannelTabURL(t *testing.T) {
+	tests := []struct {
+		input    string
+		tab      types.ChannelTab
+		expected string
+	}{
+		{"@username", types.ChannelTabShorts, "https://www.youtube.com/@username/shorts"},
+		{"UCxyz123abc", types.ChannelTabLive, "https://www.youtube.com/channel/UCxyz123abc/streams"},
+		{"https://www.youtube.com/@username/videos", types.ChannelTabPlaylists, "https://www.youtube.com/@username/playlists"},
+		{"https://www.youtube.com/@username/shorts/", types.ChannelTabVideos, "https://www.youtube.com/@username/videos"},
+	}
+
+	for _, tt := range tests {
+		if got := BuildChannelTabURL(tt.input, tt.tab); got != tt.expected {
+			t.Errorf("BuildChannelTabURL(%q, %q) = %q, want %q", tt.input, tt.tab, got, tt.expected)
+		}
+	}
+}
+
+func TestParseShortItem(t *testing.T) {
+	short, err := ParseShortItem(`{"id":"abc","title":"Short","uploader":"Channel","view_count":2000}`)
+	if err != nil {
+		t.Fatalf("ParseShortItem() error = %v", err)
+	}
+	if short.ID != "abc" || short.Desc != "short • 2.0K views • Channel" {
+		t.Errorf("short item = %+v", short)
+	}
+}
+
+func TestParsePlaylistItem(t *testing.T) {
+	playlist, err := ParsePlaylistItem(`{"id":"PL123","title":"Mixes","playlist_count":12}`)
+	if err != nil {
+		t.Fatalf("ParsePlaylistItem() error = %v", err)
+	}
+	if playlist.URL != BuildPlaylistURL("PL123") || playlist.Desc != "12 videos" || playlist.Title() != "Mixes" {
+		t.Errorf("playlist item = %+v", playlist)
+	}
+
+	if _, err := ParsePlaylistItem(`{"id":"PL123"}`); err == nil {
+		t.Error("ParsePlaylistItem() without a title error = nil, want error")
+	}
+}
+
+func TestAddSearchParam(t *testing.T) {
+	_, searchURL := ParseSearchQuery("lofi beats")
+	sp := types.SearchParam(types.SortByDate, types.SearchFilters{Type: types.ResultTypeVideo})
+
+	if got, want := AddSearchParam(searchURL, sp), searchURL+"&sp=CAISAhAB"; got != want {
+		t.Errorf("AddSearchParam() = %q, want %q", got, want)
+	}
+	if got := AddSearchParam(searchURL, ""); got != searchURL {
+		t.Errorf("AddSearchParam() without filters = %q, want %q", got, searchURL)
+	}
+	if playlistURL := BuildPlaylistURL("PL1"); AddSearchParam(playlistURL, sp) != playlistURL {
+		t.Errorf("AddSearchParam() changed a playlist URL")
+	}
+}
+
+func TestParseChannelItem(t *testing.T) {
+	channel, err := ParseChannelItem(`{"id":"UCabc","title":"Lofi Girl","url":"https://www.youtube.com/channel/UCabc","uploader_id":"@LofiGirl","channel_follower_count":1500000}`)
+	if err != nil {
+		t.Fatalf("ParseChannelItem() error = %v", err)
+	}
+	if channel.ChannelName != "LofiGirl" || channel.Desc != "channel • 1.5M subscribers" {
+		t.Errorf("channel item = %+v", channel)
+	}
+}
@@ -47,13 +47,19 @@ func (pm *PlayerManager) Kill() {
 }
 
 func (pm *PlayerManager) PlayURL(url string, ytdlFormat string, video types.VideoItem, program *tea.Program) tea.Cmd {
+	return pm.PlayURLs([]string{url}, ytdlFormat, video, program)
+}
+
+// PlayURLs plays urls one after the other in a single mpv window. video is
+// shown as the one playing.
+func (pm *PlayerManager) PlayURLs(urls []string, ytdlFormat string, video types.VideoItem, program *tea.Program) tea.Cmd {
 	return func() tea.Msg {
-		args := make([]string, 0, 2)
+		args := make([]string, 0, len(urls)+1)
 		if ytdlFormat != "" {
 			args = append(args, "--ytdl-format="+ytdlFormat)
 		}
 
-		args = append(args, url)
+		args = append(args, urls...)
 		cmd := exec.Command("mpv", args...)
 
 		if err := cmd.Start(); err != nil {
@@ -2,26 +2,121 @@ package utils
 
 import (
 	"bufio"
+	"encoding/json"
+	"fmt"
 	"io"
 	"log"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync/atomic"
+
+	"github.com/xdagiz/xytz/internal/types"
+)
+
+// yt-dlp prints these prefixes in front of the lines produced by the
+// templates from ProgressTemplateArgs.
+const (
+	progressPrefix    = "xytz-progress:"
+	postprocessPrefix = "xytz-postprocess:"
+)
+
+var (
+	percentRegex         = regexp.MustCompile(`(\d+(?:\.\d+)?)\s*%`)
+	speedRegex           = regexp.MustCompile(`(\d+(?:\.\d+)?[KMG]?i?B/s)`)
+	etaRegex             = regexp.MustCompile(`ETA\s+(\d+:\d+(?::\d+)?)`)
+	waitRegex            = regexp.MustCompile(`^\[wait\].*?(\d+:\d+(?::\d+)?)`)
+	ffmpegProgressRegex  = regexp.MustCompile(`size=\s*(\d+)\s*(?:KiB|kB)\s+time=(\d+):(\d{2}):(\d{2}(?:\.\d+)?)`)
+	destinationRegex     = regexp.MustCompile(`Destination:\s*(.+)`)
+	mergeRegex           = regexp.MustCompile(`\[Merger\] Merging formats into "(.+)"`)
+	subtitleRegex        = regexp.MustCompile(`Writing video subtitles to:\s*(.+)`)
+	formatRegex          = regexp.MustCompile(`(?:format|format_id)\s+(\d+)`)
+	postprocessStageTags = []string{
+		types.StageMerger,
+		types.StageVideoRemuxer,
+		types.StageExtractAudio,
+		types.StageEmbedSubtitle,
+		types.StageMetadata,
+		types.StageFixupM3u8,
+		"VideoConvertor",
+		"EmbedThumbnail",
+		"ModifyChapters",
+		"SponsorBlock",
+		"SplitChapters",
+		"FixupM4a",
+		"FixupStretched",
+		"FixupDuplicateMoov",
+		"FixupTimestamp",
+	}
 )
 
+// legacyProgress is set once yt-dlp has rejected --progress-template, so later
+// downloads go straight to scraping its default output.
+var legacyProgress atomic.Bool
+
+// ProgressTemplateArgs asks yt-dlp to report download and post-processing
+// progress as machine-readable lines.
+func ProgressTemplateArgs() []string {
+	return []string{
+		"--progress-template",
+		"download:" + progressPrefix + "%(info.format_id)s %(info.vcodec)s %(info.acodec)s %(progress)j",
+		"--progress-template",
+		"postprocess:" + postprocessPrefix + "%(progress.postprocessor)s %(progress.status)s",
+	}
+}
+
+type Progress struct {
+	Percent         float64
+	Speed           string
+	Eta             string
+	Status          string
+	Stage           string
+	Destination     string
+	DownloadedBytes int64
+	TotalBytes      int64
+	FragmentIndex   int
+	FragmentCount   int
+	Elapsed         float64
+}
+
+// WaitingStatus is the status of a download waiting for an upcoming stream
+// to start. Eta is then the time until yt-dlp checks again.
+const WaitingStatus = "[wait]"
+
+type templateProgress struct {
+	Status             string   `json:"status"`
+	DownloadedBytes    float64  `json:"downloaded_bytes"`
+	TotalBytes         float64  `json:"total_bytes"`
+	TotalBytesEstimate float64  `json:"total_bytes_estimate"`
+	Speed              *float64 `json:"speed"`
+	ETA                *float64 `json:"eta"`
+	FragmentIndex      float64  `json:"fragment_index"`
+	FragmentCount      float64  `json:"fragment_count"`
+	Elapsed            float64  `json:"elapsed"`
+	Filename           string   `json:"filename"`
+}
+
 type ProgressParser struct {
-	regex              *regexp.Regexp
-	currentFormat      string
-	currentDestination string
+	templateUnsupported atomic.Bool
+	noSpace             atomic.Bool
 }
 
 func NewProgressParser() *ProgressParser {
-	return &ProgressParser{
-		regex: regexp.MustCompile(`(\d+(?:\.\d+)?)%`),
-	}
+	return &ProgressParser{}
+}
+
+// TemplateUnsupported reports whether yt-dlp rejected --progress-template,
+// which happens with versions older than 2021.10.
+func (p *ProgressParser) TemplateUnsupported() bool {
+	return p.templateUnsupported.Load()
+}
+
+// NoSpace reports whether yt-dlp or ffmpeg ran out of disk space.
+func (p *ProgressParser) NoSpace() bool {
+	return p.noSpace.Load()
 }
 
-func (p *ProgressParser) ReadPipe(pipe io.Reader, sendProgress func(float64, string, string, string, string)) {
+func (p *ProgressParser) ReadPipe(pipe io.Reader, sendProgress func(Progress)) {
 	reader := bufio.NewReader(pipe)
 	var lineBuilder strings.Builder
 
@@ -29,11 +124,7 @@ func (p *ProgressParser) ReadPipe(pipe io.Reader, sendProgress func(float64, str
 		r, _, err := reader.ReadRune()
 		if err != nil {
 			if lineBuilder.Len() > 0 {
-				line := lineBuilder.String()
-				percent, speed, eta, status, destination := p.ParseLine(line)
-				if strings.Contains(line, "[download]") || percent > 0 || speed != "" || eta != "" {
-					sendProgress(percent, speed, eta, status, destination)
-				}
+				p.handleLine(lineBuilder.String(), sendProgress)
 			}
 
 			break
@@ -41,25 +132,9 @@ func (p *ProgressParser) ReadPipe(pipe io.Reader, sendProgress func(float64, str
 
 		switch r {
 		// TODO: test this on windows and remove if not needed
-		case '\r':
-			if lineBuilder.Len() > 0 {
-				line := lineBuilder.String()
-				percent, speed, eta, status, destination := p.ParseLine(line)
-				if strings.Contains(line, "[download]") || percent > 0 || speed != "" || eta != "" {
-					log.Printf("Progress parsed (\\r): %.2f%%, speed: %s, eta: %s, status: %s, destination: %s, line: %s", percent, speed, eta, status, destination, line)
-					sendProgress(percent, speed, eta, status, destination)
-				}
-
-				lineBuilder.Reset()
-			}
-
-		case '\n':
+		case '\r', '\n':
 			if lineBuilder.Len() > 0 {
-				line := lineBuilder.String()
-				percent, speed, eta, status, destination := p.ParseLine(line)
-				if strings.Contains(line, "[download]") || percent > 0 || speed != "" || eta != "" {
-					sendProgress(percent, speed, eta, status, destination)
-				}
+				p.handleLine(lineBuilder.String(), sendProgress)
 				lineBuilder.Reset()
 			}
 
@@ -69,90 +144,220 @@ func (p *ProgressParser) ReadPipe(pipe io.Reader, sendProgress func(float64, str
 	}
 }
 
-func (p *ProgressParser) ParseLine(line string) (percent float64, speed, eta, status, destination string) {
-	p.currentDestination = ""
-	p.currentFormat = ""
+func (p *ProgressParser) handleLine(line string, sendProgress func(Progress)) {
+	if strings.Contains(line, "no such option: --progress-template") {
+		p.templateUnsupported.Store(true)
+	}
 
-	percentPatterns := []*regexp.Regexp{
-		regexp.MustCompile(`(\d+(?:\.\d+)?)\s*%`),
-		regexp.MustCompile(`\[download\]\s+(\d+(?:\.\d+)?)%`),
+	if IsNoSpaceError(line) {
+		p.noSpace.Store(true)
 	}
 
-	for _, pattern := range percentPatterns {
-		percentMatch := pattern.FindStringSubmatch(line)
-		if len(percentMatch) > 1 {
-			if pr, err := strconv.ParseFloat(percentMatch[1], 64); err == nil {
-				percent = pr
-				break
-			}
-		}
+	if progress, ok := p.ParseProgressLine(line); ok {
+		sendProgress(progress)
+	}
+}
+
+// ParseProgressLine parses a line of yt-dlp output. Lines from the progress
+// templates are decoded directly; anything else goes through ParseLine. It
+// reports false for lines that carry no progress information.
+func (p *ProgressParser) ParseProgressLine(line string) (Progress, bool) {
+	trimmed := strings.TrimSpace(line)
+	if rest, ok := strings.CutPrefix(trimmed, progressPrefix); ok {
+		return parseTemplateProgress(rest)
+	}
+
+	if rest, ok := strings.CutPrefix(trimmed, postprocessPrefix); ok {
+		name, _, _ := strings.Cut(rest, " ")
+		return Progress{Percent: 100, Stage: postprocessStage(name)}, true
+	}
+
+	if match := waitRegex.FindStringSubmatch(trimmed); len(match) > 1 {
+		return Progress{Status: WaitingStatus, Eta: match[1]}, true
+	}
+
+	// yt-dlp hands live streams to ffmpeg, which only reports the size and
+	// length of what it has recorded.
+	if match := ffmpegProgressRegex.FindStringSubmatch(trimmed); len(match) > 4 {
+		size, _ := strconv.ParseInt(match[1], 10, 64)
+		hours, _ := strconv.ParseFloat(match[2], 64)
+		minutes, _ := strconv.ParseFloat(match[3], 64)
+		seconds, _ := strconv.ParseFloat(match[4], 64)
+		return Progress{
+			Status:          "[download]",
+			DownloadedBytes: size * 1024,
+			Elapsed:         hours*3600 + minutes*60 + seconds,
+		}, true
 	}
 
-	speedPattern := regexp.MustCompile(`(\d+(?:\.\d+)?[KMG]?i?B/s)`)
-	speedMatch := speedPattern.FindStringSubmatch(line)
-	if len(speedMatch) > 1 {
-		speed = speedMatch[1]
+	percent, speed, eta, status, destination := p.ParseLine(line)
+	progress := Progress{
+		Percent:     percent,
+		Speed:       speed,
+		Eta:         eta,
+		Status:      status,
+		Destination: destination,
 	}
 
-	etaPattern := regexp.MustCompile(`ETA\s+(\d+:\d+(?::\d+)?)`)
-	etaMatch := etaPattern.FindStringSubmatch(line)
-	if len(etaMatch) > 1 {
-		eta = etaMatch[1]
+	if stage := lineStage(line); stage != "" {
+		progress.Percent = 100
+		progress.Stage = stage
+		return progress, true
 	}
 
-	if strings.Contains(line, "[download] Destination:") {
-		destPattern := regexp.MustCompile(`Destination:\s*(.+)`)
-		if match := destPattern.FindStringSubmatch(line); len(match) > 1 {
-			p.currentDestination = strings.TrimSpace(match[1])
+	ok := strings.Contains(line, "[download]") || percent > 0 || speed != "" || eta != "" || destination != ""
+	return progress, ok
+}
+
+// ParseLine scrapes progress from yt-dlp's default human-readable output.
+func (p *ProgressParser) ParseLine(line string) (percent float64, speed, eta, status, destination string) {
+	if match := percentRegex.FindStringSubmatch(line); len(match) > 1 {
+		if pr, err := strconv.ParseFloat(match[1], 64); err == nil {
+			percent = pr
 		}
+	}
+
+	if match := speedRegex.FindStringSubmatch(line); len(match) > 1 {
+		speed = match[1]
+	}
+
+	if match := etaRegex.FindStringSubmatch(line); len(match) > 1 {
+		eta = match[1]
+	}
 
-		if ext := extractFormatFromDestination(line); ext != "" {
-			p.currentFormat = ext
+	if strings.HasPrefix(strings.TrimSpace(line), "[") {
+		if match := mergeRegex.FindStringSubmatch(line); len(match) > 1 {
+			destination = strings.TrimSpace(match[1])
+		} else if match := subtitleRegex.FindStringSubmatch(line); len(match) > 1 {
+			destination = strings.TrimSpace(match[1])
+		} else if match := destinationRegex.FindStringSubmatch(line); len(match) > 1 {
+			destination = strings.TrimSpace(match[1])
 		}
 	}
 
-	formatPattern := regexp.MustCompile(`(?:format|format_id)\s+(\d+)`)
-	if match := formatPattern.FindStringSubmatch(line); len(match) > 1 {
-		p.currentFormat = "format " + match[1]
+	format := ""
+	if match := formatRegex.FindStringSubmatch(line); len(match) > 1 {
+		format = "format " + match[1]
 	}
 
 	if percent > 0 {
-		if p.currentFormat != "" {
-			status = "[download] " + p.currentFormat
+		if format != "" {
+			status = "[download] " + format
 		} else {
 			status = "[download]"
 		}
 	}
 
-	return percent, speed, eta, status, p.currentDestination
+	return percent, speed, eta, status, destination
 }
 
-func extractFormatFromDestination(line string) string {
-	videoExtensions := map[string]bool{
-		".mp4":  true,
-		".webm": true,
-		".mkv":  true,
+func parseTemplateProgress(rest string) (Progress, bool) {
+	fields := strings.SplitN(rest, " ", 4)
+	if len(fields) != 4 {
+		return Progress{}, false
 	}
-	audioExtensions := map[string]bool{
-		".m4a":  true,
-		".mp3":  true,
-		".ogg":  true,
-		".wav":  true,
-		".flac": true,
-		".aac":  true,
+
+	var tp templateProgress
+	if err := json.Unmarshal([]byte(fields[3]), &tp); err != nil {
+		log.Printf("Failed to parse progress template line: %v", err)
+		return Progress{}, false
 	}
 
-	for ext := range videoExtensions {
-		if strings.Contains(line, ext) {
-			return "video"
-		}
+	progress := Progress{
+		DownloadedBytes: int64(tp.DownloadedBytes),
+		TotalBytes:      int64(tp.TotalBytes),
+		FragmentIndex:   int(tp.FragmentIndex),
+		FragmentCount:   int(tp.FragmentCount),
+		Elapsed:         tp.Elapsed,
+		Destination:     tp.Filename,
+	}
+
+	if progress.TotalBytes == 0 {
+		progress.TotalBytes = int64(tp.TotalBytesEstimate)
+	}
+
+	switch {
+	case tp.Status == "finished":
+		progress.Percent = 100
+	case progress.TotalBytes > 0:
+		progress.Percent = float64(progress.DownloadedBytes) / float64(progress.TotalBytes) * 100
+	case progress.FragmentCount > 0:
+		progress.Percent = float64(progress.FragmentIndex) / float64(progress.FragmentCount) * 100
+	}
+
+	if tp.Speed != nil && *tp.Speed > 0 {
+		progress.Speed = bytesToHuman(*tp.Speed) + "/s"
+	}
+
+	if tp.ETA != nil {
+		progress.Eta = formatETA(int(*tp.ETA))
+	}
+
+	progress.Status = "[download]"
+	if label := streamLabel(fields[0], fields[1], fields[2]); label != "" {
+		progress.Status += " " + label
+	}
+
+	return progress, true
+}
+
+// streamLabel names the stream being downloaded from its format fields,
+// e.g. "video (137)" or "audio (140)".
+func streamLabel(formatID, vcodec, acodec string) string {
+	known := func(v string) bool { return v != "" && v != "NA" }
+	hasVideo := known(vcodec) && vcodec != "none"
+	hasAudio := known(acodec) && acodec != "none"
+
+	kind := ""
+	switch {
+	case hasVideo && hasAudio:
+		kind = "video+audio"
+	case hasVideo:
+		kind = "video"
+	case hasAudio:
+		kind = "audio"
+	}
+
+	switch {
+	case kind != "" && known(formatID):
+		return fmt.Sprintf("%s (%s)", kind, formatID)
+	case kind != "":
+		return kind
+	case known(formatID):
+		return "format " + formatID
 	}
 
-	for ext := range audioExtensions {
-		if strings.Contains(line, ext) {
-			return "audio"
+	return ""
+}
+
+// postprocessStage maps a postprocessor key such as "FFmpegMerger" to the
+// tag yt-dlp prints in front of its output.
+func postprocessStage(name string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(name, "FFmpeg"), "PP")
+}
+
+func lineStage(line string) string {
+	line = strings.TrimSpace(line)
+	for _, tag := range postprocessStageTags {
+		if strings.HasPrefix(line, "["+tag+"]") {
+			return tag
 		}
 	}
 
 	return ""
 }
+
+func formatETA(seconds int) string {
+	if seconds < 0 {
+		return ""
+	}
+
+	hours := seconds / 3600
+	minutes := (seconds % 3600) / 60
+	secs := seconds % 60
+	if hours > 0 {
+		return fmt.Sprintf("%02d:%02d:%02d", hours, minutes, secs)
+	}
+
+	return fmt.Sprintf("%02d:%02d", minutes, secs)
+}
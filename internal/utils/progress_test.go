@@ -1,6 +1,10 @@
 package utils
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/xdagiz/xytz/internal/types"
+)
 
 func TestProgressParser_ParseLine(t *testing.T) {
 	parser := NewProgressParser()
@@ -172,3 +176,133 @@ func TestProgressParser_ParseLine(t *testing.T) {
 		})
 	}
 }
+
+func TestProgressParser_ParseProgressLine(t *testing.T) {
+	parser := NewProgressParser()
+
+	tests := []struct {
+		name   string
+		line   string
+		wantOK bool
+		want   Progress
+	}{
+		{
+			name:   "template video stream",
+			line:   `xytz-progress:137 avc1.640028 none {"status": "downloading", "downloaded_bytes": 2621440, "total_bytes": 10485760, "speed": 1048576.0, "eta": 8, "filename": "/tmp/video.f137.mp4"}`,
+			wantOK: true,
+			want: Progress{
+				Percent:         25,
+				Speed:           "1.00 MiB/s",
+				Eta:             "00:08",
+				Status:          "[download] video (137)",
+				Destination:     "/tmp/video.f137.mp4",
+				DownloadedBytes: 2621440,
+				TotalBytes:      10485760,
+			},
+		},
+		{
+			name:   "template audio stream with estimate and fragments",
+			line:   `xytz-progress:140 none mp4a.40.2 {"status": "downloading", "downloaded_bytes": 500, "total_bytes_estimate": 1000.5, "speed": null, "eta": 3725, "fragment_index": 3, "fragment_count": 6}`,
+			wantOK: true,
+			want: Progress{
+				Percent:         50,
+				Eta:             "01:02:05",
+				Status:          "[download] audio (140)",
+				DownloadedBytes: 500,
+				TotalBytes:      1000,
+				FragmentIndex:   3,
+				FragmentCount:   6,
+			},
+		},
+		{
+			name:   "template finished with unknown codecs",
+			line:   `xytz-progress:18 NA NA {"status": "finished", "downloaded_bytes": 100, "total_bytes": 100, "filename": "/tmp/video.mp4"}`,
+			wantOK: true,
+			want: Progress{
+				Percent:         100,
+				Status:          "[download] format 18",
+				Destination:     "/tmp/video.mp4",
+				DownloadedBytes: 100,
+				TotalBytes:      100,
+			},
+		},
+		{
+			name:   "malformed template line",
+			line:   `xytz-progress:18 NA NA {not json`,
+			wantOK: false,
+		},
+		{
+			name:   "template merger",
+			line:   "xytz-postprocess:FFmpegMerger started",
+			wantOK: true,
+			want:   Progress{Percent: 100, Stage: types.StageMerger},
+		},
+		{
+			name:   "template post-processor",
+			line:   "xytz-postprocess:FFmpegExtractAudio processing",
+			wantOK: true,
+			want:   Progress{Percent: 100, Stage: types.StageExtractAudio},
+		},
+		{
+			name:   "fallback merger line",
+			line:   `[Merger] Merging formats into "/tmp/video.mp4"`,
+			wantOK: true,
+			want:   Progress{Percent: 100, Stage: types.StageMerger, Destination: "/tmp/video.mp4"},
+		},
+		{
+			name:   "fallback extract audio line",
+			line:   "[ExtractAudio] Destination: /tmp/audio.mp3",
+			wantOK: true,
+			want:   Progress{Percent: 100, Stage: types.StageExtractAudio, Destination: "/tmp/audio.mp3"},
+		},
+		{
+			name:   "fallback progress line",
+			line:   "[download] 10.5% of 50.00MiB at 2.50MiB/s ETA 00:20",
+			wantOK: true,
+			want:   Progress{Percent: 10.5, Speed: "2.50MiB/s", Eta: "00:20", Status: "[download]"},
+		},
+		{
+			name:   "template live recording",
+			line:   `xytz-progress:301 avc1.4d401f mp4a.40.2 {"status": "downloading", "downloaded_bytes": 2048, "elapsed": 95.5, "fragment_index": 12}`,
+			wantOK: true,
+			want: Progress{
+				Status:          "[download] video+audio (301)",
+				DownloadedBytes: 2048,
+				FragmentIndex:   12,
+				Elapsed:         95.5,
+			},
+		},
+		{
+			name:   "ffmpeg recording line",
+			line:   "frame= 2474 fps= 30 q=-1.0 size=   10240KiB time=00:01:23.45 bitrate=1005.1kbits/s speed=1.0x",
+			wantOK: true,
+			want:   Progress{Status: "[download]", DownloadedBytes: 10240 * 1024, Elapsed: 83.45},
+		},
+		{
+			name:   "waiting for an upcoming stream",
+			line:   "[wait] Remaining time until next attempt: 01:02:03",
+			wantOK: true,
+			want:   Progress{Status: WaitingStatus, Eta: "01:02:03"},
+		},
+		{
+			name:   "unrelated line",
+			line:   "[youtube] abc: Downloading webpage",
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parser.ParseProgressLine(tt.line)
+			if ok != tt.wantOK {
+				t.Fatalf("ParseProgressLine() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseProgressLine() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
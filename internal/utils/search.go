@@ -2,6 +2,7 @@ package utils
 
 import (
 	"bufio"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
@@ -15,51 +16,111 @@ import (
 	"github.com/xdagiz/xytz/internal/types"
 )
 
-func runYTDLPCommand(sm *SearchManager, ytDlpPath, searchURL string, searchLimit int, args []string) ([]list.Item, []string, int, string, bool) {
-	playlistItems := fmt.Sprintf("1:%d", searchLimit)
+// entryParser turns a line of yt-dlp's --dump-json output into a list item.
+type entryParser func(line string) (list.Item, error)
+
+func parseVideoEntry(line string) (list.Item, error)    { return ParseVideoItem(line) }
+func parseShortEntry(line string) (list.Item, error)    { return ParseShortItem(line) }
+func parsePlaylistEntry(line string) (list.Item, error) { return ParsePlaylistItem(line) }
+
+// parseSearchEntry parses a search result, which is a channel or a playlist
+// rather than a video when the results are filtered by type.
+func parseSearchEntry(line string) (list.Item, error) {
+	var entry struct {
+		URL string `json:"url"`
+	}
+	if err := json.Unmarshal([]byte(line), &entry); err == nil {
+		switch {
+		case strings.Contains(entry.URL, "/playlist?list="):
+			return ParsePlaylistItem(line)
+		case strings.Contains(entry.URL, "/channel/") || strings.Contains(entry.URL, "/@"):
+			return ParseChannelItem(line)
+		}
+	}
+
+	return ParseVideoItem(line)
+}
+
+// channelTabParser picks the parser for the entries of a channel tab, or of
+// search results and playlists when tab is empty.
+func channelTabParser(tab types.ChannelTab) entryParser {
+	switch {
+	case tab == "":
+		return parseSearchEntry
+	case tab == types.ChannelTabShorts:
+		return parseShortEntry
+	case tab.ListsPlaylists():
+		return parsePlaylistEntry
+	}
+
+	return parseVideoEntry
+}
+
+// searchRun is what one yt-dlp listing produced.
+type searchRun struct {
+	items []list.Item
+	// ends[i] is the number of entries yt-dlp had listed up to items[i].
+	ends             []int
+	entries          int
+	skippedLiveShort int
+	stderrLines      []string
+	failed           bool
+}
+
+// playlistItemsRange is the --playlist-items value for count entries from
+// start on, or for every entry from start when count is 0.
+func playlistItemsRange(start, count int) string {
+	if count <= 0 {
+		return fmt.Sprintf("%d:", start)
+	}
+
+	return fmt.Sprintf("%d:%d", start, start+count-1)
+}
+
+func runYTDLPCommand(sm *SearchManager, ytDlpPath, searchURL string, start, count int, args []string, parse entryParser) (searchRun, string, bool) {
+	var run searchRun
+
 	cmdArgs := append(append([]string{}, args...),
 		"--flat-playlist",
 		"--dump-json",
-		"--playlist-items", playlistItems,
+		"--playlist-items", playlistItemsRange(start, count),
 		searchURL,
 	)
 
 	cmd := exec.Command(ytDlpPath, cmdArgs...)
 
-	sm.SetCmd(cmd)
-
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
 		errMsg := fmt.Sprintf("failed to get stdout pipe: %v", err)
-		return nil, nil, 0, errMsg, false
+		return run, errMsg, false
 	}
 	defer stdout.Close()
 
 	stderr, err := cmd.StderrPipe()
 	if err != nil {
 		errMsg := fmt.Sprintf("failed to get stderr pipe: %v", err)
-		return nil, nil, 0, errMsg, false
+		return run, errMsg, false
 	}
 
 	defer stderr.Close()
 
 	if err := cmd.Start(); err != nil {
 		errMsg := fmt.Sprintf("failed to start search: %v", err)
-		return nil, nil, 0, errMsg, false
+		return run, errMsg, false
 	}
 
-	var videos []list.Item
-	skippedLiveShort := 0
+	// Only publish the command once Start has set its Process, which Cancel
+	// reads from another goroutine.
+	sm.SetCmd(cmd)
 
 	scanner := bufio.NewScanner(stdout)
 	stderrScanner := bufio.NewScanner(stderr)
-	stderrLines := []string{}
 
 	var stderrWg sync.WaitGroup
 	stderrWg.Go(func() {
 		for stderrScanner.Scan() {
 			line := stderrScanner.Text()
-			stderrLines = append(stderrLines, line)
+			run.stderrLines = append(run.stderrLines, line)
 			log.Printf("yt-dlp stderr: %s", line)
 		}
 	})
@@ -72,10 +133,11 @@ func runYTDLPCommand(sm *SearchManager, ytDlpPath, searchURL string, searchLimit
 			continue
 		}
 
-		videoItem, err := ParseVideoItem(trimmedLine)
+		run.entries++
+		videoItem, err := parse(trimmedLine)
 		if err != nil {
 			if errors.Is(err, ErrSkippedLiveShort) {
-				skippedLiveShort++
+				run.skippedLiveShort++
 				continue
 			}
 
@@ -83,7 +145,8 @@ func runYTDLPCommand(sm *SearchManager, ytDlpPath, searchURL string, searchLimit
 			continue
 		}
 
-		videos = append(videos, videoItem)
+		run.items = append(run.items, videoItem)
+		run.ends = append(run.ends, run.entries)
 	}
 
 	if err := scanner.Err(); err != nil {
@@ -94,17 +157,21 @@ func runYTDLPCommand(sm *SearchManager, ytDlpPath, searchURL string, searchLimit
 
 	if err := cmd.Wait(); err != nil {
 		log.Printf("yt-dlp command failed: %v", err)
-		log.Printf("stderr output: %v", stderrLines)
+		log.Printf("stderr output: %v", run.stderrLines)
+		run.failed = true
 	}
 
 	if sm.ClearAndCheckCanceled() {
-		return nil, nil, 0, "", true
+		return searchRun{}, "", true
 	}
 
-	return videos, stderrLines, skippedLiveShort, "", false
+	return run, "", false
 }
 
-func executeYTDLP(sm *SearchManager, searchURL string, searchLimit int, cookiesBrowser, cookiesFile string) any {
+// executeYTDLP lists searchLimit entries of searchURL from the playlist index
+// start on, or all of them when searchLimit is 0. extraArgs are passed on to
+// yt-dlp.
+func executeYTDLP(sm *SearchManager, searchURL string, start, searchLimit int, cookiesBrowser, cookiesFile string, parse entryParser, extraArgs ...string) any {
 	cfg, err := config.Load()
 	if err != nil {
 		log.Printf("Warning: Failed to load config, using defaults: %v", err)
@@ -141,37 +208,38 @@ func executeYTDLP(sm *SearchManager, searchURL string, searchLimit int, cookiesB
 	} else if cookiesFile != "" {
 		args = append(args, "--cookies", cookiesFile)
 	}
+	args = append(args, extraArgs...)
 
 	targetLimit := searchLimit
 	fetchLimit := searchLimit
-	var (
-		videos      []list.Item
-		stderrLines []string
-	)
+	var run searchRun
 
 	for range 4 {
-		var skippedLiveShort int
 		var errMsg string
 		var canceled bool
 
-		videos, stderrLines, skippedLiveShort, errMsg, canceled = runYTDLPCommand(sm, ytDlpPath, searchURL, fetchLimit, args)
+		run, errMsg, canceled = runYTDLPCommand(sm, ytDlpPath, searchURL, start, fetchLimit, args, parse)
 		if canceled {
 			return nil
 		}
 
 		if errMsg != "" {
-			return types.SearchResultMsg{Err: errMsg}
+			return types.SearchResultMsg{Err: errMsg, URL: searchURL}
 		}
 
-		if len(videos) >= targetLimit {
-			return types.SearchResultMsg{Videos: videos[:targetLimit]}
+		if targetLimit > 0 && len(run.items) >= targetLimit {
+			return types.SearchResultMsg{
+				Videos: run.items[:targetLimit],
+				URL:    searchURL,
+				Next:   start + run.ends[targetLimit-1],
+			}
 		}
 
-		if skippedLiveShort == 0 {
+		if targetLimit == 0 || run.skippedLiveShort == 0 {
 			break
 		}
 
-		nextLimit := targetLimit + skippedLiveShort
+		nextLimit := targetLimit + run.skippedLiveShort
 		if nextLimit <= fetchLimit {
 			break
 		}
@@ -179,6 +247,15 @@ func executeYTDLP(sm *SearchManager, searchURL string, searchLimit int, cookiesB
 		fetchLimit = nextLimit
 	}
 
+	videos := run.items
+	stderrLines := run.stderrLines
+
+	// yt-dlp stops early once the list runs out.
+	next := 0
+	if fetchLimit > 0 && run.entries >= fetchLimit {
+		next = start + run.entries
+	}
+
 	var errMsg string
 	if len(videos) == 0 {
 		for _, line := range stderrLines {
@@ -197,13 +274,25 @@ func executeYTDLP(sm *SearchManager, searchURL string, searchLimit int, cookiesB
 			}
 		}
 
-		return types.SearchResultMsg{Err: errMsg}
+		// Fall back to yt-dlp's own message for failures not classified above.
+		if errMsg == "" && run.failed {
+			errMsg = "yt-dlp failed"
+			for _, line := range stderrLines {
+				if msg, ok := strings.CutPrefix(line, "ERROR: "); ok {
+					errMsg = msg
+				}
+			}
+		}
+
+		return types.SearchResultMsg{Err: errMsg, URL: searchURL}
 	} else {
-		return types.SearchResultMsg{Videos: videos}
+		return types.SearchResultMsg{Videos: videos, URL: searchURL, Next: next}
 	}
 }
 
-func PerformSearch(sm *SearchManager, query, sortParam string, searchLimit int, cookiesBrowser, cookiesFile string) tea.Cmd {
+// PerformSearch lists the results of a query, sorted and filtered by the
+// sp parameter from types.SearchParam.
+func PerformSearch(sm *SearchManager, query, searchParam string, searchLimit int, cookiesBrowser, cookiesFile string) tea.Cmd {
 	return tea.Cmd(func() tea.Msg {
 		query = strings.TrimSpace(query)
 
@@ -212,21 +301,61 @@ func PerformSearch(sm *SearchManager, query, sortParam string, searchLimit int,
 			return types.StartFormatMsg{URL: url}
 		}
 
-		return executeYTDLP(sm, url, searchLimit, cookiesBrowser, cookiesFile)
+		if urlType == "search" {
+			return executeYTDLP(sm, AddSearchParam(url, searchParam), 1, searchLimit, cookiesBrowser, cookiesFile, parseSearchEntry)
+		}
+
+		return executeYTDLP(sm, url, 1, searchLimit, cookiesBrowser, cookiesFile, parseVideoEntry)
 	})
 }
 
 func PerformChannelSearch(sm *SearchManager, input string, searchLimit int, cookiesBrowser, cookiesFile string) tea.Cmd {
 	return tea.Cmd(func() tea.Msg {
 		channelURL := BuildChannelURL(input)
-		return executeYTDLP(sm, channelURL, searchLimit, cookiesBrowser, cookiesFile)
+		return executeYTDLP(sm, channelURL, 1, searchLimit, cookiesBrowser, cookiesFile, parseVideoEntry)
+	})
+}
+
+// PerformChannelTabSearch lists the entries of one tab of a channel.
+func PerformChannelTabSearch(sm *SearchManager, input string, tab types.ChannelTab, searchLimit int, cookiesBrowser, cookiesFile string) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		tabURL := BuildChannelTabURL(input, tab)
+		result := executeYTDLP(sm, tabURL, 1, searchLimit, cookiesBrowser, cookiesFile, channelTabParser(tab))
+		if result == nil {
+			return nil
+		}
+
+		msg, ok := result.(types.SearchResultMsg)
+		if !ok {
+			return result
+		}
+
+		return types.ChannelTabResultMsg{Tab: tab, Items: msg.Videos, URL: msg.URL, Next: msg.Next, Err: msg.Err}
 	})
 }
 
 func PerformPlaylistSearch(sm *SearchManager, query string, searchLimit int, cookiesBrowser, cookiesFile string) tea.Cmd {
 	return tea.Cmd(func() tea.Msg {
 		playlistURL := BuildPlaylistURL(query)
-		return executeYTDLP(sm, playlistURL, searchLimit, cookiesBrowser, cookiesFile)
+		return executeYTDLP(sm, playlistURL, 1, searchLimit, cookiesBrowser, cookiesFile, parseVideoEntry)
+	})
+}
+
+// PerformLoadMore lists the next page of searchURL from the playlist index
+// start on. A limit of 0 lists everything that's left.
+func PerformLoadMore(sm *SearchManager, searchURL string, tab types.ChannelTab, start, limit int, cookiesBrowser, cookiesFile string) tea.Cmd {
+	return tea.Cmd(func() tea.Msg {
+		result := executeYTDLP(sm, searchURL, start, limit, cookiesBrowser, cookiesFile, channelTabParser(tab))
+		if result == nil {
+			return nil
+		}
+
+		msg, ok := result.(types.SearchResultMsg)
+		if !ok {
+			return result
+		}
+
+		return types.MoreResultsMsg{URL: searchURL, Items: msg.Videos, Next: msg.Next, Err: msg.Err}
 	})
 }
 
@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/xdagiz/xytz/internal/config"
+	"github.com/xdagiz/xytz/internal/types"
+)
+
+// setupFakeSearch points the config at a fake yt-dlp that lists total
+// entries and honours --playlist-items START:END and START: ranges.
+func setupFakeSearch(t *testing.T, total int) {
+	t.Helper()
+
+	origConfigDir := config.GetConfigDir
+	configDir := filepath.Join(t.TempDir(), "config")
+	config.GetConfigDir = func() string { return configDir }
+	t.Cleanup(func() { config.GetConfigDir = origConfigDir })
+
+	script := `#!/usr/bin/env bash
+[ "$1" = "--version" ] && exit 0
+while [ $# -gt 0 ]; do
+  if [ "$1" = "--playlist-items" ]; then range="$2"; fi
+  shift
+done
+start="${range%%:*}"
+end="${range#*:}"
+[ -z "$end" ] && end=TOTAL
+for ((i = start; i <= end && i <= TOTAL; i++)); do
+  echo "{\"id\":\"v$i\",\"title\":\"Video $i\",\"duration\":60}"
+done
+`
+	script = strings.ReplaceAll(script, "TOTAL", strconv.Itoa(total))
+
+	cfg := config.GetDefault()
+	cfg.YTDLPPath = makeExecutable(t, "fake-yt-dlp.sh", script)
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+}
+
+func TestPlaylistItemsRange(t *testing.T) {
+	if got := playlistItemsRange(1, 25); got != "1:25" {
+		t.Errorf("playlistItemsRange(1, 25) = %q, want 1:25", got)
+	}
+	if got := playlistItemsRange(26, 0); got != "26:" {
+		t.Errorf("playlistItemsRange(26, 0) = %q, want 26:", got)
+	}
+}
+
+func TestExecuteYTDLPPages(t *testing.T) {
+	setupFakeSearch(t, 5)
+	const url = "https://www.youtube.com/playlist?list=PL1"
+
+	tests := []struct {
+		name      string
+		start     int
+		limit     int
+		wantFirst string
+		wantCount int
+		wantNext  int
+	}{
+		{name: "first page", start: 1, limit: 2, wantFirst: "v1", wantCount: 2, wantNext: 3},
+		{name: "page ending on the last entry", start: 4, limit: 2, wantFirst: "v4", wantCount: 2, wantNext: 6},
+		{name: "past the end", start: 4, limit: 3, wantFirst: "v4", wantCount: 2, wantNext: 0},
+		{name: "all remaining", start: 2, limit: 0, wantFirst: "v2", wantCount: 4, wantNext: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := executeYTDLP(NewSearchManager(), url, tt.start, tt.limit, "", "", parseVideoEntry)
+			msg, ok := result.(types.SearchResultMsg)
+			if !ok {
+				t.Fatalf("result type = %T, want types.SearchResultMsg", result)
+			}
+			if msg.Err != "" {
+				t.Fatalf("Err = %q", msg.Err)
+			}
+			if len(msg.Videos) != tt.wantCount || msg.Next != tt.wantNext || msg.URL != url {
+				t.Fatalf("got %d videos, Next %d, URL %q; want %d, %d, %q", len(msg.Videos), msg.Next, msg.URL, tt.wantCount, tt.wantNext, url)
+			}
+			if first := msg.Videos[0].(types.VideoItem).ID; first != tt.wantFirst {
+				t.Fatalf("first video = %q, want %q", first, tt.wantFirst)
+			}
+		})
+	}
+}
+
+func TestParseSearchEntry(t *testing.T) {
+	tests := []struct {
+		line string
+		want any
+	}{
+		{`{"id":"abc","title":"Video","url":"https://www.youtube.com/watch?v=abc","duration":60}`, types.VideoItem{}},
+		{`{"id":"PL1","title":"Mixes","url":"https://www.youtube.com/playlist?list=PL1"}`, types.PlaylistItem{}},
+		{`{"id":"UCabc","title":"Channel","url":"https://www.youtube.com/channel/UCabc"}`, types.ChannelItem{}},
+	}
+
+	for _, tt := range tests {
+		item, err := parseSearchEntry(tt.line)
+		if err != nil {
+			t.Fatalf("parseSearchEntry(%s) error = %v", tt.line, err)
+		}
+		if fmt.Sprintf("%T", item) != fmt.Sprintf("%T", tt.want) {
+			t.Errorf("parseSearchEntry(%s) = %T, want %T", tt.line, item, tt.want)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package utils
+
+import (
+	"errors"
+	"log"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/xdagiz/xytz/internal/paths"
+	"github.com/xdagiz/xytz/internal/store"
+)
+
+var (
+	ErrAlreadySubscribed = errors.New("already subscribed")
+	ErrNotSubscribed     = errors.New("not subscribed")
+)
+
+const SubscriptionsFileName = "subscriptions.json"
+
+type Subscription struct {
+	// Channel is the channel's username or ID, as ExtractChannelUsername
+	// returns it.
+	Channel      string    `json:"channel"`
+	SubscribedAt time.Time `json:"subscribed_at"`
+	// LastChecked is when the feed last listed the channel's uploads.
+	LastChecked time.Time `json:"last_checked,omitzero"`
+}
+
+// Since is the time uploads have to be newer than to count as new.
+func (s Subscription) Since() time.Time {
+	if s.LastChecked.IsZero() {
+		return s.SubscribedAt
+	}
+
+	return s.LastChecked
+}
+
+var GetSubscriptionsFilePath = func() string {
+	dataDir := paths.GetDataDir()
+	if err := paths.EnsureDirExists(dataDir); err != nil {
+		log.Printf("Warning: Could not create data directory: %v", err)
+		return SubscriptionsFileName
+	}
+
+	return filepath.Join(dataDir, SubscriptionsFileName)
+}
+
+func LoadSubscriptions() ([]Subscription, error) {
+	var subs []Subscription
+	if err := store.LoadJSON(GetSubscriptionsFilePath(), &subs); err != nil {
+		return nil, err
+	}
+
+	if subs == nil {
+		return []Subscription{}, nil
+	}
+
+	return subs, nil
+}
+
+// updateSubscriptions applies fn to the subscriptions under the file lock.
+func updateSubscriptions(fn func([]Subscription) ([]Subscription, error)) error {
+	var subs []Subscription
+	return store.UpdateJSON(GetSubscriptionsFilePath(), &subs, func() error {
+		var err error
+		subs, err = fn(subs)
+		if subs == nil {
+			subs = []Subscription{}
+		}
+
+		return err
+	})
+}
+
+func sameChannel(a, b string) bool {
+	return strings.EqualFold(a, b)
+}
+
+// AddSubscription subscribes to a channel given as @username, ID or URL
+// and returns the channel name it was stored under.
+func AddSubscription(input string) (string, error) {
+	channel := ExtractChannelUsername(input)
+	if channel == "" {
+		return "", errors.New("empty channel name")
+	}
+
+	err := updateSubscriptions(func(subs []Subscription) ([]Subscription, error) {
+		if slices.ContainsFunc(subs, func(s Subscription) bool { return sameChannel(s.Channel, channel) }) {
+			return subs, ErrAlreadySubscribed
+		}
+
+		return append(subs, Subscription{Channel: channel, SubscribedAt: time.Now()}), nil
+	})
+
+	return channel, err
+}
+
+// RemoveSubscription unsubscribes from a channel given as @username, ID or
+// URL and returns the channel name it was stored under.
+func RemoveSubscription(input string) (string, error) {
+	channel := ExtractChannelUsername(input)
+
+	err := updateSubscriptions(func(subs []Subscription) ([]Subscription, error) {
+		i := slices.IndexFunc(subs, func(s Subscription) bool { return sameChannel(s.Channel, channel) })
+		if i < 0 {
+			return subs, ErrNotSubscribed
+		}
+
+		channel = subs[i].Channel
+		return slices.Delete(subs, i, i+1), nil
+	})
+
+	return channel, err
+}
+
+// MarkSubscriptionsChecked sets the last check of the given channels.
+func MarkSubscriptionsChecked(channels []string, checked time.Time) error {
+	return updateSubscriptions(func(subs []Subscription) ([]Subscription, error) {
+		for i := range subs {
+			if slices.ContainsFunc(channels, func(c string) bool { return sameChannel(c, subs[i].Channel) }) {
+				subs[i].LastChecked = checked
+			}
+		}
+
+		return subs, nil
+	})
+}
@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func setupSubscriptionsFilePath(t *testing.T) {
+	t.Helper()
+
+	orig := GetSubscriptionsFilePath
+	path := filepath.Join(t.TempDir(), "subscriptions.json")
+	GetSubscriptionsFilePath = func() string { return path }
+	t.Cleanup(func() {
+		GetSubscriptionsFilePath = orig
+	})
+}
+
+func TestSubscriptionsAddRemove(t *testing.T) {
+	setupSubscriptionsFilePath(t)
+
+	for _, input := range []string{"@gopher", "https://www.youtube.com/@rustacean/videos"} {
+		if _, err := AddSubscription(input); err != nil {
+			t.Fatalf("AddSubscription(%q) error = %v", input, err)
+		}
+	}
+
+	if channel, err := AddSubscription("@Gopher"); !errors.Is(err, ErrAlreadySubscribed) || channel != "Gopher" {
+		t.Fatalf("AddSubscription(@Gopher) = %q, %v, want ErrAlreadySubscribed", channel, err)
+	}
+
+	subs, err := LoadSubscriptions()
+	if err != nil {
+		t.Fatalf("LoadSubscriptions() error = %v", err)
+	}
+	if len(subs) != 2 || subs[0].Channel != "gopher" || subs[1].Channel != "rustacean" {
+		t.Fatalf("subscriptions = %+v", subs)
+	}
+
+	if channel, err := RemoveSubscription("@GOPHER"); err != nil || channel != "gopher" {
+		t.Fatalf("RemoveSubscription() = %q, %v", channel, err)
+	}
+	if _, err := RemoveSubscription("@gopher"); !errors.Is(err, ErrNotSubscribed) {
+		t.Fatalf("RemoveSubscription() error = %v, want ErrNotSubscribed", err)
+	}
+
+	subs, _ = LoadSubscriptions()
+	if len(subs) != 1 || subs[0].Channel != "rustacean" {
+		t.Fatalf("subscriptions after remove = %+v", subs)
+	}
+}
+
+func TestMarkSubscriptionsChecked(t *testing.T) {
+	setupSubscriptionsFilePath(t)
+
+	AddSubscription("@gopher")
+	AddSubscription("@rustacean")
+
+	subs, _ := LoadSubscriptions()
+	if !subs[0].Since().Equal(subs[0].SubscribedAt) {
+		t.Fatalf("Since() = %v, want the subscription time before the first check", subs[0].Since())
+	}
+
+	checked := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	if err := MarkSubscriptionsChecked([]string{"gopher"}, checked); err != nil {
+		t.Fatalf("MarkSubscriptionsChecked() error = %v", err)
+	}
+
+	subs, _ = LoadSubscriptions()
+	if !subs[0].LastChecked.Equal(checked) || !subs[0].Since().Equal(checked) {
+		t.Fatalf("gopher LastChecked = %v, want %v", subs[0].LastChecked, checked)
+	}
+	if !subs[1].LastChecked.IsZero() {
+		t.Fatalf("rustacean LastChecked = %v, want zero", subs[1].LastChecked)
+	}
+}
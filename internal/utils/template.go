@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/xdagiz/xytz/internal/config"
+	"github.com/xdagiz/xytz/internal/types"
+)
+
+// templateFieldReplacer swaps characters that aren't allowed in file names
+// for the lookalikes yt-dlp uses.
+var templateFieldReplacer = strings.NewReplacer(
+	"/", "⧸", "\\", "⧹", ":", "：", "*", "＊", "?", "？",
+	"\"", "＂", "<", "＜", ">", "＞", "|", "｜",
+)
+
+// OutputTemplateFields returns the yt-dlp template fields xytz knows about
+// before a download starts.
+func OutputTemplateFields(video types.VideoItem, ext string) map[string]string {
+	fields := map[string]string{
+		"id":          video.ID,
+		"title":       video.VideoTitle,
+		"channel":     video.Channel,
+		"uploader":    video.Channel,
+		"upload_date": video.UploadDate,
+		"ext":         ext,
+	}
+
+	if video.Duration > 0 {
+		fields["duration"] = strconv.Itoa(int(video.Duration))
+		fields["duration_string"] = FormatDuration(video.Duration)
+	}
+
+	return fields
+}
+
+// ResolveOutputTemplate fills a yt-dlp output template for previews. Fields
+// that are unknown until download time resolve to "NA" like yt-dlp does.
+func ResolveOutputTemplate(tmpl string, fields map[string]string) string {
+	parts := strings.Split(tmpl, "%%")
+	for i, part := range parts {
+		parts[i] = config.OutputTemplateFieldRegex.ReplaceAllStringFunc(part, func(match string) string {
+			sub := config.OutputTemplateFieldRegex.FindStringSubmatch(match)
+			value := resolveTemplateField(sub[1], fields)
+
+			if sub[2] != "" {
+				if n, err := strconv.Atoi(sub[2]); err == nil && len([]rune(value)) > n {
+					value = string([]rune(value)[:n])
+				}
+			}
+
+			return value
+		})
+	}
+
+	return strings.Join(parts, "%")
+}
+
+func resolveTemplateField(expr string, fields map[string]string) string {
+	name, fallback, hasFallback := strings.Cut(expr, "|")
+	if !hasFallback {
+		fallback = "NA"
+	}
+
+	for _, alt := range strings.Split(name, ",") {
+		alt, _, _ = strings.Cut(alt, ">")
+		alt, _, _ = strings.Cut(alt, "&")
+		if value := fields[strings.TrimSpace(alt)]; value != "" {
+			return sanitizeTemplateField(value)
+		}
+	}
+
+	return fallback
+}
+
+func sanitizeTemplateField(value string) string {
+	value = strings.Map(func(r rune) rune {
+		if r < 32 || r == 127 {
+			return -1
+		}
+
+		return r
+	}, value)
+
+	return templateFieldReplacer.Replace(value)
+}
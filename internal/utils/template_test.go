@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/xdagiz/xytz/internal/types"
+)
+
+func TestResolveOutputTemplate(t *testing.T) {
+	video := types.VideoItem{
+		ID:         "abc123",
+		VideoTitle: "AC/DC Live",
+		Channel:    "Rock Channel",
+		UploadDate: "20240102",
+	}
+	fields := OutputTemplateFields(video, "mp4")
+
+	tests := []struct {
+		name string
+		tmpl string
+		want string
+	}{
+		{
+			name: "team template",
+			tmpl: "%(upload_date)s - %(channel)s - %(title)s [%(id)s].%(ext)s",
+			want: "20240102 - Rock Channel - AC⧸DC Live [abc123].mp4",
+		},
+		{name: "unknown field", tmpl: "%(artist)s - %(title)s.%(ext)s", want: "NA - AC⧸DC Live.mp4"},
+		{name: "fallback value", tmpl: "%(artist|Unknown)s.%(ext)s", want: "Unknown.mp4"},
+		{name: "alternatives", tmpl: "%(artist,channel)s.%(ext)s", want: "Rock Channel.mp4"},
+		{name: "precision", tmpl: "%(title).2s.%(ext)s", want: "AC.mp4"},
+		{name: "literal percent", tmpl: "100%% %(id)s.%(ext)s", want: "100% abc123.mp4"},
+		{name: "subdirectory", tmpl: "%(channel)s/%(title)s.%(ext)s", want: "Rock Channel/AC⧸DC Live.mp4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ResolveOutputTemplate(tt.tmpl, fields); got != tt.want {
+				t.Errorf("ResolveOutputTemplate(%q) = %q, want %q", tt.tmpl, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveOutputTemplateSanitizesLikeYTDLP(t *testing.T) {
+	fields := OutputTemplateFields(types.VideoItem{VideoTitle: "Why? \"Live\": A|B <1> *\tC\\D"}, "jpg")
+
+	want := "Why？ ＂Live＂： A｜B ＜1＞ ＊C⧹D.jpg"
+	if got := ResolveOutputTemplate("%(title)s.%(ext)s", fields); got != want {
+		t.Fatalf("ResolveOutputTemplate() = %q, want %q", got, want)
+	}
+}
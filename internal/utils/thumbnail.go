@@ -0,0 +1,219 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/xdagiz/xytz/internal/config"
+	"github.com/xdagiz/xytz/internal/types"
+)
+
+var thumbnailClient = &http.Client{Timeout: 30 * time.Second}
+
+func doThumbnailDownload(dm *DownloadManager, program Sender, req types.DownloadRequest, cfg *config.Config) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	dm.SetItemContext(req.QueueIndex, ctx, cancel)
+
+	destination, err := downloadThumbnail(ctx, req, cfg, func(downloaded, total int64) {
+		percent := 0.0
+		if total > 0 {
+			percent = float64(downloaded) / float64(total) * 100
+		}
+
+		program.Send(types.ProgressMsg{
+			Percent:         percent,
+			Status:          "[download] thumbnail",
+			DownloadedBytes: downloaded,
+			TotalBytes:      total,
+			QueueIndex:      req.QueueIndex,
+			QueueTotal:      req.QueueTotal,
+			Title:           req.Title,
+		})
+	})
+	dm.ClearItem(req.QueueIndex, ctx)
+
+	switch {
+	case ctx.Err() == context.Canceled:
+		program.Send(types.DownloadResultMsg{Err: "Download cancelled", QueueIndex: req.QueueIndex, QueueTotal: req.QueueTotal})
+	case err != nil:
+		errMsg := fmt.Sprintf("Thumbnail error: %v", err)
+		log.Print(errMsg)
+		program.Send(types.DownloadResultMsg{Err: errMsg, Destination: destination, QueueIndex: req.QueueIndex, QueueTotal: req.QueueTotal})
+	default:
+		program.Send(types.DownloadResultMsg{
+			Output:      "Download complete",
+			Destination: destination,
+			QueueIndex:  req.QueueIndex,
+			QueueTotal:  req.QueueTotal,
+		})
+	}
+}
+
+// downloadThumbnail saves the requested thumbnail next to where the video
+// would go, converting it to the configured thumbnail format with ffmpeg.
+// On a failed conversion it returns the unconverted file with the error.
+func downloadThumbnail(ctx context.Context, req types.DownloadRequest, cfg *config.Config, progress func(downloaded, total int64)) (string, error) {
+	downloadPath := req.OutputDir
+	if downloadPath == "" {
+		downloadPath = cfg.GetDownloadPath()
+	}
+
+	if err := os.MkdirAll(downloadPath, 0o755); err != nil {
+		return "", err
+	}
+
+	srcExt := thumbnailExt(req.Thumbnail.URL)
+	video := types.VideoItem{ID: req.URL, VideoTitle: req.Title}
+	if len(req.Videos) > 0 {
+		video = req.Videos[0]
+	}
+
+	name := ResolveOutputTemplate(cfg.VideoOutputTemplate, OutputTemplateFields(video, srcExt))
+	src := filepath.Join(downloadPath, name)
+	if err := os.MkdirAll(filepath.Dir(src), 0o755); err != nil {
+		return "", err
+	}
+
+	if err := fetchThumbnail(ctx, req.Thumbnail.URL, src, progress); err != nil {
+		return "", err
+	}
+
+	format := cfg.ThumbnailFormat
+	if format == "" || format == srcExt {
+		return src, nil
+	}
+
+	dst := strings.TrimSuffix(src, filepath.Ext(src)) + "." + format
+	if err := convertThumbnail(ctx, cfg.FFmpegPath, src, dst); err != nil {
+		return src, err
+	}
+
+	if err := os.Remove(src); err != nil {
+		log.Printf("Failed to remove unconverted thumbnail: %v", err)
+	}
+
+	return dst, nil
+}
+
+func fetchThumbnail(ctx context.Context, url, dst string, progress func(downloaded, total int64)) error {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := thumbnailClient.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+
+	counter := &progressWriter{total: resp.ContentLength, report: progress}
+	if _, err := io.Copy(io.MultiWriter(f, counter), resp.Body); err != nil {
+		f.Close()
+		os.Remove(dst)
+		return err
+	}
+
+	counter.flush()
+	return f.Close()
+}
+
+func convertThumbnail(ctx context.Context, ffmpegPath, src, dst string) error {
+	if ffmpegPath == "" {
+		ffmpegPath = "ffmpeg"
+	}
+
+	out, err := exec.CommandContext(ctx, ffmpegPath, "-y", "-loglevel", "error", "-i", src, dst).CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("converting thumbnail: %w: %s", err, msg)
+		}
+
+		return fmt.Errorf("converting thumbnail: %w", err)
+	}
+
+	if _, err := os.Stat(dst); err != nil {
+		return errors.New("converting thumbnail: ffmpeg produced no file")
+	}
+
+	return nil
+}
+
+// thumbnailExt reads the image type from a thumbnail URL, e.g.
+// ".../maxresdefault.webp?v=1" gives "webp".
+func thumbnailExt(url string) string {
+	url, _, _ = strings.Cut(url, "?")
+	ext := strings.ToLower(strings.TrimPrefix(path.Ext(url), "."))
+	switch ext {
+	case "jpeg":
+		return "jpg"
+	case "":
+		return "jpg"
+	}
+
+	return ext
+}
+
+// progressWriter counts the bytes written through it and reports them on
+// every whole percent, or every progressInterval when the size is unknown.
+type progressWriter struct {
+	written  int64
+	total    int64
+	report   func(downloaded, total int64)
+	reported int64
+	percent  int64
+	last     time.Time
+}
+
+const progressInterval = 100 * time.Millisecond
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	w.written += int64(len(p))
+	if w.report == nil {
+		return len(p), nil
+	}
+
+	if w.total > 0 {
+		if percent := w.written * 100 / w.total; percent != w.percent {
+			w.percent = percent
+			w.send()
+		}
+	} else if time.Since(w.last) >= progressInterval {
+		w.send()
+	}
+
+	return len(p), nil
+}
+
+// flush reports the bytes written since the last report.
+func (w *progressWriter) flush() {
+	if w.report != nil && w.written != w.reported {
+		w.send()
+	}
+}
+
+func (w *progressWriter) send() {
+	w.reported = w.written
+	w.last = time.Now()
+	w.report(w.written, w.total)
+}
@@ -0,0 +1,124 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/xdagiz/xytz/internal/config"
+	"github.com/xdagiz/xytz/internal/types"
+)
+
+func newThumbnailServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/vi_webp/abc/maxresdefault.webp" {
+			http.NotFound(w, r)
+			return
+		}
+
+		_, _ = w.Write([]byte("RIFF-fake-webp"))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func thumbnailRequest(url string) types.DownloadRequest {
+	return types.DownloadRequest{
+		URL:       "https://www.youtube.com/watch?v=abc",
+		Title:     "Video",
+		Videos:    []types.VideoItem{{ID: "abc", VideoTitle: "Video"}},
+		Thumbnail: types.ThumbnailItem{ID: "2", URL: url, Width: 1280, Height: 720},
+	}
+}
+
+func TestDownloadThumbnailKeepsMatchingFormat(t *testing.T) {
+	srv := newThumbnailServer(t)
+
+	cfg := config.GetDefault()
+	cfg.DefaultDownloadPath = t.TempDir()
+	cfg.ThumbnailFormat = config.ThumbnailWEBP
+
+	var reported int64
+	dest, err := downloadThumbnail(context.Background(), thumbnailRequest(srv.URL+"/vi_webp/abc/maxresdefault.webp?v=1"), cfg, func(downloaded, total int64) {
+		reported = downloaded
+	})
+	if err != nil {
+		t.Fatalf("downloadThumbnail error: %v", err)
+	}
+
+	want := filepath.Join(cfg.DefaultDownloadPath, "Video.webp")
+	if dest != want {
+		t.Fatalf("destination = %q, want %q", dest, want)
+	}
+
+	data, err := os.ReadFile(dest)
+	if err != nil || string(data) != "RIFF-fake-webp" {
+		t.Fatalf("thumbnail file = %q, %v", data, err)
+	}
+	if reported != int64(len(data)) {
+		t.Fatalf("reported %d bytes, want %d", reported, len(data))
+	}
+}
+
+func TestDownloadThumbnailConvertsWithFFmpeg(t *testing.T) {
+	srv := newThumbnailServer(t)
+
+	cfg := config.GetDefault()
+	cfg.DefaultDownloadPath = t.TempDir()
+	cfg.ThumbnailFormat = config.ThumbnailPNG
+	cfg.FFmpegPath = makeExecutable(t, "fake-ffmpeg.sh", "#!/usr/bin/env bash\ncp \"$5\" \"$6\"\n")
+
+	dest, err := downloadThumbnail(context.Background(), thumbnailRequest(srv.URL+"/vi_webp/abc/maxresdefault.webp"), cfg, nil)
+	if err != nil {
+		t.Fatalf("downloadThumbnail error: %v", err)
+	}
+
+	want := filepath.Join(cfg.DefaultDownloadPath, "Video.png")
+	if dest != want {
+		t.Fatalf("destination = %q, want %q", dest, want)
+	}
+	if _, err := os.Stat(dest); err != nil {
+		t.Fatalf("converted thumbnail missing: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(cfg.DefaultDownloadPath, "Video.webp")); !os.IsNotExist(err) {
+		t.Fatalf("expected the unconverted thumbnail to be removed, stat err = %v", err)
+	}
+}
+
+func TestDownloadThumbnailReportsHTTPErrors(t *testing.T) {
+	srv := newThumbnailServer(t)
+
+	cfg := config.GetDefault()
+	cfg.DefaultDownloadPath = t.TempDir()
+
+	if _, err := downloadThumbnail(context.Background(), thumbnailRequest(srv.URL+"/missing.jpg"), cfg, nil); err == nil {
+		t.Fatalf("expected an error for a missing thumbnail")
+	}
+
+	entries, _ := os.ReadDir(cfg.DefaultDownloadPath)
+	if len(entries) != 0 {
+		t.Fatalf("expected no files after a failed download, got %d", len(entries))
+	}
+}
+
+func TestProgressWriterReportsWholePercents(t *testing.T) {
+	var reports []int64
+	w := &progressWriter{total: 1000, report: func(downloaded, total int64) {
+		reports = append(reports, downloaded)
+	}}
+
+	for range 200 {
+		w.Write(make([]byte, 5))
+	}
+	w.flush()
+
+	if len(reports) != 100 || reports[len(reports)-1] != 1000 {
+		t.Fatalf("got %d reports ending at %d, want 100 ending at 1000", len(reports), reports[len(reports)-1])
+	}
+}
@@ -1,30 +1,115 @@
 package utils
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
-	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/xdagiz/xytz/internal/paths"
+	"github.com/xdagiz/xytz/internal/store"
 	"github.com/xdagiz/xytz/internal/types"
 )
 
-var ErrInvalidUnfinishedDownload = errors.New("unfinished download must have valid URL and title")
+var (
+	ErrInvalidUnfinishedDownload = errors.New("unfinished download must have valid URL and title")
+	ErrUnfinishedVersion         = errors.New("unfinished downloads file was written by a newer version of xytz")
+)
 
-const UnfinishedFileName = ".xytz_unfinished.json"
+const (
+	UnfinishedFileName = ".xytz_unfinished.json"
+	// UnfinishedSchemaVersion is the version of the unfinished downloads
+	// file. Version 1 files were a bare JSON array of downloads.
+	UnfinishedSchemaVersion = 2
+)
 
+// UnfinishedDownload holds everything needed to restart a download or a
+// queue the way it was started. Items lists every video with its status,
+// so a resumed queue keeps the ones that already finished.
 type UnfinishedDownload struct {
-	URL       string            `json:"url"`
-	FormatID  string            `json:"format_id"`
-	Title     string            `json:"title"`
-	Desc      string            `json:"desc,omitempty"`
-	URLs      []string          `json:"urls,omitempty"`
-	Videos    []types.VideoItem `json:"videos,omitempty"`
-	Timestamp time.Time         `json:"timestamp"`
+	URL                string                `json:"url"`
+	FormatID           string                `json:"format_id"`
+	Title              string                `json:"title"`
+	Desc               string                `json:"desc,omitempty"`
+	IsAudio            bool                  `json:"is_audio,omitempty"`
+	ABR                float64               `json:"abr,omitempty"`
+	Options            []UnfinishedOption    `json:"options,omitempty"`
+	CookiesFromBrowser string                `json:"cookies_from_browser,omitempty"`
+	Cookies            string                `json:"cookies,omitempty"`
+	Clip               types.ClipRange       `json:"clip,omitzero"`
+	Subtitles          types.SubtitleRequest `json:"subtitles,omitzero"`
+	ForceRedownload    bool                  `json:"force_redownload,omitempty"`
+	Items              []UnfinishedItem      `json:"items,omitempty"`
+	Timestamp          time.Time             `json:"timestamp"`
+}
+
+type UnfinishedItem struct {
+	URL             string            `json:"url"`
+	Video           types.VideoItem   `json:"video"`
+	OutputDir       string            `json:"output_dir,omitempty"`
+	Status          types.QueueStatus `json:"status"`
+	Error           string            `json:"error,omitempty"`
+	ForceRedownload bool              `json:"force_redownload,omitempty"`
+}
+
+// UnfinishedOption is the saved state of a download option, keyed by its
+// config field.
+type UnfinishedOption struct {
+	Field   string `json:"field"`
+	Enabled bool   `json:"enabled"`
+	Mode    string `json:"mode,omitempty"`
+}
+
+type unfinishedFile struct {
+	Version   int                  `json:"version"`
+	Downloads []UnfinishedDownload `json:"downloads"`
+}
+
+// unfinishedDownloadV1 is a version 1 entry, which kept the videos still to
+// download in parallel slices.
+type unfinishedDownloadV1 struct {
+	UnfinishedDownload
+	URLs       []string          `json:"urls"`
+	Videos     []types.VideoItem `json:"videos"`
+	OutputDir  string            `json:"output_dir"`
+	OutputDirs []string          `json:"output_dirs"`
+}
+
+// IsQueue reports whether the entry is a queue rather than a single video.
+func (d UnfinishedDownload) IsQueue() bool {
+	return strings.HasPrefix(d.URL, queueKeyPrefix)
+}
+
+// SaveOptions records the state of every download option.
+func SaveOptions(options []types.DownloadOption) []UnfinishedOption {
+	saved := make([]UnfinishedOption, len(options))
+	for i, opt := range options {
+		saved[i] = UnfinishedOption{Field: opt.ConfigField, Enabled: opt.Enabled, Mode: opt.Mode}
+	}
+
+	return saved
+}
+
+// RestoreOptions applies saved option states on top of options. Options
+// that weren't saved, e.g. ones added since, keep their current state.
+func RestoreOptions(options []types.DownloadOption, saved []UnfinishedOption) []types.DownloadOption {
+	restored := make([]types.DownloadOption, len(options))
+	copy(restored, options)
+
+	for _, s := range saved {
+		for i := range restored {
+			if restored[i].ConfigField == s.Field {
+				restored[i].Enabled = s.Enabled
+				restored[i].Mode = s.Mode
+			}
+		}
+	}
+
+	return restored
 }
 
 var GetUnfinishedFilePath = func() string {
@@ -39,76 +124,178 @@ var GetUnfinishedFilePath = func() string {
 
 func LoadUnfinished() ([]UnfinishedDownload, error) {
 	path := GetUnfinishedFilePath()
-	data, err := os.ReadFile(path)
+	data, err := store.Read(path)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return []UnfinishedDownload{}, nil
-		}
-
 		return nil, err
 	}
 
-	if len(data) == 0 {
-		return []UnfinishedDownload{}, nil
+	downloads, migrated, err := decodeUnfinished(data)
+	if err == nil && !migrated {
+		return downloads, nil
 	}
 
-	var downloads []UnfinishedDownload
-	if err := json.Unmarshal(data, &downloads); err != nil {
+	if errors.Is(err, ErrUnfinishedVersion) {
+		return nil, err
+	}
+
+	// Migrate or recover the file under the lock.
+	err = updateUnfinished(func(d []UnfinishedDownload) []UnfinishedDownload {
+		downloads = d
+		return d
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return downloads, nil
 }
 
-func SaveUnfinished(downloads []UnfinishedDownload) error {
+// decodeUnfinished parses the unfinished downloads file, reporting whether
+// it was in the version 1 format.
+func decodeUnfinished(data []byte) ([]UnfinishedDownload, bool, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return []UnfinishedDownload{}, false, nil
+	}
+
+	if data[0] == '[' {
+		downloads, err := migrateUnfinishedV1(data)
+		if err != nil {
+			return nil, false, fmt.Errorf("%w: %v", store.ErrCorrupt, err)
+		}
+
+		return downloads, true, nil
+	}
+
+	var file unfinishedFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, false, fmt.Errorf("%w: %v", store.ErrCorrupt, err)
+	}
+
+	if file.Version > UnfinishedSchemaVersion {
+		return nil, false, fmt.Errorf("%w (version %d)", ErrUnfinishedVersion, file.Version)
+	}
+
+	if file.Downloads == nil {
+		return []UnfinishedDownload{}, false, nil
+	}
+
+	return file.Downloads, false, nil
+}
+
+func encodeUnfinished(downloads []UnfinishedDownload) ([]byte, error) {
 	if downloads == nil {
 		downloads = []UnfinishedDownload{}
 	}
 
-	path := GetUnfinishedFilePath()
-	data, err := json.MarshalIndent(downloads, "", "  ")
-	if err != nil {
-		return err
+	return json.MarshalIndent(unfinishedFile{Version: UnfinishedSchemaVersion, Downloads: downloads}, "", "  ")
+}
+
+// updateUnfinished applies fn to the unfinished downloads while holding the
+// file lock, so concurrent instances don't overwrite each other's entries.
+func updateUnfinished(fn func([]UnfinishedDownload) []UnfinishedDownload) error {
+	return store.Update(GetUnfinishedFilePath(), func(data []byte) ([]byte, error) {
+		downloads, _, err := decodeUnfinished(data)
+		if err != nil {
+			return nil, err
+		}
+
+		return encodeUnfinished(fn(downloads))
+	})
+}
+
+func migrateUnfinishedV1(data []byte) ([]UnfinishedDownload, error) {
+	var old []unfinishedDownloadV1
+	if err := json.Unmarshal(data, &old); err != nil {
+		return nil, err
 	}
 
-	return os.WriteFile(path, data, 0o644)
+	downloads := make([]UnfinishedDownload, len(old))
+	for i, d := range old {
+		downloads[i] = d.migrate()
+	}
+
+	return downloads, nil
 }
 
-func AddUnfinished(download UnfinishedDownload) error {
-	if download.URL == "" || download.Title == "" {
-		return ErrInvalidUnfinishedDownload
+// migrate turns the pending URLs, Videos and OutputDirs of a version 1
+// entry into items. Version 1 didn't record the audio flag or options, so
+// those keep their defaults.
+func (d unfinishedDownloadV1) migrate() UnfinishedDownload {
+	download := d.UnfinishedDownload
+
+	videos := d.Videos
+	if len(videos) == 0 {
+		for _, u := range d.URLs {
+			videos = append(videos, types.VideoItem{ID: u, VideoTitle: u})
+		}
 	}
 
-	downloads, err := LoadUnfinished()
-	if err != nil {
-		return err
+	if len(videos) == 0 && !download.IsQueue() {
+		videos = []types.VideoItem{{ID: d.URL, VideoTitle: d.Title}}
 	}
 
-	for i, d := range downloads {
-		if d.URL == download.URL {
-			downloads[i] = download
-			return SaveUnfinished(downloads)
+	for i, v := range videos {
+		url := v.ID
+		if !download.IsQueue() {
+			url = d.URL
+		} else if len(d.URLs) == len(videos) {
+			url = d.URLs[i]
+		}
+
+		outputDir := d.OutputDir
+		if len(d.OutputDirs) == len(videos) {
+			outputDir = d.OutputDirs[i]
 		}
+
+		download.Items = append(download.Items, UnfinishedItem{
+			URL:       url,
+			Video:     v,
+			OutputDir: outputDir,
+			Status:    types.QueueStatusPending,
+		})
 	}
 
-	downloads = append(downloads, download)
-	return SaveUnfinished(downloads)
+	return download
 }
 
-func RemoveUnfinished(url string) error {
-	downloads, err := LoadUnfinished()
+func SaveUnfinished(downloads []UnfinishedDownload) error {
+	data, err := encodeUnfinished(downloads)
 	if err != nil {
 		return err
 	}
 
-	var newDownloads []UnfinishedDownload
-	for _, d := range downloads {
-		if d.URL != url {
-			newDownloads = append(newDownloads, d)
-		}
+	return store.Write(GetUnfinishedFilePath(), data)
+}
+
+func AddUnfinished(download UnfinishedDownload) error {
+	if download.URL == "" || download.Title == "" {
+		return ErrInvalidUnfinishedDownload
 	}
 
-	return SaveUnfinished(newDownloads)
+	return updateUnfinished(func(downloads []UnfinishedDownload) []UnfinishedDownload {
+		for i, d := range downloads {
+			if d.URL == download.URL {
+				downloads[i] = download
+				return downloads
+			}
+		}
+
+		return append(downloads, download)
+	})
+}
+
+func RemoveUnfinished(url string) error {
+	return updateUnfinished(func(downloads []UnfinishedDownload) []UnfinishedDownload {
+		var newDownloads []UnfinishedDownload
+		for _, d := range downloads {
+			if d.URL != url {
+				newDownloads = append(newDownloads, d)
+			}
+		}
+
+		return newDownloads
+	})
 }
 
 func GetUnfinishedByURL(url string) *UnfinishedDownload {
@@ -131,29 +318,27 @@ func AddUnfinishedBatch(downloads []UnfinishedDownload) error {
 		return nil
 	}
 
-	existing, err := LoadUnfinished()
-	if err != nil {
-		return err
-	}
-
-	existingMap := make(map[string]int)
-	for i, d := range existing {
-		existingMap[d.URL] = i
-	}
-
-	for _, d := range downloads {
-		if d.URL == "" || d.Title == "" {
-			continue
+	return updateUnfinished(func(existing []UnfinishedDownload) []UnfinishedDownload {
+		existingMap := make(map[string]int)
+		for i, d := range existing {
+			existingMap[d.URL] = i
 		}
 
-		if idx, exists := existingMap[d.URL]; exists {
-			existing[idx] = d
-		} else {
-			existing = append(existing, d)
+		for _, d := range downloads {
+			if d.URL == "" || d.Title == "" {
+				continue
+			}
+
+			if idx, exists := existingMap[d.URL]; exists {
+				existing[idx] = d
+			} else {
+				existingMap[d.URL] = len(existing)
+				existing = append(existing, d)
+			}
 		}
-	}
 
-	return SaveUnfinished(existing)
+		return existing
+	})
 }
 
 func RemoveUnfinishedBatch(urls []string) error {
@@ -161,26 +346,25 @@ func RemoveUnfinishedBatch(urls []string) error {
 		return nil
 	}
 
-	downloads, err := LoadUnfinished()
-	if err != nil {
-		return err
-	}
-
 	urlSet := make(map[string]bool)
 	for _, url := range urls {
 		urlSet[url] = true
 	}
 
-	var newDownloads []UnfinishedDownload
-	for _, d := range downloads {
-		if !urlSet[d.URL] {
-			newDownloads = append(newDownloads, d)
+	return updateUnfinished(func(downloads []UnfinishedDownload) []UnfinishedDownload {
+		var newDownloads []UnfinishedDownload
+		for _, d := range downloads {
+			if !urlSet[d.URL] {
+				newDownloads = append(newDownloads, d)
+			}
 		}
-	}
 
-	return SaveUnfinished(newDownloads)
+		return newDownloads
+	})
 }
 
+const queueKeyPrefix = "queue:"
+
 func QueueUnfinishedKey(query string) string {
-	return "queue:" + strings.TrimSpace(query)
+	return queueKeyPrefix + strings.TrimSpace(query)
 }
@@ -1,10 +1,17 @@
 package utils
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/xdagiz/xytz/internal/types"
 )
 
 func TestAddUnfinishedValidation(t *testing.T) {
@@ -407,3 +414,163 @@ func TestGetUnfinishedByURL(t *testing.T) {
 		}
 	})
 }
+
+func TestLoadUnfinishedMigratesVersion1(t *testing.T) {
+	unfinishedPath := filepath.Join(t.TempDir(), "unfinished.json")
+	original := GetUnfinishedFilePath
+	GetUnfinishedFilePath = func() string { return unfinishedPath }
+	t.Cleanup(func() { GetUnfinishedFilePath = original })
+
+	content := `[
+		{
+			"url": "queue:lofi",
+			"title": "lofi",
+			"format_id": "140",
+			"urls": ["u1", "u2"],
+			"videos": [{"ID": "id1", "VideoTitle": "one"}, {"ID": "id2", "VideoTitle": "two"}],
+			"output_dirs": ["/music/one", "/music/two"],
+			"timestamp": "2024-01-01T00:00:00Z"
+		},
+		{
+			"url": "https://example.com/video",
+			"title": "Video",
+			"format_id": "best",
+			"output_dir": "/videos",
+			"timestamp": "2024-01-01T00:00:00Z"
+		}
+	]`
+	if err := os.WriteFile(unfinishedPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write unfinished file: %v", err)
+	}
+
+	downloads, err := LoadUnfinished()
+	if err != nil {
+		t.Fatalf("LoadUnfinished() error = %v", err)
+	}
+	if len(downloads) != 2 {
+		t.Fatalf("LoadUnfinished() len = %d, want 2", len(downloads))
+	}
+
+	queue := downloads[0]
+	if !queue.IsQueue() || len(queue.Items) != 2 {
+		t.Fatalf("queue = %+v, want a queue with two items", queue)
+	}
+	want := UnfinishedItem{URL: "u2", Video: types.VideoItem{ID: "id2", VideoTitle: "two"}, OutputDir: "/music/two", Status: types.QueueStatusPending}
+	if !reflect.DeepEqual(queue.Items[1], want) {
+		t.Fatalf("queue.Items[1] = %+v, want %+v", queue.Items[1], want)
+	}
+
+	single := downloads[1]
+	if single.IsQueue() || len(single.Items) != 1 {
+		t.Fatalf("single = %+v, want one item", single)
+	}
+	if single.Items[0].URL != "https://example.com/video" || single.Items[0].OutputDir != "/videos" || single.Items[0].Video.VideoTitle != "Video" {
+		t.Fatalf("single.Items[0] = %+v", single.Items[0])
+	}
+
+	data, err := os.ReadFile(unfinishedPath)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	var file unfinishedFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		t.Fatalf("migrated file is not a version 2 document: %v", err)
+	}
+	if file.Version != UnfinishedSchemaVersion || len(file.Downloads) != 2 {
+		t.Fatalf("migrated file = version %d with %d downloads, want version %d with 2", file.Version, len(file.Downloads), UnfinishedSchemaVersion)
+	}
+}
+
+func TestLoadUnfinishedRejectsNewerVersion(t *testing.T) {
+	unfinishedPath := filepath.Join(t.TempDir(), "unfinished.json")
+	original := GetUnfinishedFilePath
+	GetUnfinishedFilePath = func() string { return unfinishedPath }
+	t.Cleanup(func() { GetUnfinishedFilePath = original })
+
+	if err := os.WriteFile(unfinishedPath, []byte(`{"version": 99, "downloads": []}`), 0o644); err != nil {
+		t.Fatalf("Failed to write unfinished file: %v", err)
+	}
+
+	if _, err := LoadUnfinished(); !errors.Is(err, ErrUnfinishedVersion) {
+		t.Fatalf("LoadUnfinished() error = %v, want ErrUnfinishedVersion", err)
+	}
+}
+
+func TestSaveAndRestoreOptions(t *testing.T) {
+	options := []types.DownloadOption{
+		{Name: "Embed Subtitles", ConfigField: "EmbedSubtitles"},
+		{Name: "SponsorBlock", ConfigField: "SponsorBlockMode", Modes: []string{"mark", "remove"}},
+		{Name: "New Option", ConfigField: "NewOption", Enabled: true},
+	}
+	saved := SaveOptions([]types.DownloadOption{
+		{ConfigField: "EmbedSubtitles", Enabled: true},
+		{ConfigField: "SponsorBlockMode", Enabled: true, Mode: "remove"},
+	})
+
+	restored := RestoreOptions(options, saved)
+	if !restored[0].Enabled {
+		t.Fatalf("EmbedSubtitles not restored: %+v", restored[0])
+	}
+	if !restored[1].Enabled || restored[1].Mode != "remove" {
+		t.Fatalf("SponsorBlock not restored: %+v", restored[1])
+	}
+	if !restored[2].Enabled {
+		t.Fatalf("unsaved option lost its current state: %+v", restored[2])
+	}
+	if options[0].Enabled {
+		t.Fatalf("RestoreOptions modified its input")
+	}
+}
+
+func TestAddUnfinishedConcurrentlyKeepsEveryEntry(t *testing.T) {
+	setupUnfinishedFilePath(t)
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			url := fmt.Sprintf("https://example.com/video%d", i)
+			if err := AddUnfinished(UnfinishedDownload{URL: url, Title: url}); err != nil {
+				t.Errorf("AddUnfinished() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	downloads, err := LoadUnfinished()
+	if err != nil {
+		t.Fatalf("LoadUnfinished() error = %v", err)
+	}
+	if len(downloads) != 10 {
+		t.Errorf("LoadUnfinished() length = %d, want 10", len(downloads))
+	}
+}
+
+func TestLoadUnfinishedRecoversFromCorruptFile(t *testing.T) {
+	setupUnfinishedFilePath(t)
+
+	path := GetUnfinishedFilePath()
+	if err := os.WriteFile(path, []byte(`{"version": 2, "downloads": [{"url": "ht`), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	downloads, err := LoadUnfinished()
+	if err != nil {
+		t.Fatalf("LoadUnfinished() error = %v", err)
+	}
+	if len(downloads) != 0 {
+		t.Errorf("LoadUnfinished() = %v, want empty", downloads)
+	}
+
+	if backups, _ := filepath.Glob(path + ".corrupt-*"); len(backups) != 1 {
+		t.Errorf("backups = %v, want one", backups)
+	}
+
+	if err := AddUnfinished(UnfinishedDownload{URL: "https://example.com/video", Title: "Video"}); err != nil {
+		t.Fatalf("AddUnfinished() error = %v", err)
+	}
+	if downloads, _ := LoadUnfinished(); len(downloads) != 1 {
+		t.Errorf("LoadUnfinished() length = %d, want 1", len(downloads))
+	}
+}
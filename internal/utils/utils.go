@@ -20,6 +20,10 @@ func bytesToHuman(bytes float64) string {
 	return fmt.Sprintf("%.2f %s", bytes, suffixes[i])
 }
 
+func FormatBytes(bytes int64) string {
+	return bytesToHuman(float64(bytes))
+}
+
 func FormatDuration(seconds float64) string {
 	hours := int(seconds / 3600)
 	minutes := int((seconds - float64(hours*3600)) / 60)